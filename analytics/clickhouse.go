@@ -0,0 +1,306 @@
+// Package analytics streams delivery lifecycle records into ClickHouse with
+// batched inserts, for reliability trends beyond metrics retention
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// ErrExport is returned when a batch cannot be inserted
+var ErrExport = errors.New("analytics: export failed")
+
+// tablePattern accepts table or database.table identifiers
+var tablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Record is one delivery lifecycle event as stored; payload bodies are never exported
+type Record struct {
+	Time       string `json:"time"` // UTC, ClickHouse DateTime64(3) text
+	MessageID  string `json:"message_id"`
+	Event      string `json:"event"`
+	Stage      string `json:"stage"`
+	Target     string `json:"target"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+	DelayMS    int64  `json:"delay_ms"`
+	BodyBytes  int    `json:"body_bytes"`
+}
+
+// NewRecord converts a lifecycle event into a Record
+func NewRecord(e webhook.DeliveryEvent) Record {
+	r := Record{
+		Time:       e.Time.UTC().Format("2006-01-02 15:04:05.000"),
+		MessageID:  e.MessageID,
+		Event:      e.Event,
+		Stage:      string(e.Stage),
+		Target:     e.Target,
+		Attempt:    e.Attempt,
+		StatusCode: e.StatusCode,
+		DelayMS:    e.Delay.Milliseconds(),
+		BodyBytes:  e.BodySize,
+	}
+	if e.Error != nil {
+		r.Error = e.Error.Error()
+	}
+	return r
+}
+
+// Config holds the exporter configuration
+type Config struct {
+	BatchSize     int           // Records per insert (default: 1000)
+	FlushInterval time.Duration // Longest a record waits for its batch (default: 5s)
+	SampleRate    float64       // Share of messages exported, 0-1, chosen by message ID (default: 1)
+	MaxBuffered   int           // Records held while ClickHouse is unreachable; oldest dropped first (default: 100000)
+	Username      string
+	Password      string
+	HTTPClient    *http.Client
+}
+
+// Option configures a ClickHouse exporter
+type Option func(*Config)
+
+// WithBatchSize sets the records sent per insert
+func WithBatchSize(n int) Option {
+	return func(c *Config) {
+		c.BatchSize = n
+	}
+}
+
+// WithFlushInterval bounds how long a record waits for its batch to fill
+func WithFlushInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.FlushInterval = d
+	}
+}
+
+// WithSampleRate exports only a share of messages. Sampling is by message ID,
+// so a kept message keeps every one of its lifecycle events.
+func WithSampleRate(rate float64) Option {
+	return func(c *Config) {
+		c.SampleRate = rate
+	}
+}
+
+// WithMaxBuffered bounds the records held while inserts fail
+func WithMaxBuffered(n int) Option {
+	return func(c *Config) {
+		c.MaxBuffered = n
+	}
+}
+
+// WithCredentials authenticates inserts as a ClickHouse user
+func WithCredentials(username, password string) Option {
+	return func(c *Config) {
+		c.Username, c.Password = username, password
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client for inserts
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// ClickHouse batches delivery records into a ClickHouse table over its HTTP
+// interface. Register Observe with webhook.Client.ObserveDeliveries and start Run.
+type ClickHouse struct {
+	endpoint string
+	table    string
+	config   Config
+	full     chan struct{} // Signals Run that a batch is ready
+
+	mu      sync.Mutex
+	buf     []Record
+	dropped uint64
+
+	flushMu sync.Mutex // Serializes inserts, keeping batches in order
+}
+
+// NewClickHouse creates an exporter inserting into table through the HTTP
+// interface at endpoint, e.g. http://localhost:8123
+func NewClickHouse(endpoint, table string, opts ...Option) (*ClickHouse, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("analytics: invalid ClickHouse endpoint %q", endpoint)
+	}
+	if !tablePattern.MatchString(table) {
+		return nil, fmt.Errorf("analytics: invalid table name %q", table)
+	}
+
+	cfg := Config{
+		BatchSize:     1000,
+		FlushInterval: 5 * time.Second,
+		SampleRate:    1,
+		MaxBuffered:   100000,
+		HTTPClient:    http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.BatchSize <= 0 || cfg.FlushInterval <= 0 || cfg.MaxBuffered < cfg.BatchSize {
+		return nil, fmt.Errorf("analytics: batch size and flush interval must be positive and within MaxBuffered")
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("analytics: sample rate %v must be between 0 and 1", cfg.SampleRate)
+	}
+
+	return &ClickHouse{endpoint: strings.TrimSuffix(endpoint, "/"), table: table, config: cfg, full: make(chan struct{}, 1)}, nil
+}
+
+// Schema returns a CREATE TABLE statement matching Record
+func (c *ClickHouse) Schema() string {
+	return "CREATE TABLE IF NOT EXISTS " + c.table + ` (
+    time DateTime64(3, 'UTC'),
+    message_id String,
+    event LowCardinality(String),
+    stage LowCardinality(String),
+    target String,
+    attempt UInt16,
+    status_code UInt16,
+    error String,
+    delay_ms Int64,
+    body_bytes UInt32
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(time)
+ORDER BY (event, time)`
+}
+
+// Observe buffers e for export; it never blocks on ClickHouse
+func (c *ClickHouse) Observe(e webhook.DeliveryEvent) {
+	if !c.sampled(e.MessageID) {
+		return
+	}
+	r := NewRecord(e)
+
+	c.mu.Lock()
+	c.buf = append(c.buf, r)
+	c.trim()
+	ready := len(c.buf) >= c.config.BatchSize
+	c.mu.Unlock()
+
+	if ready {
+		select {
+		case c.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// sampled reports whether messageID falls inside the sample
+func (c *ClickHouse) sampled(messageID string) bool {
+	if c.config.SampleRate >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	io.WriteString(h, messageID)
+	return float64(h.Sum32()) < c.config.SampleRate*math.MaxUint32
+}
+
+// trim drops the oldest records beyond MaxBuffered; c.mu must be held
+func (c *ClickHouse) trim() {
+	if over := len(c.buf) - c.config.MaxBuffered; over > 0 {
+		c.buf = c.buf[over:]
+		c.dropped += uint64(over)
+	}
+}
+
+// Dropped returns how many records were discarded because the buffer was full
+func (c *ClickHouse) Dropped() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Run flushes every FlushInterval, or as soon as a batch fills, until ctx is
+// done, then spends up to one more FlushInterval flushing what is left
+func (c *ClickHouse) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			final, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.FlushInterval)
+			c.Flush(final)
+			cancel()
+			return
+		case <-ticker.C:
+		case <-c.full:
+		}
+		c.Flush(ctx)
+	}
+}
+
+// Flush inserts every buffered record in batches. A failed batch goes back to
+// the front of the buffer to be retried by the next flush.
+func (c *ClickHouse) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	for {
+		c.mu.Lock()
+		n := min(len(c.buf), c.config.BatchSize)
+		batch := c.buf[:n:n]
+		c.buf = c.buf[n:]
+		c.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+
+		if err := c.insert(ctx, batch); err != nil {
+			c.mu.Lock()
+			c.buf = append(batch, c.buf...)
+			c.trim()
+			c.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// insert sends one batch as JSONEachRow
+func (c *ClickHouse) insert(ctx context.Context, batch []Record) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range batch {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("%w: %v", ErrExport, err)
+		}
+	}
+
+	q := url.Values{"query": {"INSERT INTO " + c.table + " FORMAT JSONEachRow"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/?"+q.Encode(), &body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExport, err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if c.config.Username != "" {
+		req.Header.Set("X-ClickHouse-User", c.config.Username)
+		req.Header.Set("X-ClickHouse-Key", c.config.Password)
+	}
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExport, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: status %d: %s", ErrExport, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
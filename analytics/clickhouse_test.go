@@ -0,0 +1,178 @@
+package analytics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// clickHouseStub records inserted rows and fails while down is set
+type clickHouseStub struct {
+	mu      sync.Mutex
+	queries []string
+	rows    []Record
+	user    string
+	down    bool
+}
+
+func (s *clickHouseStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		http.Error(w, "Code: 210. Connection refused", http.StatusServiceUnavailable)
+		return
+	}
+	s.queries = append(s.queries, r.URL.Query().Get("query"))
+	s.user = r.Header.Get("X-ClickHouse-User")
+	sc := bufio.NewScanner(r.Body)
+	for sc.Scan() {
+		var rec Record
+		json.Unmarshal(sc.Bytes(), &rec)
+		s.rows = append(s.rows, rec)
+	}
+}
+
+func testEvent(id string, stage webhook.DeliveryStage) webhook.DeliveryEvent {
+	return webhook.DeliveryEvent{
+		Stage:      stage,
+		MessageID:  id,
+		Event:      "order.created",
+		Target:     "https://example.com/hook",
+		Attempt:    2,
+		StatusCode: 503,
+		Error:      errors.New("webhook: server error: status 503"),
+		Delay:      1500 * time.Millisecond,
+		Time:       time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		BodySize:   42,
+	}
+}
+
+func TestClickHouse_Flush(t *testing.T) {
+	stub := &clickHouseStub{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ch, err := NewClickHouse(server.URL+"/", "hookshot.deliveries", WithBatchSize(2), WithCredentials("writer", "pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 3 {
+		ch.Observe(testEvent(fmt.Sprintf("msg_%d", i), webhook.StageRetried))
+	}
+	if err := ch.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stub.queries) != 2 || stub.queries[0] != "INSERT INTO hookshot.deliveries FORMAT JSONEachRow" {
+		t.Errorf("Expected two batched inserts, got %q", stub.queries)
+	}
+	if stub.user != "writer" {
+		t.Errorf("Expected user 'writer', got %q", stub.user)
+	}
+	want := Record{
+		Time: "2024-01-15 10:30:00.000", MessageID: "msg_0", Event: "order.created", Stage: "retried",
+		Target: "https://example.com/hook", Attempt: 2, StatusCode: 503,
+		Error: "webhook: server error: status 503", DelayMS: 1500, BodyBytes: 42,
+	}
+	if len(stub.rows) != 3 || stub.rows[0] != want {
+		t.Errorf("Expected %+v first of 3 rows, got %+v", want, stub.rows)
+	}
+}
+
+func TestClickHouse_RetriesFailedBatches(t *testing.T) {
+	stub := &clickHouseStub{down: true}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ch, _ := NewClickHouse(server.URL, "deliveries", WithBatchSize(2), WithMaxBuffered(3))
+	for i := range 4 {
+		ch.Observe(testEvent(fmt.Sprintf("msg_%d", i), webhook.StageSent))
+	}
+	err := ch.Flush(context.Background())
+	if !errors.Is(err, ErrExport) || !strings.Contains(err.Error(), "Code: 210") {
+		t.Fatalf("Expected ErrExport with the server's message, got %v", err)
+	}
+	if ch.Dropped() != 1 {
+		t.Errorf("Expected the oldest record dropped, got %d", ch.Dropped())
+	}
+
+	stub.down = false
+	if err := ch.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.rows) != 3 || stub.rows[0].MessageID != "msg_1" || stub.rows[2].MessageID != "msg_3" {
+		t.Errorf("Expected the kept records in order, got %+v", stub.rows)
+	}
+}
+
+func TestClickHouse_SampleRate(t *testing.T) {
+	ch, _ := NewClickHouse("http://localhost:8123", "deliveries", WithSampleRate(0.25))
+	kept := 0
+	for i := range 1000 {
+		id := fmt.Sprintf("msg_%d", i)
+		if ch.sampled(id) != ch.sampled(id) {
+			t.Fatal("Expected sampling to be stable per message")
+		}
+		if ch.sampled(id) {
+			kept++
+		}
+	}
+	if kept < 150 || kept > 350 {
+		t.Errorf("Expected about 250 of 1000 messages kept, got %d", kept)
+	}
+}
+
+func TestClickHouse_Run(t *testing.T) {
+	stub := &clickHouseStub{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ch, _ := NewClickHouse(server.URL, "deliveries", WithFlushInterval(time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ch.Run(ctx)
+		close(done)
+	}()
+	ch.Observe(testEvent("msg_1", webhook.StageSent))
+	cancel()
+	<-done
+
+	if len(stub.rows) != 1 {
+		t.Errorf("Expected the final flush on shutdown, got %d rows", len(stub.rows))
+	}
+}
+
+func TestNewClickHouse_Invalid(t *testing.T) {
+	tests := []struct {
+		endpoint, table string
+		opts            []Option
+	}{
+		{"localhost:8123", "deliveries", nil},
+		{"http://localhost:8123", "deliveries; DROP TABLE x", nil},
+		{"http://localhost:8123", "deliveries", []Option{WithSampleRate(2)}},
+		{"http://localhost:8123", "deliveries", []Option{WithBatchSize(10), WithMaxBuffered(5)}},
+	}
+	for _, tt := range tests {
+		if _, err := NewClickHouse(tt.endpoint, tt.table, tt.opts...); err == nil {
+			t.Errorf("Expected an error for %q %q", tt.endpoint, tt.table)
+		}
+	}
+}
+
+func TestClickHouse_Schema(t *testing.T) {
+	ch, _ := NewClickHouse("http://localhost:8123", "hookshot.deliveries")
+	if !strings.HasPrefix(ch.Schema(), "CREATE TABLE IF NOT EXISTS hookshot.deliveries (") {
+		t.Errorf("Unexpected schema %s", ch.Schema())
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// debugSignature recomputes the signatures of a captured delivery and reports
+// what broke verification. Exits non-zero when the delivery does not verify.
+func debugSignature(args []string) error {
+	fs := flag.NewFlagSet("debug-signature", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "whsec_ secret, whsk_ or whpk_ key (default $WEBHOOK_SECRET)")
+	bodyPath := fs.String("body", "", "file holding the raw request body as received, or - for stdin")
+	headersPath := fs.String("headers", "", `file holding the received headers, one "Name: value" per line`)
+	var extra headerFlags
+	fs.Var(&extra, "H", `additional "Name: value" header (repeatable)`)
+	fs.Parse(args)
+
+	if *secret == "" {
+		return errors.New("debug-signature: -secret or WEBHOOK_SECRET is required")
+	}
+	if *bodyPath == "" {
+		return errors.New("debug-signature: -body is required")
+	}
+
+	body, err := readInput(*bodyPath)
+	if err != nil {
+		return fmt.Errorf("debug-signature: reading body: %w", err)
+	}
+
+	header := make(http.Header)
+	if *headersPath != "" {
+		raw, err := readInput(*headersPath)
+		if err != nil {
+			return fmt.Errorf("debug-signature: reading headers: %w", err)
+		}
+		if header, err = parseHeaders(raw); err != nil {
+			return fmt.Errorf("debug-signature: parsing headers: %w", err)
+		}
+	}
+	for _, line := range extra {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("debug-signature: malformed header %q", line)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	d, err := signing.Diagnose(*secret, body, header)
+	if err != nil {
+		return fmt.Errorf("debug-signature: %w", err)
+	}
+
+	if d.MsgID != "" {
+		fmt.Printf("headers:    %s, %s, %s\n", d.Headers.ID, d.Headers.Timestamp, d.Headers.Signature)
+		fmt.Printf("message id: %s\n", d.MsgID)
+		fmt.Printf("timestamp:  %s\n", d.Timestamp)
+		fmt.Printf("body:       %d bytes\n", len(body))
+		for _, s := range d.Received {
+			fmt.Printf("received:   %s\n", s)
+		}
+		for _, s := range d.Expected {
+			fmt.Printf("expected:   %s\n", s)
+		}
+	}
+
+	for _, f := range d.Findings {
+		fmt.Printf("  - %s\n", f)
+	}
+
+	if !d.Verified {
+		return errors.New("debug-signature: signature does not verify")
+	}
+	fmt.Println("signature verifies")
+	return nil
+}
+
+// parseHeaders reads "Name: value" lines, skipping a leading HTTP request or
+// status line so raw captures can be passed as-is
+func parseHeaders(raw []byte) (http.Header, error) {
+	raw = bytes.TrimLeft(raw, "\r\n")
+	if first, rest, _ := bytes.Cut(raw, []byte("\n")); bytes.HasPrefix(first, []byte("HTTP/")) || bytes.Contains(first, []byte(" HTTP/")) {
+		raw = rest
+	}
+	r := textproto.NewReader(bufio.NewReader(io.MultiReader(bytes.NewReader(raw), strings.NewReader("\r\n\r\n"))))
+	h, err := r.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return http.Header(h), nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// headerFlags collects repeated -H flags
+type headerFlags []string
+
+func (h *headerFlags) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerFlags) Set(v string) error {
+	*h = append(*h, v)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// diagnoseReceiver sends diagnostics deliveries to a receiver and prints the
+// integration mistakes they reveal
+func diagnoseReceiver(args []string) error {
+	fs := flag.NewFlagSet("diagnose-receiver", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "whsec_ secret the receiver verifies with (default $WEBHOOK_SECRET)")
+	standard := fs.Bool("standard", false, "send webhook-* headers instead of svix-*")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("diagnose-receiver: expected one receiver URL")
+	}
+	if *secret == "" {
+		return errors.New("diagnose-receiver: -secret or WEBHOOK_SECRET is required")
+	}
+
+	var opts []webhook.Option
+	if *standard {
+		opts = append(opts, webhook.WithStandardWebhooks())
+	}
+	client, err := webhook.NewClient(fs.Arg(0), *secret, opts...)
+	if err != nil {
+		return fmt.Errorf("diagnose-receiver: %w", err)
+	}
+
+	diag, err := client.DiagnoseReceiver(context.Background())
+	if err != nil {
+		return fmt.Errorf("diagnose-receiver: %w", err)
+	}
+	fmt.Print(diag)
+	if !diag.OK() {
+		return errors.New("diagnose-receiver: receiver is misconfigured")
+	}
+	return nil
+}
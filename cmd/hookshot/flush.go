@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// rejectedDir holds spooled sends the receiver refused for good
+const rejectedDir = "rejected"
+
+// flushReport counts the outcome of one flush
+type flushReport struct {
+	Delivered int
+	Kept      int // Still failing; left in the spool
+	Rejected  int // Moved to the rejected subdirectory
+}
+
+// flushSpool retries every spooled send, oldest first. Delivered entries are
+// removed, rejected ones moved aside and the rest updated in place.
+func flushSpool(ctx context.Context, dir string, newClient func(spoolEntry) (*webhook.Client, error), out func(string)) (flushReport, error) {
+	var report flushReport
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return report, err
+	}
+
+	type spooled struct {
+		path  string
+		entry spoolEntry
+	}
+	var entries []spooled
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return report, err
+		}
+		var e spoolEntry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return report, fmt.Errorf("%s: %w", path, err)
+		}
+		entries = append(entries, spooled{path, e})
+	}
+	slices.SortStableFunc(entries, func(a, b spooled) int { return a.entry.SpooledAt.Compare(b.entry.SpooledAt) })
+
+	for _, s := range entries {
+		client, err := newClient(s.entry)
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", s.path, err)
+		}
+		resp := s.entry.deliver(ctx, client)
+		switch {
+		case resp.Success:
+			report.Delivered++
+			out(fmt.Sprintf("delivered %s (%s)", resp.MessageID, s.entry.Event))
+			if err := os.Remove(s.path); err != nil {
+				return report, err
+			}
+		case spoolable(resp):
+			report.Kept++
+			out(fmt.Sprintf("kept %s (%s) after %d attempts: %v", resp.MessageID, s.entry.Event, s.entry.Attempts, resp.Error))
+			if _, err := writeSpool(dir, s.entry); err != nil {
+				return report, err
+			}
+		default:
+			report.Rejected++
+			out(fmt.Sprintf("rejected %s (%s): %v", s.entry.MessageID, s.entry.Event, resp.Error))
+			if _, err := writeSpool(filepath.Join(dir, rejectedDir), s.entry); err != nil {
+				return report, err
+			}
+			if err := os.Remove(s.path); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// flush retries the sends spooled by hookshot send -spool
+func flush(args []string) error {
+	fs := flag.NewFlagSet("flush", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "signing secret (default $WEBHOOK_SECRET)")
+	spool := fs.String("spool", os.Getenv("HOOKSHOT_SPOOL"), "spool directory (default $HOOKSHOT_SPOOL)")
+	retries := fs.Uint64("retries", 3, "attempts per spooled send in this flush")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-attempt HTTP timeout")
+	fs.Parse(args)
+
+	if *secret == "" {
+		return errors.New("flush: -secret or WEBHOOK_SECRET is required")
+	}
+	if *spool == "" {
+		return errors.New("flush: -spool or HOOKSHOT_SPOOL is required")
+	}
+
+	clients := make(map[string]*webhook.Client)
+	newClient := func(e spoolEntry) (*webhook.Client, error) {
+		key := fmt.Sprint(e.Target, " ", e.Standard)
+		if c, ok := clients[key]; ok {
+			return c, nil
+		}
+		c, err := spoolClient(e, *secret, *retries, *timeout)
+		clients[key] = c
+		return c, err
+	}
+	report, err := flushSpool(context.Background(), *spool, newClient, func(line string) { fmt.Println(line) })
+	if err != nil {
+		return fmt.Errorf("flush: %w", err)
+	}
+	fmt.Printf("flushed %s: %d delivered, %d kept, %d rejected\n", *spool, report.Delivered, report.Kept, report.Rejected)
+	if report.Kept > 0 {
+		return fmt.Errorf("flush: %d spooled sends still failing", report.Kept)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// eventAnnotation marks a payload struct, e.g. "// hookshot:event order.created"
+const eventAnnotation = "hookshot:event"
+
+// genEventsFile is written next to the annotated types
+const genEventsFile = "hookshot_events_gen.go"
+
+type eventDef struct {
+	Name string // Event name, e.g. order.created
+	Type string // Payload type, e.g. OrderCreated
+}
+
+// genEvents scans a package directory for annotated payload structs and
+// generates event-name constants and typed send/handle wrappers
+func genEvents(args []string) error {
+	fs := flag.NewFlagSet("gen-events", flag.ExitOnError)
+	out := fs.String("o", genEventsFile, "output file name, relative to the package directory")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("gen-events: expected one package directory")
+	}
+	dir := fs.Arg(0)
+
+	pkg, defs, err := scanEvents(dir)
+	if err != nil {
+		return fmt.Errorf("gen-events: %w", err)
+	}
+	if len(defs) == 0 {
+		return fmt.Errorf("gen-events: no %q annotations in %s", eventAnnotation, dir)
+	}
+
+	src, err := renderEvents(pkg, defs)
+	if err != nil {
+		return fmt.Errorf("gen-events: %w", err)
+	}
+	path := filepath.Join(dir, *out)
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return fmt.Errorf("gen-events: %w", err)
+	}
+	fmt.Printf("wrote %d events to %s\n", len(defs), path)
+	return nil
+}
+
+// scanEvents parses the non-test Go files in dir and collects annotated structs
+func scanEvents(dir string) (string, []eventDef, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != genEventsFile
+	}, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var (
+		name string
+		defs []eventDef
+		seen = make(map[string]string)
+	)
+	for pkgName, pkg := range pkgs {
+		name = pkgName
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					event, ok := annotation(doc)
+					if !ok {
+						continue
+					}
+					if _, isStruct := ts.Type.(*ast.StructType); !isStruct {
+						return "", nil, fmt.Errorf("%s: %s is annotated but is not a struct", fset.Position(ts.Pos()), ts.Name.Name)
+					}
+					if err := validateEventName(event); err != nil {
+						return "", nil, fmt.Errorf("%s: %w", fset.Position(ts.Pos()), err)
+					}
+					if prev, dup := seen[event]; dup {
+						return "", nil, fmt.Errorf("%s: event %q is already declared by %s", fset.Position(ts.Pos()), event, prev)
+					}
+					seen[event] = ts.Name.Name
+					defs = append(defs, eventDef{Name: event, Type: ts.Name.Name})
+				}
+			}
+		}
+	}
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return name, defs, nil
+}
+
+// annotation returns the event name from a "hookshot:event" doc line
+func annotation(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), " "))
+		if rest, ok := strings.CutPrefix(text, eventAnnotation); ok {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
+
+// validateEventName applies the Client's name rules at generation time
+func validateEventName(name string) error {
+	if strings.HasPrefix(name, webhook.ReservedPrefix) {
+		return fmt.Errorf("%w: %q uses the reserved %q prefix", webhook.ErrInvalidEvent, name, webhook.ReservedPrefix)
+	}
+	return webhook.Event{Name: name, Data: struct{}{}}.Validate()
+}
+
+var eventsTemplate = template.Must(template.New("events").Parse(`// Code generated by hookshot gen-events. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// Event names
+const (
+{{- range .Events}}
+	Event{{.Type}} = "{{.Name}}"
+{{- end}}
+)
+{{range .Events}}
+// Send{{.Type}} sends the {{.Name}} event
+func Send{{.Type}}(ctx context.Context, c *webhook.Client, data {{.Type}}, opts ...webhook.SendOption) webhook.Response {
+	return c.Send(ctx, Event{{.Type}}, data, opts...)
+}
+
+// Handle{{.Type}} registers a handler receiving decoded {{.Name}} payloads
+func Handle{{.Type}}(r *receiver.Receiver, fn func(ctx context.Context, e *receiver.Event, data {{.Type}}) error) *receiver.Receiver {
+	return r.On(Event{{.Type}}, func(ctx context.Context, e *receiver.Event) error {
+		var data {{.Type}}
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		return fn(ctx, e, data)
+	})
+}
+{{end}}`))
+
+func renderEvents(pkg string, defs []eventDef) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := eventsTemplate.Execute(&buf, struct {
+		Package string
+		Events  []eventDef
+	}{pkg, defs}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
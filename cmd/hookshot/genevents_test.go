@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "events.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenEvents(t *testing.T) {
+	dir := writePackage(t, `package events
+
+// OrderCreated is sent when an order is placed
+// hookshot:event order.created
+type OrderCreated struct {
+	OrderID string `+"`json:\"order_id\"`"+`
+}
+
+type (
+	// hookshot:event user.signup
+	UserSignup struct {
+		Email string
+	}
+
+	// not an event
+	internal struct{}
+)
+`)
+
+	if err := genEvents([]string{dir}); err != nil {
+		t.Fatalf("genEvents() error = %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(dir, genEventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := string(out)
+	for _, want := range []string{
+		"package events",
+		`EventOrderCreated = "order.created"`,
+		`EventUserSignup   = "user.signup"`,
+		"func SendOrderCreated(ctx context.Context, c *webhook.Client, data OrderCreated, opts ...webhook.SendOption) webhook.Response",
+		"func HandleUserSignup(r *receiver.Receiver, fn func(ctx context.Context, e *receiver.Event, data UserSignup) error) *receiver.Receiver",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "internal") {
+		t.Error("Expected unannotated types to be skipped")
+	}
+
+	// Regenerating ignores the previous output
+	if err := genEvents([]string{dir}); err != nil {
+		t.Errorf("Expected regeneration to succeed, got %v", err)
+	}
+}
+
+func TestGenEvents_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "bad name", src: "package e\n\n// hookshot:event OrderCreated\ntype A struct{}\n"},
+		{name: "reserved", src: "package e\n\n// hookshot:event hookshot.ping\ntype A struct{}\n"},
+		{name: "not a struct", src: "package e\n\n// hookshot:event order.created\ntype A string\n"},
+		{name: "duplicate", src: "package e\n\n// hookshot:event order.created\ntype A struct{}\n\n// hookshot:event order.created\ntype B struct{}\n"},
+		{name: "none", src: "package e\n\ntype A struct{}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := genEvents([]string{writePackage(t, tt.src)}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
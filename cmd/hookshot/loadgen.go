@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// loadConfig shapes the generated traffic
+type loadConfig struct {
+	Rate        float64       // Events started per second
+	Duration    time.Duration // How long to generate
+	Concurrency int           // Max sends in flight; ticks beyond it are skipped
+	MedianSize  int           // Median payload size in bytes
+	P99Size     int           // 99th percentile payload size in bytes
+	Event       string        // Event name sent
+}
+
+// loadReport aggregates the outcome of a load run
+type loadReport struct {
+	Sent      int
+	Succeeded int
+	Failed    int
+	Skipped   int // Ticks dropped because Concurrency sends were in flight
+	Bytes     int64
+	Statuses  map[int]int // Final status per send; 0 for network errors
+	Latencies []time.Duration
+	Elapsed   time.Duration
+}
+
+// sizeSampler draws payload sizes from a log-normal distribution fitted to
+// the median and p99, the usual shape of real event payloads
+func sizeSampler(median, p99 int, rng *rand.Rand) func() int {
+	sigma := 0.0
+	if p99 > median && median > 0 {
+		sigma = math.Log(float64(p99)/float64(median)) / 2.326 // z-score of p99
+	}
+	mu := math.Log(float64(max(median, 1)))
+	return func() int {
+		return max(int(math.Exp(mu+sigma*rng.NormFloat64())), 0)
+	}
+}
+
+// runLoad sends events through client at cfg.Rate for cfg.Duration, without
+// waiting on earlier sends (an open-loop generator), and waits for all to finish
+func runLoad(ctx context.Context, client *webhook.Client, cfg loadConfig, rng *rand.Rand) loadReport {
+	report := loadReport{Statuses: make(map[int]int)}
+	size := sizeSampler(cfg.MedianSize, cfg.P99Size, rng)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, cfg.Concurrency)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	start := time.Now()
+	for seq := 0; ; seq++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			report.Elapsed = time.Since(start)
+			return report
+		case <-ticker.C:
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			report.Skipped++
+			continue
+		}
+		n := size()
+		report.Sent++
+		report.Bytes += int64(n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			began := time.Now()
+			// Sends outlive the generation window so late responses are counted
+			resp := client.Send(context.WithoutCancel(ctx), cfg.Event, map[string]any{"seq": seq, "pad": strings.Repeat("x", n)})
+			latency := time.Since(began)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Latencies = append(report.Latencies, latency)
+			report.Statuses[resp.StatusCode]++
+			if resp.Success {
+				report.Succeeded++
+			} else {
+				report.Failed++
+			}
+		}()
+	}
+}
+
+// percentile returns the p-th percentile (0-100) of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+func (r loadReport) String() string {
+	var b strings.Builder
+	secs := r.Elapsed.Seconds()
+	fmt.Fprintf(&b, "sent        %d in %s (%.1f/s), %d skipped at the concurrency limit\n", r.Sent, r.Elapsed.Round(time.Millisecond), float64(r.Sent)/secs, r.Skipped)
+	fmt.Fprintf(&b, "succeeded   %d (%.1f/s)\n", r.Succeeded, float64(r.Succeeded)/secs)
+	errRate := 0.0
+	if r.Sent > 0 {
+		errRate = 100 * float64(r.Failed) / float64(r.Sent)
+	}
+	fmt.Fprintf(&b, "failed      %d (%.2f%%)\n", r.Failed, errRate)
+	if r.Sent > 0 {
+		fmt.Fprintf(&b, "payload     %d bytes total, %d average\n", r.Bytes, r.Bytes/int64(r.Sent))
+	}
+
+	sorted := slices.Sorted(slices.Values(r.Latencies))
+	fmt.Fprintf(&b, "latency     p50 %s  p90 %s  p99 %s  max %s\n",
+		percentile(sorted, 50).Round(time.Microsecond), percentile(sorted, 90).Round(time.Microsecond),
+		percentile(sorted, 99).Round(time.Microsecond), percentile(sorted, 100).Round(time.Microsecond))
+
+	for _, code := range slices.Sorted(maps.Keys(r.Statuses)) {
+		label := fmt.Sprint(code)
+		if code == 0 {
+			label = "network"
+		}
+		fmt.Fprintf(&b, "status %-7s %d\n", label, r.Statuses[code])
+	}
+	return b.String()
+}
+
+// loadgen drives synthetic traffic at a receiver and prints throughput,
+// latency percentiles and error rates
+func loadgen(args []string) error {
+	fs := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "signing secret (default $WEBHOOK_SECRET)")
+	var cfg loadConfig
+	fs.Float64Var(&cfg.Rate, "rate", 50, "events per second")
+	fs.DurationVar(&cfg.Duration, "duration", 30*time.Second, "how long to generate load")
+	fs.IntVar(&cfg.Concurrency, "concurrency", 64, "max sends in flight")
+	fs.IntVar(&cfg.MedianSize, "size-median", 1024, "median payload size in bytes")
+	fs.IntVar(&cfg.P99Size, "size-p99", 32*1024, "99th percentile payload size in bytes")
+	fs.StringVar(&cfg.Event, "event", "loadgen.event", "event name to send")
+	retries := fs.Uint64("retries", 1, "attempts per event, as for WithMaxRetries")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-attempt HTTP timeout")
+	standard := fs.Bool("standard", false, "send webhook-* headers instead of svix-*")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("loadgen: expected one target URL")
+	}
+	if *secret == "" {
+		return errors.New("loadgen: -secret or WEBHOOK_SECRET is required")
+	}
+	if cfg.Rate <= 0 || cfg.Concurrency <= 0 || cfg.Duration <= 0 {
+		return errors.New("loadgen: -rate, -concurrency and -duration must be positive")
+	}
+
+	// Per-delivery warnings would drown the report
+	quiet := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	opts := []webhook.Option{webhook.WithMaxRetries(*retries), webhook.WithTimeout(*timeout), webhook.WithLogger(quiet)}
+	if *standard {
+		opts = append(opts, webhook.WithStandardWebhooks())
+	}
+	client, err := webhook.NewClient(fs.Arg(0), *secret, opts...)
+	if err != nil {
+		return fmt.Errorf("loadgen: %w", err)
+	}
+
+	fmt.Printf("loadgen: %.0f events/s for %s against %s\n", cfg.Rate, cfg.Duration, fs.Arg(0))
+	fmt.Print(runLoad(context.Background(), client, cfg, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))))
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func TestRunLoad(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1)%4 == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithMaxRetries(1))
+	cfg := loadConfig{Rate: 200, Duration: 250 * time.Millisecond, Concurrency: 8, MedianSize: 256, P99Size: 2048, Event: "loadgen.event"}
+	report := runLoad(context.Background(), client, cfg, rand.New(rand.NewPCG(1, 2)))
+
+	if report.Sent < 20 || report.Succeeded+report.Failed != report.Sent {
+		t.Fatalf("Expected every started send accounted for, got %+v", report)
+	}
+	if report.Statuses[http.StatusBadRequest] != report.Failed || report.Failed == 0 {
+		t.Errorf("Expected the 400s counted as failures, got %v", report.Statuses)
+	}
+	if out := report.String(); !strings.Contains(out, "latency     p50") || !strings.Contains(out, "status 400") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+}
+
+func TestSizeSampler(t *testing.T) {
+	sample := sizeSampler(1000, 20000, rand.New(rand.NewPCG(1, 2)))
+	sizes := make([]int, 10000)
+	for i := range sizes {
+		sizes[i] = sample()
+	}
+	slices.Sort(sizes)
+	if median := sizes[5000]; median < 900 || median > 1100 {
+		t.Errorf("Expected a median near 1000, got %d", median)
+	}
+	if p99 := sizes[9900]; p99 < 15000 || p99 > 26000 {
+		t.Errorf("Expected a p99 near 20000, got %d", p99)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if percentile(sorted, 50) != 5 || percentile(sorted, 99) != 10 || percentile(sorted, 100) != 10 || percentile(nil, 50) != 0 {
+		t.Error("percentile() gave a wrong result")
+	}
+}
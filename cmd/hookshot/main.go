@@ -0,0 +1,61 @@
+// Command hookshot provides operational tooling for Hookshot webhooks
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: hookshot <command> [flags]
+
+commands:
+  debug-signature   explain why a captured delivery fails verification
+  diagnose-receiver detect common verification mistakes in a live receiver
+  flush             retry the sends spooled by send -spool
+  gen-events        generate event constants and typed wrappers from annotated structs
+  loadgen           send synthetic traffic and report throughput and latency
+  proxy             verify webhooks and forward them to an upstream service
+  send              sign and deliver one event, optionally spooling it on failure
+  validate-config   check the sender's environment before it takes traffic
+  validate-endpoint check an endpoint URL's syntax, DNS, SSRF policy and TLS
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "debug-signature":
+		err = debugSignature(os.Args[2:])
+	case "diagnose-receiver":
+		err = diagnoseReceiver(os.Args[2:])
+	case "flush":
+		err = flush(os.Args[2:])
+	case "gen-events":
+		err = genEvents(os.Args[2:])
+	case "loadgen":
+		err = loadgen(os.Args[2:])
+	case "proxy":
+		err = proxy(os.Args[2:])
+	case "send":
+		err = send(os.Args[2:])
+	case "validate-config":
+		err = validateConfig(os.Args[2:])
+	case "validate-endpoint":
+		err = validateEndpoint(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "hookshot: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hookshot: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+)
+
+// proxy runs a verifying reverse proxy in front of an upstream service
+func proxy(args []string) error {
+	fs := flag.NewFlagSet("proxy", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "whsec_ secret or whpk_ key (default $WEBHOOK_SECRET)")
+	listen := fs.String("listen", ":4000", "address to listen on")
+	upstream := fs.String("upstream", "", "URL of the service to forward verified requests to")
+	fs.Parse(args)
+
+	if *secret == "" {
+		return errors.New("proxy: -secret or WEBHOOK_SECRET is required")
+	}
+	target, err := url.Parse(*upstream)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return fmt.Errorf("proxy: -upstream must be an absolute URL, got %q", *upstream)
+	}
+
+	rcv, err := receiver.New(*secret)
+	if err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+
+	log.Printf("🔐 Verifying proxy on %s -> %s", *listen, target)
+	return http.ListenAndServe(*listen, rcv.Proxy(target))
+}
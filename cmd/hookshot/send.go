@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/google/uuid"
+)
+
+// spoolEntry is a send that failed and waits in the spool for flush
+type spoolEntry struct {
+	Target         string          `json:"target"`
+	Event          string          `json:"event"`
+	Data           json.RawMessage `json:"data"`
+	Timestamp      time.Time       `json:"timestamp"`       // Payload timestamp of the original send
+	IdempotencyKey string          `json:"idempotency_key"` // Keeps the message ID stable across flushes
+	Standard       bool            `json:"standard"`        // Sent with webhook-* headers
+	MessageID      string          `json:"message_id"`
+	Attempts       int             `json:"attempts"` // Attempts made so far, across every flush
+	LastError      string          `json:"last_error"`
+	SpooledAt      time.Time       `json:"spooled_at"`
+}
+
+// deliver sends the entry through client and updates its attempt history
+func (e *spoolEntry) deliver(ctx context.Context, client *webhook.Client) webhook.Response {
+	payload := webhook.Payload{Event: e.Event, Timestamp: e.Timestamp, Data: e.Data}
+	resp := client.SendPayload(ctx, payload, webhook.WithIdempotencyKey(e.IdempotencyKey))
+	e.MessageID = resp.MessageID
+	e.Attempts += len(resp.Attempts)
+	if resp.Error != nil {
+		e.LastError = resp.Error.Error()
+	}
+	return resp
+}
+
+// spoolable reports whether a failed send may still succeed when retried;
+// rejections and invalid events never will
+func spoolable(resp webhook.Response) bool {
+	return resp.MessageID != "" && !errors.Is(resp.Error, webhook.ErrClientError) && !errors.Is(resp.Error, webhook.ErrInvalidEvent)
+}
+
+// writeSpool stores e as <message ID>.json in dir, replacing any earlier copy
+func writeSpool(dir string, e spoolEntry) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, e.MessageID+".json")
+	tmp, err := os.CreateTemp(dir, ".spool-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp.Name(), path)
+}
+
+// send signs and delivers one event, spooling it when delivery fails
+func send(args []string) error {
+	fs := flag.NewFlagSet("send", flag.ExitOnError)
+	secret := fs.String("secret", os.Getenv("WEBHOOK_SECRET"), "signing secret (default $WEBHOOK_SECRET)")
+	spool := fs.String("spool", os.Getenv("HOOKSHOT_SPOOL"), "directory keeping failed sends for flush (default $HOOKSHOT_SPOOL)")
+	key := fs.String("idempotency-key", "", "idempotency key; a random one is used when spooling")
+	retries := fs.Uint64("retries", 3, "attempts before giving up, as for WithMaxRetries")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-attempt HTTP timeout")
+	standard := fs.Bool("standard", false, "send webhook-* headers instead of svix-*")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: hookshot send [flags] <target URL> <event> [JSON data, or - for stdin]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 2 || fs.NArg() > 3 {
+		fs.Usage()
+		return errors.New("send: expected a target URL, an event and optional data")
+	}
+	if *secret == "" {
+		return errors.New("send: -secret or WEBHOOK_SECRET is required")
+	}
+	data := json.RawMessage("{}")
+	if fs.NArg() == 3 {
+		data = json.RawMessage(fs.Arg(2))
+		if fs.Arg(2) == "-" {
+			b, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("send: %w", err)
+			}
+			data = b
+		}
+	}
+	if !json.Valid(data) {
+		return errors.New("send: data is not valid JSON")
+	}
+
+	e := spoolEntry{Target: fs.Arg(0), Event: fs.Arg(1), Data: data, Timestamp: time.Now(), IdempotencyKey: *key, Standard: *standard}
+	if e.IdempotencyKey == "" && *spool != "" {
+		e.IdempotencyKey = uuid.NewString()
+	}
+	client, err := spoolClient(e, *secret, *retries, *timeout)
+	if err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	resp := e.deliver(context.Background(), client)
+	if resp.Success {
+		fmt.Printf("delivered %s (status %d, %d attempts)\n", resp.MessageID, resp.StatusCode, len(resp.Attempts))
+		return nil
+	}
+	if *spool == "" || !spoolable(resp) {
+		return fmt.Errorf("send: %w", resp.Error)
+	}
+	e.SpooledAt = time.Now()
+	path, err := writeSpool(*spool, e)
+	if err != nil {
+		return fmt.Errorf("send: %v; spooling failed: %w", resp.Error, err)
+	}
+	fmt.Printf("spooled %s to %s after %d attempts: %v\n", resp.MessageID, path, e.Attempts, resp.Error)
+	return nil
+}
+
+// spoolClient builds a client delivering e
+func spoolClient(e spoolEntry, secret string, retries uint64, timeout time.Duration) (*webhook.Client, error) {
+	opts := []webhook.Option{webhook.WithMaxRetries(retries), webhook.WithTimeout(timeout)}
+	if e.Standard {
+		opts = append(opts, webhook.WithStandardWebhooks())
+	}
+	return webhook.NewClient(e.Target, secret, opts...)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// spoolTarget answers with the current status and records each svix-id
+func spoolTarget(t *testing.T) (*httptest.Server, *atomic.Int32, *[]string) {
+	t.Helper()
+	var status atomic.Int32
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("svix-id"))
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	return server, &status, &ids
+}
+
+// spoolFailedSend delivers one event to an unavailable target and spools it
+func spoolFailedSend(t *testing.T, target string, dir string) spoolEntry {
+	t.Helper()
+	e := spoolEntry{Target: target, Event: "order.created", Data: json.RawMessage(`{"id":1}`), Timestamp: time.Now(), IdempotencyKey: "key-1"}
+	client, _ := spoolClient(e, testSecret, 1, time.Second)
+	resp := e.deliver(context.Background(), client)
+	if resp.Success || !spoolable(resp) {
+		t.Fatalf("Expected a spoolable failure, got %v", resp.Error)
+	}
+	e.SpooledAt = time.Now()
+	if _, err := writeSpool(dir, e); err != nil {
+		t.Fatalf("Failed to spool: %v", err)
+	}
+	return e
+}
+
+func flushWith(t *testing.T, dir string) flushReport {
+	t.Helper()
+	newClient := func(e spoolEntry) (*webhook.Client, error) { return spoolClient(e, testSecret, 1, time.Second) }
+	report, err := flushSpool(context.Background(), dir, newClient, func(string) {})
+	if err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	return report
+}
+
+func TestFlushSpool_Delivers(t *testing.T) {
+	server, status, ids := spoolTarget(t)
+	dir := t.TempDir()
+	status.Store(http.StatusServiceUnavailable)
+	e := spoolFailedSend(t, server.URL, dir)
+
+	status.Store(http.StatusOK)
+	if report := flushWith(t, dir); report.Delivered != 1 || report.Kept != 0 {
+		t.Fatalf("Expected the spooled send delivered, got %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, e.MessageID+".json")); !os.IsNotExist(err) {
+		t.Errorf("Expected the delivered entry removed, got %v", err)
+	}
+	if len(*ids) != 2 || (*ids)[0] != (*ids)[1] {
+		t.Errorf("Expected the flush to keep the message ID, got %v", *ids)
+	}
+}
+
+func TestFlushSpool_KeepsFailing(t *testing.T) {
+	server, status, _ := spoolTarget(t)
+	dir := t.TempDir()
+	status.Store(http.StatusBadGateway)
+	e := spoolFailedSend(t, server.URL, dir)
+
+	if report := flushWith(t, dir); report.Kept != 1 {
+		t.Fatalf("Expected the entry kept, got %+v", report)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, e.MessageID+".json"))
+	var kept spoolEntry
+	json.Unmarshal(b, &kept)
+	if kept.Attempts != 2 || kept.LastError == "" {
+		t.Errorf("Expected the attempt history updated, got %+v", kept)
+	}
+}
+
+func TestFlushSpool_Rejected(t *testing.T) {
+	server, status, _ := spoolTarget(t)
+	dir := t.TempDir()
+	status.Store(http.StatusServiceUnavailable)
+	e := spoolFailedSend(t, server.URL, dir)
+
+	status.Store(http.StatusUnprocessableEntity)
+	if report := flushWith(t, dir); report.Rejected != 1 {
+		t.Fatalf("Expected the entry rejected, got %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, rejectedDir, e.MessageID+".json")); err != nil {
+		t.Errorf("Expected the entry moved aside: %v", err)
+	}
+	if report := flushWith(t, dir); report != (flushReport{}) {
+		t.Errorf("Expected rejected entries left alone, got %+v", report)
+	}
+}
+
+func TestSpoolable(t *testing.T) {
+	server, status, _ := spoolTarget(t)
+	status.Store(http.StatusBadRequest)
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithMaxRetries(1))
+
+	if resp := client.Send(context.Background(), "order.created", nil); spoolable(resp) {
+		t.Error("Expected a rejected send not to be spooled")
+	}
+	if resp := client.Send(context.Background(), "", nil); spoolable(resp) {
+		t.Error("Expected an invalid event not to be spooled")
+	}
+}
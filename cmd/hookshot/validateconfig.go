@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/analytics"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// defaultSenderSecret is the test secret the sender falls back to
+const defaultSenderSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+// configCheck is one line of validate-config output
+type configCheck struct {
+	level  string // ok, warn or FAIL
+	name   string
+	detail string
+}
+
+// validateConfig checks the sender's environment, as read by examples/sender,
+// and prints one line per setting. Endpoint checks run only when asked for,
+// since they need the network.
+func validateConfig(args []string) error {
+	fs := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	endpoints := fs.Bool("endpoints", false, "also run validate-endpoint checks on the target and canary URLs")
+	var policy webhook.EndpointPolicy
+	fs.BoolVar(&policy.AllowPrivate, "allow-private", false, "permit loopback and private endpoint addresses")
+	fs.BoolVar(&policy.RequireHTTPS, "require-https", false, "reject http:// endpoints")
+	fs.BoolVar(&policy.Probe, "probe", false, "send a HEAD request to each endpoint once the other checks pass")
+	fs.Parse(args)
+
+	checks := checkSenderEnv(os.Getenv)
+	if *endpoints {
+		for _, name := range []string{"WEBHOOK_TARGET_URL", "HOOKSHOT_CANARY_URL"} {
+			target := os.Getenv(name)
+			if name == "WEBHOOK_TARGET_URL" && target == "" {
+				target = "http://localhost:4000/webhook"
+			}
+			if target == "" {
+				continue
+			}
+			report := webhook.ValidateEndpoint(context.Background(), target, policy)
+			for _, c := range report.Checks {
+				checks = append(checks, levelCheck(!c.OK, name+" "+c.Name, c.Detail))
+			}
+		}
+	}
+
+	failed := false
+	for _, c := range checks {
+		fmt.Printf("%-4s %-30s %s\n", c.level, c.name, c.detail)
+		failed = failed || c.level == "FAIL"
+	}
+	if failed {
+		return errors.New("validate-config: fix the FAIL lines before starting the server")
+	}
+	return nil
+}
+
+func levelCheck(failed bool, name, detail string) configCheck {
+	if failed {
+		return configCheck{"FAIL", name, detail}
+	}
+	return configCheck{"ok", name, detail}
+}
+
+// checkSenderEnv validates every setting the sender reads, then builds its
+// webhook configuration and reports each problem Config.Validate finds
+func checkSenderEnv(getenv func(string) string) []configCheck {
+	var checks []configCheck
+	fail := func(name, format string, args ...any) {
+		checks = append(checks, configCheck{"FAIL", name, fmt.Sprintf(format, args...)})
+	}
+	warn := func(name, format string, args ...any) {
+		checks = append(checks, configCheck{"warn", name, fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range []string{"PORT", "HOOKSHOT_QUOTA_PER_MINUTE", "HOOKSHOT_QUOTA_PER_DAY", "HOOKSHOT_CANARY_PERCENT"} {
+		if v := getenv(name); v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n < 0 {
+				fail(name, "%q is not a non-negative integer; the sender would silently use its default", v)
+			}
+		}
+	}
+	for _, name := range []string{"HOOKSHOT_DEDUP_WINDOW", "HOOKSHOT_DIGEST_INTERVAL"} {
+		if v := getenv(name); v != "" {
+			if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+				fail(name, "%q is not a positive Go duration such as 30s or 1h", v)
+			}
+		}
+	}
+	for _, name := range []string{"HOOKSHOT_PAYLOAD_METRICS", "HOOKSHOT_EGRESS_ACCOUNTING"} {
+		if v := getenv(name); v != "" && v != "true" && v != "false" {
+			fail(name, "%q is neither true nor false; anything but true leaves it off", v)
+		}
+	}
+	if mode := getenv("WEBHOOK_HEADER_MODE"); mode != "" && mode != "svix" && mode != "standard" {
+		fail("WEBHOOK_HEADER_MODE", "%q must be svix or standard", mode)
+	}
+	for _, name := range []string{"HOOKSHOT_DIGEST_URL", "HOOKSHOT_STATUS_URL"} {
+		if v := getenv(name); v != "" {
+			if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				fail(name, "%q must be an absolute http:// or https:// URL", v)
+			}
+		}
+	}
+	if v := getenv("HOOKSHOT_CLICKHOUSE_URL"); v != "" {
+		table := getenv("HOOKSHOT_CLICKHOUSE_TABLE")
+		if table == "" {
+			table = "hookshot_deliveries"
+		}
+		rate := 1.0
+		if r := getenv("HOOKSHOT_ANALYTICS_SAMPLE_RATE"); r != "" {
+			var err error
+			if rate, err = strconv.ParseFloat(r, 64); err != nil {
+				fail("HOOKSHOT_ANALYTICS_SAMPLE_RATE", "%q is not a number between 0 and 1", r)
+				rate = 1
+			}
+		}
+		if _, err := analytics.NewClickHouse(v, table, analytics.WithSampleRate(rate)); err != nil {
+			fail("HOOKSHOT_CLICKHOUSE_URL", "%s", strings.TrimPrefix(err.Error(), "analytics: "))
+		}
+	}
+
+	apiKeys, adminKeys := splitEnv(getenv("HOOKSHOT_API_KEYS")), splitEnv(getenv("HOOKSHOT_ADMIN_KEYS"))
+	if len(apiKeys) == 0 {
+		warn("HOOKSHOT_API_KEYS", "empty; /v1/events will reject every request")
+	}
+	for _, k := range adminKeys {
+		if slices.Contains(apiKeys, k) {
+			fail("HOOKSHOT_ADMIN_KEYS", "a key is also listed in HOOKSHOT_API_KEYS; give admins their own keys")
+			break
+		}
+	}
+
+	secret := getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		secret = defaultSenderSecret
+		warn("WEBHOOK_SECRET", "unset; the sender signs with the public test secret")
+	}
+	target := getenv("WEBHOOK_TARGET_URL")
+	if target == "" {
+		target = "http://localhost:4000/webhook"
+	}
+	var opts []webhook.Option
+	if getenv("WEBHOOK_HEADER_MODE") == "standard" {
+		opts = append(opts, webhook.WithStandardWebhooks())
+	}
+	if canary := getenv("HOOKSHOT_CANARY_URL"); canary != "" {
+		percent, _ := strconv.Atoi(getenv("HOOKSHOT_CANARY_PERCENT"))
+		opts = append(opts, webhook.WithTrafficSplit(canary, percent))
+	}
+	err := webhook.NewConfig(target, secret, opts...).Validate()
+	if err == nil {
+		checks = append(checks, configCheck{"ok", "webhook config", "target " + target})
+		return checks
+	}
+	for _, problem := range err.(interface{ Unwrap() []error }).Unwrap() {
+		fail("webhook config", "%s", strings.TrimPrefix(problem.Error(), webhook.ErrInvalidConfig.Error()+": "))
+	}
+	return checks
+}
+
+// splitEnv parses a comma-separated environment value
+func splitEnv(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
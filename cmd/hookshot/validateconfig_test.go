@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSenderEnv(t *testing.T) {
+	env := map[string]string{
+		"WEBHOOK_SECRET":           testSecret,
+		"WEBHOOK_TARGET_URL":       "https://example.com/hook",
+		"HOOKSHOT_API_KEYS":        "key-1",
+		"HOOKSHOT_ADMIN_KEYS":      "admin-1",
+		"HOOKSHOT_DIGEST_INTERVAL": "1h",
+		"HOOKSHOT_PAYLOAD_METRICS": "true",
+		"HOOKSHOT_CANARY_URL":      "https://canary.example.com/hook",
+		"HOOKSHOT_CANARY_PERCENT":  "10",
+	}
+	for _, c := range checkSenderEnv(func(k string) string { return env[k] }) {
+		if c.level != "ok" {
+			t.Errorf("Expected a clean config, got %s %s: %s", c.level, c.name, c.detail)
+		}
+	}
+
+	env = map[string]string{
+		"WEBHOOK_SECRET":             "whsec_@@@",
+		"WEBHOOK_TARGET_URL":         "localhost:4000",
+		"HOOKSHOT_API_KEYS":          "key-1",
+		"HOOKSHOT_ADMIN_KEYS":        "key-1",
+		"HOOKSHOT_QUOTA_PER_DAY":     "lots",
+		"HOOKSHOT_DEDUP_WINDOW":      "5",
+		"HOOKSHOT_EGRESS_ACCOUNTING": "yes",
+		"HOOKSHOT_CLICKHOUSE_URL":    "http://clickhouse:8123",
+		"HOOKSHOT_CLICKHOUSE_TABLE":  "bad-table",
+	}
+	failed := map[string]string{}
+	for _, c := range checkSenderEnv(func(k string) string { return env[k] }) {
+		if c.level == "FAIL" {
+			failed[c.name] += c.detail + "; "
+		}
+	}
+	for _, name := range []string{"HOOKSHOT_QUOTA_PER_DAY", "HOOKSHOT_DEDUP_WINDOW", "HOOKSHOT_EGRESS_ACCOUNTING", "HOOKSHOT_CLICKHOUSE_URL", "HOOKSHOT_ADMIN_KEYS"} {
+		if failed[name] == "" {
+			t.Errorf("Expected %s to fail, got %v", name, failed)
+		}
+	}
+	if got := failed["webhook config"]; !strings.Contains(got, "TargetURL:") || !strings.Contains(got, "Secret:") {
+		t.Errorf("Expected TargetURL and Secret problems, got %q", got)
+	}
+}
+
+func TestCheckSenderEnv_Defaults(t *testing.T) {
+	var warned []string
+	for _, c := range checkSenderEnv(func(string) string { return "" }) {
+		if c.level == "FAIL" {
+			t.Errorf("Expected defaults to pass, got %s: %s", c.name, c.detail)
+		}
+		if c.level == "warn" {
+			warned = append(warned, c.name)
+		}
+	}
+	if strings.Join(warned, ",") != "HOOKSHOT_API_KEYS,WEBHOOK_SECRET" {
+		t.Errorf("Expected warnings for the empty key list and test secret, got %v", warned)
+	}
+}
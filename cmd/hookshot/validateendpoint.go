@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// validateEndpoint runs the pre-flight checks against an endpoint URL and
+// prints one line per check
+func validateEndpoint(args []string) error {
+	fs := flag.NewFlagSet("validate-endpoint", flag.ExitOnError)
+	var policy webhook.EndpointPolicy
+	fs.BoolVar(&policy.AllowPrivate, "allow-private", false, "permit loopback and private addresses")
+	fs.BoolVar(&policy.RequireHTTPS, "require-https", false, "reject http:// endpoints")
+	fs.BoolVar(&policy.Probe, "probe", false, "send a HEAD request once the other checks pass")
+	fs.DurationVar(&policy.Timeout, "timeout", 0, "budget for each network check (default 5s)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("validate-endpoint: expected one URL")
+	}
+
+	report := webhook.ValidateEndpoint(context.Background(), fs.Arg(0), policy)
+	for _, c := range report.Checks {
+		status := "ok  "
+		if !c.OK {
+			status = "FAIL"
+		}
+		fmt.Printf("%s %-7s %s\n", status, c.Name, c.Detail)
+	}
+	if err := report.Err(); err != nil {
+		return errors.New("validate-endpoint: endpoint failed validation")
+	}
+	return nil
+}
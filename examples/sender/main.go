@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/analytics"
+	"github.com/sabry-awad97/Hookshot/examples/sender/server"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func main() {
+	// Get configuration from environment (with defaults)
+	secret := getEnv("WEBHOOK_SECRET", "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc=")
+	targetURL := getEnv("WEBHOOK_TARGET_URL", "http://localhost:4000/webhook")
+	apiKeys := splitList(os.Getenv("HOOKSHOT_API_KEYS"))
+	namespaces := splitList(os.Getenv("HOOKSHOT_EVENT_NAMESPACES"))
+
+	opts := []webhook.Option{webhook.WithMaxRetries(3)}
+	if getEnv("WEBHOOK_HEADER_MODE", "svix") == "standard" {
+		opts = append(opts, webhook.WithStandardWebhooks())
+	}
+	if len(namespaces) > 0 {
+		opts = append(opts, webhook.WithNamePolicies(webhook.AllowNamespaces(namespaces...)))
+	}
+	if canaryURL := os.Getenv("HOOKSHOT_CANARY_URL"); canaryURL != "" {
+		opts = append(opts, webhook.WithTrafficSplit(canaryURL, getEnvInt("HOOKSHOT_CANARY_PERCENT", 0)))
+	}
+	if getEnv("HOOKSHOT_PAYLOAD_METRICS", "") == "true" {
+		opts = append(opts, webhook.WithPayloadMetrics())
+	}
+	if getEnv("HOOKSHOT_EGRESS_ACCOUNTING", "") == "true" {
+		opts = append(opts, webhook.WithEgressAccounting())
+	}
+	var evidence *server.EvidenceLog
+	if getEnv("HOOKSHOT_EVIDENCE", "") == "true" {
+		evidence = server.NewEvidenceLog(getEnvInt("HOOKSHOT_EVIDENCE_RETAIN", 0))
+		opts = append(opts, webhook.WithEvidence(evidence.Record))
+	}
+	if window, err := time.ParseDuration(os.Getenv("HOOKSHOT_DEDUP_WINDOW")); err == nil {
+		opts = append(opts, webhook.WithDedupWindow(window))
+	}
+
+	// Create reusable webhook client
+	client, err := webhook.NewClient(targetURL, secret, opts...)
+	if err != nil {
+		log.Fatalf("Failed to create webhook client: %v", err)
+	}
+
+	// Halt outbound deliveries at startup; events are still accepted and held
+	switch disabled := os.Getenv("HOOKSHOT_DISABLE_DELIVERY"); disabled {
+	case "", "false":
+	case "true":
+		client.DisableDelivery()
+	default:
+		client.DisableDelivery(splitList(disabled)...)
+	}
+	go toggleDeliveryOnSignal(client)
+
+	// Pre-establish the connection to the receiver and keep it warm
+	if err := client.WarmUp(context.Background()); err != nil {
+		log.Printf("⚠️  Warm-up failed, first delivery will dial fresh: %v", err)
+	}
+	go client.KeepWarm(context.Background(), 30*time.Second)
+
+	// Stream delivery records into ClickHouse for long-term analysis
+	if chURL := os.Getenv("HOOKSHOT_CLICKHOUSE_URL"); chURL != "" {
+		rate, err := strconv.ParseFloat(getEnv("HOOKSHOT_ANALYTICS_SAMPLE_RATE", "1"), 64)
+		if err != nil {
+			log.Fatalf("Invalid HOOKSHOT_ANALYTICS_SAMPLE_RATE: %v", err)
+		}
+		exporter, err := analytics.NewClickHouse(chURL, getEnv("HOOKSHOT_CLICKHOUSE_TABLE", "hookshot_deliveries"), analytics.WithSampleRate(rate))
+		if err != nil {
+			log.Fatalf("Failed to create ClickHouse exporter: %v", err)
+		}
+		client.ObserveDeliveries(exporter.Observe)
+		go exporter.Run(context.Background())
+	}
+
+	if len(apiKeys) == 0 {
+		log.Printf("⚠️  HOOKSHOT_API_KEYS is empty; /v1/events will reject every request")
+	}
+
+	digestInterval, _ := time.ParseDuration(os.Getenv("HOOKSHOT_DIGEST_INTERVAL"))
+	srv := server.New(client, server.Config{
+		APIKeys:        apiKeys,
+		AdminKeys:      splitList(os.Getenv("HOOKSHOT_ADMIN_KEYS")),
+		DigestURL:      os.Getenv("HOOKSHOT_DIGEST_URL"),
+		DigestInterval: digestInterval,
+		StatusURL:      os.Getenv("HOOKSHOT_STATUS_URL"),
+		Evidence:       evidence,
+		Quota: server.Quota{
+			PerMinute: getEnvInt("HOOKSHOT_QUOTA_PER_MINUTE", 0),
+			PerDay:    getEnvInt("HOOKSHOT_QUOTA_PER_DAY", 0),
+		},
+	})
+
+	go srv.RunDigest(context.Background())
+
+	port := getEnv("PORT", "8080")
+	log.Printf("🚀 Gin + Webhook server running on :%s", port)
+	srv.Run(":" + port)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// splitList parses a comma-separated environment value
+func splitList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyContextKey holds the authenticated API key in the gin context
+const apiKeyContextKey = "hookshot.apiKey"
+
+// apiKeyAuth accepts requests carrying one of keys, or a live key from managed,
+// as a Bearer token or X-API-Key header. With no keys configured every request
+// is rejected. Managed keys are identified by their ID rather than the secret.
+func apiKeyAuth(keys []string, managed *keyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if auth := c.GetHeader("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		if key != "" && validKey(keys, key) {
+			c.Set(apiKeyContextKey, key)
+			c.Next()
+			return
+		}
+		if managed != nil && key != "" {
+			if k, ok := managed.authenticate(key); ok {
+				c.Set(apiKeyContextKey, k.ID)
+				c.Set(managedKeyContextKey, k)
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing API key"})
+	}
+}
+
+// validKey compares against every key in constant time
+func validKey(keys []string, key string) bool {
+	ok := 0
+	for _, k := range keys {
+		ok |= subtle.ConstantTimeCompare([]byte(k), []byte(key))
+	}
+	return ok == 1
+}
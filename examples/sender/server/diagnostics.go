@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// diagnoseReceiver runs the client's receiver diagnostics against its target
+// and returns the report
+func (s *Server) diagnoseReceiver(c *gin.Context) {
+	diag, err := s.client.DiagnoseReceiver(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"target_url": diag.TargetURL,
+		"ok":         diag.OK(),
+		"probes":     diag.Probes,
+		"findings":   diag.Findings,
+		"report":     diag.String(),
+	})
+}
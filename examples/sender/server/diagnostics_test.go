@@ -0,0 +1,39 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiagnoseReceiver(t *testing.T) {
+	// The stub receiver accepts everything, so signatures are evidently not verified
+	srv, received := newTestServer(t, http.StatusOK)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/diagnostics", nil)
+	req.Header.Set("Authorization", "Bearer key-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var out struct {
+		OK       bool     `json:"ok"`
+		Findings []string `json:"findings"`
+		Probes   []struct {
+			Name     string `json:"name"`
+			Accepted bool   `json:"accepted"`
+		} `json:"probes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.OK || len(out.Findings) != 1 {
+		t.Errorf("Expected one finding, got %v", out.Findings)
+	}
+	if len(out.Probes) == 0 || len(out.Probes) != len(*received) {
+		t.Errorf("Expected one probe per delivery, got %d probes and %d deliveries", len(out.Probes), len(*received))
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// digestSample bounds how many failed messages a digest lists per endpoint
+const digestSample = 5
+
+// digest summarizes messages that failed for good after since, grouped by
+// endpoint, or returns "" when there were none
+func (s *Server) digest(since time.Time) string {
+	failed := s.deliveries.latest(func(r deliveryRecord) bool {
+		return r.Stage == webhook.StageFailed && r.Time.After(since)
+	})
+	if len(failed) == 0 {
+		return ""
+	}
+
+	byTarget := make(map[string][]deliveryRecord)
+	for _, r := range failed {
+		byTarget[r.Target] = append(byTarget[r.Target], r)
+	}
+	targets := make([]string, 0, len(byTarget))
+	for t := range byTarget {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hookshot: %d failed deliveries across %d endpoints since %s\n", len(failed), len(targets), since.UTC().Format(time.RFC3339))
+	for _, t := range targets {
+		records := byTarget[t]
+		fmt.Fprintf(&b, "\n%s: %d failed", t, len(records))
+		if link := s.statusLink("/status/endpoint", url.Values{"url": {t}}); link != "" {
+			fmt.Fprintf(&b, " (%s)", link)
+		}
+		b.WriteString("\n")
+		for i, r := range records {
+			if i == digestSample {
+				fmt.Fprintf(&b, "  … and %d more\n", len(records)-digestSample)
+				break
+			}
+			fmt.Fprintf(&b, "  %s %s attempt %d: %s\n", r.MessageID, r.Event, r.Attempt, r.Error)
+		}
+	}
+	if link := s.statusLink("/status/failed", nil); link != "" {
+		fmt.Fprintf(&b, "\nAll failures: %s\n", link)
+	}
+	return b.String()
+}
+
+// statusLink builds a deep link into the status pages, or "" without a StatusURL
+func (s *Server) statusLink(path string, q url.Values) string {
+	if s.config.StatusURL == "" {
+		return ""
+	}
+	u := strings.TrimRight(s.config.StatusURL, "/") + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return u
+}
+
+// RunDigest posts a digest of failed deliveries to DigestURL every
+// DigestInterval until ctx is done. Quiet intervals post nothing. The body is
+// {"text": ...}, as Slack-compatible incoming webhooks expect.
+func (s *Server) RunDigest(ctx context.Context) {
+	if s.config.DigestURL == "" {
+		return
+	}
+	ticker := time.NewTicker(s.config.DigestInterval)
+	defer ticker.Stop()
+
+	since := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if text := s.digest(since); text != "" {
+				if err := postDigest(ctx, s.config.DigestURL, text); err != nil {
+					log.Printf("⚠️  Failed to post delivery digest: %v", err)
+					continue // Keep the window so the next digest covers these failures
+				}
+			}
+			since = now
+		}
+	}
+}
+
+func postDigest(ctx context.Context, target, text string) error {
+	body, _ := json.Marshal(map[string]string{"text": text})
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("digest endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestDigest(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer target.Close()
+
+	posted := make(chan string, 4)
+	chat := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg map[string]string
+		json.Unmarshal(body, &msg)
+		posted <- msg["text"]
+	}))
+	defer chat.Close()
+
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1))
+	srv := New(client, Config{
+		DigestURL:      chat.URL,
+		DigestInterval: 20 * time.Millisecond,
+		StatusURL:      "https://hookshot.example.com/",
+	})
+
+	since := time.Now()
+	if text := srv.digest(since); text != "" {
+		t.Fatalf("Expected no digest without failures, got %q", text)
+	}
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+
+	text := srv.digest(since)
+	for _, want := range []string{
+		"1 failed deliveries across 1 endpoints",
+		resp.MessageID,
+		"https://hookshot.example.com/status/endpoint?url=",
+		"https://hookshot.example.com/status/failed",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected digest to contain %q, got:\n%s", want, text)
+		}
+	}
+	if srv.digest(time.Now()) != "" {
+		t.Error("Expected failures before the window to be left out")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.RunDigest(ctx)
+	time.Sleep(5 * time.Millisecond)
+	client.Send(context.Background(), "order.updated", map[string]any{})
+
+	select {
+	case got := <-posted:
+		if !strings.Contains(got, "order.updated") || strings.Contains(got, resp.MessageID) {
+			t.Errorf("Expected digest of the latest window only, got:\n%s", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a digest to be posted")
+	}
+}
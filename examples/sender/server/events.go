@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventRequest is the body accepted by POST /v1/events
+type eventRequest struct {
+	Event          string `json:"event" binding:"required"`
+	Payload        any    `json:"payload" binding:"required"`
+	IdempotencyKey string `json:"idempotency_key"`
+	OrderingKey    string `json:"ordering_key"`
+	CorrelationID  string `json:"correlation_id"`
+	CausationID    string `json:"causation_id"`
+	Sequence       uint64 `json:"sequence"`
+}
+
+func (s *Server) createEvent(c *gin.Context) {
+	req := c.MustGet(eventRequestContextKey).(*eventRequest)
+	s.countPublished(c)
+
+	event, err := webhook.NewEvent(req.Event).
+		WithData(req.Payload).
+		WithIdemKey(req.IdempotencyKey).
+		WithOrderingKey(req.OrderingKey).
+		WithCorrelationID(req.CorrelationID).
+		WithCausationID(req.CausationID).
+		WithSequence(req.Sequence).
+		Build()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event", "details": err.Error()})
+		return
+	}
+
+	resp := s.client.SendEvent(webhook.ContextWithTenant(c.Request.Context(), tenantFor(c)), event)
+
+	if errors.Is(resp.Error, webhook.ErrInvalidEvent) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event", "details": resp.Error.Error()})
+		return
+	}
+	if errors.Is(resp.Error, webhook.ErrHoldFull) {
+		// Delivery is switched off and the hold is full; the caller keeps the event
+		c.Header("Retry-After", "30")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Delivery disabled and hold full, retry later", "event": req.Event})
+		return
+	}
+	if resp.Duplicate {
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Duplicate suppressed",
+			"event":     req.Event,
+			"msgId":     resp.MessageID,
+			"duplicate": true,
+		})
+		return
+	}
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	s.publish(resp.MessageID, webhook.Payload{
+		Event:         event.Name,
+		Timestamp:     timestamp,
+		Data:          event.Data,
+		CorrelationID: event.CorrelationID,
+		CausationID:   event.CausationID,
+		Sequence:      event.Sequence,
+	})
+
+	if resp.Parked {
+		// Delivery is switched off; the event is held until it is re-enabled
+		c.JSON(http.StatusAccepted, gin.H{
+			"message": "Webhook held",
+			"event":   req.Event,
+			"msgId":   resp.MessageID,
+		})
+		return
+	}
+
+	if !resp.Success {
+		// The event was accepted but the downstream receiver did not take it
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":      resp.Error.Error(),
+			"event":      req.Event,
+			"msgId":      resp.MessageID,
+			"statusCode": resp.StatusCode,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook sent!",
+		"event":   req.Event,
+		"msgId":   resp.MessageID,
+	})
+}
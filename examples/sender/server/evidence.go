@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EvidenceLog keeps the newest sealed attempt snapshots for GET /v1/evidence.
+// It is in memory; regulated deployments should also persist each record
+// from their own webhook.WithEvidence callback.
+type EvidenceLog struct {
+	mu      sync.RWMutex
+	size    int
+	records []webhook.Evidence // Chain order
+}
+
+// NewEvidenceLog keeps the last size records (default: 10000)
+func NewEvidenceLog(size int) *EvidenceLog {
+	if size <= 0 {
+		size = 10000
+	}
+	return &EvidenceLog{size: size}
+}
+
+// Record is passed to webhook.WithEvidence
+func (l *EvidenceLog) Record(e webhook.Evidence) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, e)
+	if len(l.records) > l.size {
+		l.records = slices.Delete(l.records, 0, len(l.records)-l.size)
+	}
+}
+
+// evidenceRecord is one record with its integrity checks
+type evidenceRecord struct {
+	webhook.Evidence
+	Verified bool `json:"verified"` // Hash matches the record
+	Chained  bool `json:"chained"`  // PrevHash matches the retained record before it
+}
+
+// message returns the records of msgID in chain order
+func (l *EvidenceLog) message(msgID string) []evidenceRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []evidenceRecord
+	for i, e := range l.records {
+		if e.MessageID != msgID {
+			continue
+		}
+		// The oldest retained record's predecessor was evicted
+		chained := i == 0 || e.PrevHash == l.records[i-1].Hash
+		out = append(out, evidenceRecord{Evidence: e, Verified: e.Verify(), Chained: chained})
+	}
+	return out
+}
+
+func (s *Server) messageEvidence(c *gin.Context) {
+	if s.config.Evidence == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Evidence mode is off"})
+		return
+	}
+	records := s.config.Evidence.message(c.Param("id"))
+	if len(records) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No evidence for message"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message_id": c.Param("id"), "records": records})
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestEvidence(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	evidence := NewEvidenceLog(0)
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithEvidence(evidence.Record))
+	srv := New(client, Config{APIKeys: []string{"key-1"}, AdminKeys: []string{"admin-1"}, Evidence: evidence})
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	get := func(id, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/evidence/"+id, nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(resp.MessageID, "key-1"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected publishing key to be refused evidence, got %d", rec.Code)
+	}
+	if rec := get("msg_missing", "admin-1"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec := get(resp.MessageID, "admin-1")
+	var body struct {
+		Records []struct {
+			Hash     string `json:"hash"`
+			Body     []byte `json:"body"`
+			Verified bool   `json:"verified"`
+			Chained  bool   `json:"chained"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body.Records) != 1 {
+		t.Fatalf("Expected one evidence record, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if r := body.Records[0]; !r.Verified || !r.Chained || r.Hash == "" || len(r.Body) == 0 {
+		t.Errorf("Expected a verified record with the body, got %+v", r)
+	}
+}
+
+func TestEvidence_Off(t *testing.T) {
+	client, _ := webhook.NewClient("http://primary", testSecret)
+	srv := New(client, Config{AdminKeys: []string{"admin-1"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/evidence/msg_1", nil)
+	req.Header.Set("X-API-Key", "admin-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
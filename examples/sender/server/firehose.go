@@ -0,0 +1,126 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deliveryRecord is the SSE data of one delivery lifecycle event. Target is
+// always in its redacted form, as attempts record it.
+type deliveryRecord struct {
+	Stage      webhook.DeliveryStage `json:"stage"`
+	MessageID  string                `json:"msg_id"`
+	Event      string                `json:"event"`
+	Target     string                `json:"target"`
+	Attempt    int                   `json:"attempt"`
+	StatusCode int                   `json:"status_code,omitempty"`
+	Error      string                `json:"error,omitempty"`
+	Delay      string                `json:"retry_in,omitempty"`
+	BodySize   int                   `json:"body_bytes"`
+	Time       time.Time             `json:"time"`
+}
+
+func newDeliveryRecord(e webhook.DeliveryEvent) deliveryRecord {
+	r := deliveryRecord{
+		Stage:      e.Stage,
+		MessageID:  e.MessageID,
+		Event:      e.Event,
+		Target:     redact.String(e.Target),
+		Attempt:    e.Attempt,
+		StatusCode: e.StatusCode,
+		BodySize:   e.BodySize,
+		Time:       e.Time,
+	}
+	if e.Error != nil {
+		r.Error = e.Error.Error()
+	}
+	if e.Delay > 0 {
+		r.Delay = e.Delay.String()
+	}
+	return r
+}
+
+// firehoseSub is one SSE connection's filters and queue
+type firehoseSub struct {
+	endpoint string   // Exact target URL, redacted; empty matches every endpoint
+	events   []string // Event name patterns, as for /v1/stream
+	send     chan deliveryRecord
+}
+
+// firehose fans the client's delivery lifecycle events out to SSE observers
+type firehose struct {
+	mu   sync.RWMutex
+	subs map[*firehoseSub]struct{}
+}
+
+func newFirehose() *firehose {
+	return &firehose{subs: make(map[*firehoseSub]struct{})}
+}
+
+// publish is registered with webhook.Client.ObserveDeliveries, so it never
+// blocks the send path: slow observers miss events instead
+func (f *firehose) publish(e webhook.DeliveryEvent) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if len(f.subs) == 0 {
+		return
+	}
+	r := newDeliveryRecord(e)
+	for sub := range f.subs {
+		if (sub.endpoint == "" || sub.endpoint == r.Target) && matchEvent(sub.events, e.Event) {
+			select {
+			case sub.send <- r:
+			default:
+			}
+		}
+	}
+}
+
+func (f *firehose) add(sub *firehoseSub) {
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+}
+
+func (f *firehose) remove(sub *firehoseSub) {
+	f.mu.Lock()
+	delete(f.subs, sub)
+	f.mu.Unlock()
+}
+
+// deliveryStream serves delivery lifecycle events as server-sent events,
+// filtered by ?endpoint= and ?events=
+func (s *Server) deliveryStream(c *gin.Context) {
+	events, err := streamEvents(c.Query("events"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := &firehoseSub{endpoint: redact.String(c.Query("endpoint")), events: events, send: make(chan deliveryRecord, 64)}
+	s.firehose.add(sub)
+	defer s.firehose.remove(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(_ io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case r := <-sub.send:
+			c.SSEvent(string(r.Stage), r)
+			return true
+		}
+	})
+}
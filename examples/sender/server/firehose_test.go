@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeliveryStream(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+	target := httptest.NewServer(srv.Handler())
+	defer target.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, target.URL+"/v1/deliveries/stream?ticket="+streamTicket(t, target)+"&events=order.*", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	postEvent(t, target, `{"event":"payment.received","payload":{"id":"p1"}}`)
+	postEvent(t, target, `{"event":"order.created","payload":{"order_id":"1"}}`)
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var stage string
+	var record deliveryRecord
+	for record.MessageID == "" {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("Stream closed before an event arrived")
+			}
+			if v, ok := strings.CutPrefix(line, "event:"); ok {
+				stage = v
+			}
+			if v, ok := strings.CutPrefix(line, "data:"); ok {
+				json.Unmarshal([]byte(v), &record)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a delivery event")
+		}
+	}
+	if stage != "sent" || record.Event != "order.created" || record.StatusCode != http.StatusOK {
+		t.Errorf("Expected a sent order.created record, got %s %+v", stage, record)
+	}
+}
+
+func TestDeliveryStream_Unauthorized(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/deliveries/stream", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
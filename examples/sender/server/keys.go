@@ -0,0 +1,233 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// managedKeyContextKey holds the authenticated *managedKey in the gin context
+const managedKeyContextKey = "hookshot.managedKey"
+
+// APIKey describes a managed publishing key; the secret itself is never kept
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefixes  []string   `json:"prefixes,omitempty"` // Event-name prefixes the key may publish (empty: any)
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	Usage     KeyUsage   `json:"usage"`
+}
+
+// KeyUsage counts what a managed key has done since it was created
+type KeyUsage struct {
+	Published uint64     `json:"published"` // Events accepted for delivery
+	Denied    uint64     `json:"denied"`    // Events refused for falling outside the key's prefixes
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+	Quota     QuotaUsage `json:"quota"`
+}
+
+type managedKey struct {
+	APIKey
+	hash [sha256.Size]byte
+}
+
+// allows reports whether event falls under one of the key's prefixes
+func (k *managedKey) allows(event string) bool {
+	if len(k.Prefixes) == 0 {
+		return true
+	}
+	for _, p := range k.Prefixes {
+		if strings.HasPrefix(event, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyStore holds managed keys by the SHA-256 of their secret
+type keyStore struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	byHash map[[sha256.Size]byte]*managedKey
+	byID   map[string]*managedKey
+}
+
+func newKeyStore() *keyStore {
+	return &keyStore{
+		now:    time.Now,
+		byHash: make(map[[sha256.Size]byte]*managedKey),
+		byID:   make(map[string]*managedKey),
+	}
+}
+
+// create mints a key and returns its record and the one-time plaintext secret
+func (s *keyStore) create(name string, prefixes []string) (APIKey, string) {
+	secret := "hk_" + base64.RawURLEncoding.EncodeToString(randomBytes(32))
+	k := &managedKey{
+		APIKey: APIKey{
+			ID:        "key_" + hex.EncodeToString(randomBytes(8)),
+			Name:      name,
+			Prefixes:  prefixes,
+			CreatedAt: s.now(),
+		},
+		hash: sha256.Sum256([]byte(secret)),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byHash[k.hash] = k
+	s.byID[k.ID] = k
+	return k.APIKey, secret
+}
+
+// authenticate returns the live key matching secret and stamps its last use
+func (s *keyStore) authenticate(secret string) (*managedKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.byHash[sha256.Sum256([]byte(secret))]
+	if !ok || k.RevokedAt != nil {
+		return nil, false
+	}
+	now := s.now()
+	k.Usage.LastUsed = &now
+	return k, true
+}
+
+func (s *keyStore) revoke(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.byID[id]
+	if !ok {
+		return false
+	}
+	if k.RevokedAt == nil {
+		now := s.now()
+		k.RevokedAt = &now
+	}
+	return true
+}
+
+func (s *keyStore) count(k *managedKey, published bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if published {
+		k.Usage.Published++
+	} else {
+		k.Usage.Denied++
+	}
+}
+
+// list returns every key, revoked ones included, oldest first
+func (s *keyStore) list() []APIKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]APIKey, 0, len(s.byID))
+	for _, k := range s.byID {
+		keys = append(keys, k.APIKey)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// APIKeys lists managed publishing keys with their usage
+func (s *Server) APIKeys() []APIKey {
+	keys := s.keys.list()
+	for i := range keys {
+		keys[i].Usage.Quota = s.quotas.usageOf(keys[i].ID)
+	}
+	return keys
+}
+
+// keyRequest is the body accepted by POST /v1/keys; a nil quota uses the server default
+type keyRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Prefixes []string `json:"prefixes"`
+	Quota    *struct {
+		PerMinute int `json:"per_minute"`
+		PerDay    int `json:"per_day"`
+	} `json:"quota"`
+}
+
+func (s *Server) createKey(c *gin.Context) {
+	var req keyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	for _, p := range req.Prefixes {
+		if p == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": "prefixes must not be empty"})
+			return
+		}
+	}
+
+	key, secret := s.keys.create(req.Name, req.Prefixes)
+	if req.Quota != nil {
+		s.quotas.setQuota(key.ID, Quota{PerMinute: req.Quota.PerMinute, PerDay: req.Quota.PerDay})
+	}
+	key.Usage.Quota = s.quotas.usageOf(key.ID)
+	c.JSON(http.StatusCreated, gin.H{"key": secret, "api_key": key})
+}
+
+func (s *Server) listKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"keys": s.APIKeys()})
+}
+
+func (s *Server) revokeKey(c *gin.Context) {
+	if !s.keys.revoke(c.Param("id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown API key"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// eventRequestContextKey holds the *eventRequest parsed by bindEvent
+const eventRequestContextKey = "hookshot.eventRequest"
+
+// bindEvent parses the POST /v1/events body and refuses events outside a
+// managed key's prefixes; static keys are unscoped. It runs ahead of
+// enforceQuota so a refused event costs no quota.
+func (s *Server) bindEvent(c *gin.Context) {
+	var req eventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+	if v, ok := c.Get(managedKeyContextKey); ok {
+		if k := v.(*managedKey); !k.allows(req.Event) {
+			s.keys.count(k, false)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Event not permitted for this API key", "event": req.Event})
+			return
+		}
+	}
+	c.Set(eventRequestContextKey, &req)
+	c.Next()
+}
+
+// countPublished records an admitted event against the caller's managed key
+func (s *Server) countPublished(c *gin.Context) {
+	if v, ok := c.Get(managedKeyContextKey); ok {
+		s.keys.count(v.(*managedKey), true)
+	}
+}
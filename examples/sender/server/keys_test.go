@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestManagedKeys(t *testing.T) {
+	hits := 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer target.Close()
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1))
+	srv := New(client, Config{APIKeys: []string{"key-1"}, AdminKeys: []string{"admin-1"}})
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/v1/keys", "key-1", `{"name":"billing"}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected publishing key to be refused key management, got %d", rec.Code)
+	}
+
+	rec := do(http.MethodPost, "/v1/keys", "admin-1", `{"name":"billing","prefixes":["invoice."],"quota":{"per_minute":2}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Key    string `json:"key"`
+		APIKey APIKey `json:"api_key"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if !strings.HasPrefix(created.Key, "hk_") || created.APIKey.ID == "" {
+		t.Fatalf("Unexpected created key %s", rec.Body.String())
+	}
+
+	if rec := do(http.MethodPost, "/v1/events", created.Key, `{"event":"invoice.paid","payload":{}}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected in-scope event to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/v1/events", created.Key, `{"event":"user.deleted","payload":{}}`); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected out-of-scope event to be forbidden, got %d", rec.Code)
+	}
+	if u := srv.QuotaUsage(created.APIKey.ID); u.Minute != 1 || u.Day != 1 {
+		t.Errorf("Expected out-of-scope event to leave the quota unchanged, got %+v", u)
+	}
+	if rec := do(http.MethodPost, "/v1/events", created.Key, `{"event":"invoice.sent","payload":{}}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected in-scope event to be accepted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/v1/events", created.Key, `{"event":"invoice.void","payload":{}}`); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected key quota to apply, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/v1/split", created.Key, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected managed key to be limited to publishing, got %d", rec.Code)
+	}
+	if hits != 2 {
+		t.Errorf("Expected 2 deliveries, got %d", hits)
+	}
+
+	keys := srv.APIKeys()
+	if len(keys) != 1 {
+		t.Fatalf("Expected 1 key, got %d", len(keys))
+	}
+	usage := keys[0].Usage
+	if usage.Published != 2 || usage.Denied != 1 || usage.LastUsed == nil || usage.Quota.Rejected != 1 || usage.Quota.Quota.PerMinute != 2 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+	if strings.Contains(do(http.MethodGet, "/v1/keys", "admin-1", "").Body.String(), created.Key) {
+		t.Errorf("Expected key listing not to expose the secret")
+	}
+
+	if rec := do(http.MethodDelete, "/v1/keys/"+created.APIKey.ID, "admin-1", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if rec := do(http.MethodPost, "/v1/events", created.Key, `{"event":"invoice.paid","payload":{}}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to be refused, got %d", rec.Code)
+	}
+	if srv.APIKeys()[0].RevokedAt == nil {
+		t.Errorf("Expected revoked key to record RevokedAt")
+	}
+	if rec := do(http.MethodDelete, "/v1/keys/key_missing", "admin-1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
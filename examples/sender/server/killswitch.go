@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deliveryRequest is the body accepted by POST /v1/delivery/disable and
+// /v1/delivery/enable; no targets means every endpoint
+type deliveryRequest struct {
+	Targets []string `json:"targets"`
+}
+
+func (s *Server) deliveryStatus(c *gin.Context) {
+	all, targets := s.client.DeliveryDisabled()
+	if targets == nil {
+		targets = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"disabled": all,
+		"targets":  targets,
+		"held":     s.client.Held(),
+	})
+}
+
+// disableDelivery stops outbound deliveries; events are still accepted and held
+func (s *Server) disableDelivery(c *gin.Context) {
+	var req deliveryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+	s.client.DisableDelivery(req.Targets...)
+	s.deliveryStatus(c)
+}
+
+// enableDelivery resumes deliveries and flushes held ones in the background
+func (s *Server) enableDelivery(c *gin.Context) {
+	var req deliveryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+	results := s.client.EnableDelivery(req.Targets...)
+	go func() {
+		for range results {
+		}
+	}()
+	s.deliveryStatus(c)
+}
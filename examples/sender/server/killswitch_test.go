@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestDeliveryKillSwitch(t *testing.T) {
+	hits := make(chan struct{}, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits <- struct{}{}
+	}))
+	defer target.Close()
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1), webhook.WithMaxHeld(1))
+	srv := New(client, Config{APIKeys: []string{"key-1"}, AdminKeys: []string{"admin-1"}})
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/v1/delivery/disable", "key-1", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected publishing key to be refused the kill switch, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/v1/delivery/disable", "admin-1", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"disabled":true`) {
+		t.Fatalf("Expected delivery disabled, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(http.MethodPost, "/v1/events", "key-1", `{"event":"order.created","payload":{}}`); rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected the event accepted and held, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/v1/delivery", "admin-1", ""); !strings.Contains(rec.Body.String(), `"held":1`) {
+		t.Errorf("Expected one held delivery, got %s", rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/v1/events", "key-1", `{"event":"order.created","payload":{}}`); rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After once the hold is full, got %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case <-hits:
+		t.Fatal("Expected nothing delivered while disabled")
+	default:
+	}
+
+	if rec := do(http.MethodPost, "/v1/delivery/enable", "admin-1", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"disabled":false`) {
+		t.Fatalf("Expected delivery enabled, got %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case <-hits:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the held event delivered after enabling")
+	}
+}
+
+func TestDeliveryKillSwitch_Targets(t *testing.T) {
+	client, _ := webhook.NewClient("http://primary", testSecret)
+	srv := New(client, Config{AdminKeys: []string{"admin-1"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/delivery/disable", strings.NewReader(`{"targets":["http://primary"]}`))
+	req.Header.Set("X-API-Key", "admin-1")
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"targets":["http://primary"]`) {
+		t.Errorf("Expected the endpoint disabled, got %s", rec.Body.String())
+	}
+	if all, targets := client.DeliveryDisabled(); all || len(targets) != 1 {
+		t.Errorf("Expected only the endpoint disabled, got %v %v", all, targets)
+	}
+}
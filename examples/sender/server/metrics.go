@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// payloadMetric is one event type's entry in GET /v1/metrics/payloads
+type payloadMetric struct {
+	Count            uint64  `json:"count"`
+	Bytes            uint64  `json:"bytes"`
+	AverageBytes     float64 `json:"average_bytes"`
+	MaxBytes         int     `json:"max_bytes"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	SerializationMS  float64 `json:"serialization_ms"`
+}
+
+// payloadMetrics reports payload size and serialization cost per event type
+func (s *Server) payloadMetrics(c *gin.Context) {
+	out := make(map[string]payloadMetric)
+	for event, st := range s.client.PayloadStats() {
+		out[event] = payloadMetric{
+			Count:            st.Count,
+			Bytes:            st.Bytes,
+			AverageBytes:     st.AverageBytes(),
+			MaxBytes:         st.MaxBytes,
+			CompressionRatio: st.CompressionRatio(),
+			SerializationMS:  float64(st.Serialization.Microseconds()) / 1000,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"events": out})
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestPayloadMetrics(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithPayloadMetrics())
+	srv := New(client, Config{APIKeys: []string{"key-1"}})
+	client.Send(t.Context(), "order.created", map[string]string{"id": "1"})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/metrics/payloads", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	var body struct {
+		Events map[string]payloadMetric `json:"events"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid response %s: %v", rec.Body.String(), err)
+	}
+	if m := body.Events["order.created"]; m.Count != 1 || m.Bytes == 0 || m.CompressionRatio == 0 {
+		t.Errorf("Unexpected order.created metrics %+v", m)
+	}
+}
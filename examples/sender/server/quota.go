@@ -0,0 +1,140 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Quota caps how many events one API key may publish; zero fields are unlimited
+type Quota struct {
+	PerMinute int
+	PerDay    int
+}
+
+// QuotaUsage is one API key's consumption in the current windows
+type QuotaUsage struct {
+	Quota    Quota  `json:"quota"`
+	Minute   int    `json:"minute"`   // Events admitted in the current minute
+	Day      int    `json:"day"`      // Events admitted in the current UTC day
+	Rejected uint64 `json:"rejected"` // Requests refused with 429 since start
+}
+
+// quotaTracker counts admitted events per key in fixed minute and UTC-day windows
+type quotaTracker struct {
+	quota Quota
+	now   func() time.Time
+
+	mu        sync.Mutex
+	overrides map[string]Quota
+	usage     map[string]*keyUsage
+}
+
+type keyUsage struct {
+	minuteStart, dayStart time.Time
+	minute, day           int
+	rejected              uint64
+}
+
+func newQuotaTracker(quota Quota, overrides map[string]Quota) *quotaTracker {
+	own := make(map[string]Quota, len(overrides))
+	for k, q := range overrides {
+		own[k] = q
+	}
+	return &quotaTracker{
+		quota:     quota,
+		overrides: own,
+		now:       time.Now,
+		usage:     make(map[string]*keyUsage),
+	}
+}
+
+// setQuota overrides the default quota for key
+func (t *quotaTracker) setQuota(key string, q Quota) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.overrides[key] = q
+}
+
+// quotaFor returns key's quota; t.mu must be held
+func (t *quotaTracker) quotaFor(key string) Quota {
+	if q, ok := t.overrides[key]; ok {
+		return q
+	}
+	return t.quota
+}
+
+// current returns the key's usage with expired windows reset; t.mu must be held
+func (t *quotaTracker) current(key string, now time.Time) *keyUsage {
+	u, ok := t.usage[key]
+	if !ok {
+		u = &keyUsage{}
+		t.usage[key] = u
+	}
+	if minute := now.Truncate(time.Minute); !u.minuteStart.Equal(minute) {
+		u.minuteStart, u.minute = minute, 0
+	}
+	if day := now.UTC().Truncate(24 * time.Hour); !u.dayStart.Equal(day) {
+		u.dayStart, u.day = day, 0
+	}
+	return u
+}
+
+// admit counts one event against key, or reports which window is exhausted
+// and how long until it resets
+func (t *quotaTracker) admit(key string) (window string, limit int, retryAfter time.Duration, ok bool) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q := t.quotaFor(key)
+	u := t.current(key, now)
+	switch {
+	case q.PerMinute > 0 && u.minute >= q.PerMinute:
+		u.rejected++
+		return "minute", q.PerMinute, u.minuteStart.Add(time.Minute).Sub(now), false
+	case q.PerDay > 0 && u.day >= q.PerDay:
+		u.rejected++
+		return "day", q.PerDay, u.dayStart.Add(24 * time.Hour).Sub(now), false
+	}
+	u.minute++
+	u.day++
+	return "", 0, 0, true
+}
+
+func (t *quotaTracker) usageOf(key string) QuotaUsage {
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.current(key, now)
+	return QuotaUsage{Quota: t.quotaFor(key), Minute: u.minute, Day: u.day, Rejected: u.rejected}
+}
+
+// QuotaUsage reports an API key's publishing quota and current consumption
+func (s *Server) QuotaUsage(key string) QuotaUsage {
+	return s.quotas.usageOf(key)
+}
+
+// enforceQuota answers 429 with Retry-After once the caller's key exhausts a window
+func (s *Server) enforceQuota(c *gin.Context) {
+	window, limit, retryAfter, ok := s.quotas.admit(c.GetString(apiKeyContextKey))
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.999)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":  "Quota exceeded",
+			"window": window,
+			"limit":  limit,
+		})
+		return
+	}
+	c.Next()
+}
+
+func (s *Server) quotaUsage(c *gin.Context) {
+	c.JSON(http.StatusOK, s.QuotaUsage(c.GetString(apiKeyContextKey)))
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestQuota(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1))
+
+	srv := New(client, Config{
+		APIKeys:   []string{"key-1", "key-2"},
+		Quota:     Quota{PerMinute: 2, PerDay: 3},
+		KeyQuotas: map[string]Quota{"key-2": {}},
+	})
+	now := time.Date(2024, 1, 15, 10, 30, 15, 0, time.UTC)
+	srv.quotas.now = func() time.Time { return now }
+
+	publish := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"order.created","payload":{}}`))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := publish("key-1"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected event %d to be admitted, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec := publish("key-1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "45" {
+		t.Errorf("Expected Retry-After '45', got '%s'", got)
+	}
+	var body map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["window"] != "minute" {
+		t.Errorf("Expected minute window, got %v", body["window"])
+	}
+
+	// Keys with an override are limited independently
+	for i := 0; i < 5; i++ {
+		if rec := publish("key-2"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected unlimited key to be admitted, got %d", rec.Code)
+		}
+	}
+
+	// The next minute admits one more before the daily cap applies
+	now = now.Add(time.Minute)
+	if rec := publish("key-1"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected admission in a new minute, got %d", rec.Code)
+	}
+	if rec := publish("key-1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected daily quota to apply, got %d", rec.Code)
+	}
+
+	usage := srv.QuotaUsage("key-1")
+	if usage.Minute != 1 || usage.Day != 3 || usage.Rejected != 2 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/quota", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	var got QuotaUsage
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if rec.Code != http.StatusOK || got.Day != 3 || got.Quota.PerDay != 3 {
+		t.Errorf("Expected quota usage for caller, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config holds the HTTP server configuration
+type Config struct {
+	APIKeys        []string         // Keys accepted by the versioned trigger API
+	Quota          Quota            // Default per-key publishing quota (zero: unlimited)
+	KeyQuotas      map[string]Quota // Per-key quota overrides
+	AdminKeys      []string         // Keys allowed to manage API keys and the kill switch, and to read evidence and the status pages
+	StreamRate     int              // Events per second pushed to each /v1/stream connection (default: 50)
+	StreamOrigins  []string         // Browser origins allowed to open /v1/stream (default: same host)
+	DigestURL      string           // Chat webhook receiving RunDigest summaries of failed deliveries
+	DigestInterval time.Duration    // Time between digests (default: 1h)
+	StatusURL      string           // Public base URL of this server, for deep links in digests
+	Evidence       *EvidenceLog     // Serves GET /v1/evidence/:id when the client records into it
+}
+
+// Server exposes webhook triggering over HTTP
+type Server struct {
+	client     *webhook.Client
+	config     Config
+	engine     *gin.Engine
+	quotas     *quotaTracker
+	keys       *keyStore
+	stream     *streamHub
+	firehose   *firehose
+	deliveries *deliveryLog
+	tickets    *ticketStore
+	sessions   *ticketStore // Status page logins
+}
+
+// New creates a server that sends webhooks through client
+func New(client *webhook.Client, cfg Config) *Server {
+	if cfg.StreamRate <= 0 {
+		cfg.StreamRate = 50
+	}
+	if cfg.DigestInterval <= 0 {
+		cfg.DigestInterval = time.Hour
+	}
+	s := &Server{
+		client:     client,
+		config:     cfg,
+		engine:     gin.New(),
+		quotas:     newQuotaTracker(cfg.Quota, cfg.KeyQuotas),
+		keys:       newKeyStore(),
+		stream:     newStreamHub(),
+		firehose:   newFirehose(),
+		deliveries: &deliveryLog{},
+		tickets:    newTicketStore(streamTicketTTL),
+		sessions:   newTicketStore(statusSessionTTL),
+	}
+	s.engine.Use(requestLogger(), gin.Recovery())
+	client.ObserveDeliveries(s.firehose.publish)
+	client.ObserveDeliveries(s.deliveries.record)
+	s.routes()
+	return s
+}
+
+// requestLogger is gin's request log with credentials masked in the logged
+// path, so keys or tickets passed in a query string never reach the log
+func requestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"), p.StatusCode, p.Latency, p.ClientIP,
+			p.Method, redact.String(p.Path), p.ErrorMessage)
+	})
+}
+
+// Handler returns the server's HTTP handler
+func (s *Server) Handler() http.Handler {
+	return s.engine
+}
+
+// Run starts serving on addr
+func (s *Server) Run(addr string) error {
+	return s.engine.Run(addr)
+}
+
+func (s *Server) routes() {
+	// Health check
+	s.engine.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	// Trigger default webhook
+	s.engine.POST("/trigger", s.trigger)
+
+	// Versioned ingestion API; managed keys may only publish
+	publish := s.engine.Group("/v1", apiKeyAuth(s.config.APIKeys, s.keys))
+	publish.POST("/events", s.bindEvent, s.enforceQuota, s.createEvent)
+	publish.GET("/quota", s.quotaUsage)
+
+	v1 := s.engine.Group("/v1", apiKeyAuth(s.config.APIKeys, nil))
+	v1.POST("/diagnostics", s.diagnoseReceiver)
+	v1.GET("/split", s.splitStatus)
+	v1.PUT("/split", s.updateSplit)
+	v1.GET("/metrics/payloads", s.payloadMetrics)
+	v1.GET("/usage/egress", s.egressUsage)
+	v1.POST("/stream/ticket", s.issueStreamTicket)
+
+	// Managed publishing keys
+	admin := s.engine.Group("/v1/keys", apiKeyAuth(s.config.AdminKeys, nil))
+	admin.POST("", s.createKey)
+	admin.GET("", s.listKeys)
+	admin.DELETE("/:id", s.revokeKey)
+
+	// Delivery kill switch
+	delivery := s.engine.Group("/v1/delivery", apiKeyAuth(s.config.AdminKeys, nil))
+	delivery.GET("", s.deliveryStatus)
+	delivery.POST("/disable", s.disableDelivery)
+	delivery.POST("/enable", s.enableDelivery)
+
+	// Sealed attempt snapshots for compliance
+	s.engine.GET("/v1/evidence/:id", apiKeyAuth(s.config.AdminKeys, nil), s.messageEvidence)
+
+	// Live event stream for browser and desktop clients
+	s.engine.GET("/v1/stream", s.streamAuth(), s.streamSubscribe)
+
+	// Delivery lifecycle firehose for internal monitors
+	s.engine.GET("/v1/deliveries/stream", s.streamAuth(), s.deliveryStream)
+
+	// Server-rendered operational pages behind a login cookie
+	s.engine.GET("/status/login", s.statusLoginPage)
+	s.engine.POST("/status/login", s.statusLogin)
+	s.engine.POST("/status/logout", s.statusLogout)
+	status := s.engine.Group("/status", s.statusAuth)
+	status.GET("", s.statusOverview)
+	status.GET("/endpoint", s.statusEndpoint)
+	status.GET("/failed", s.statusFailed)
+}
+
+func (s *Server) trigger(c *gin.Context) {
+	payload := webhook.Payload{
+		Event:     "order.created",
+		Timestamp: time.Now(),
+		Data:      map[string]any{"order_id": "12345", "amount": 99.99},
+	}
+	resp := s.client.SendPayload(c.Request.Context(), payload)
+	s.publish(resp.MessageID, payload)
+
+	if resp.Parked {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Webhook held", "msgId": resp.MessageID})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": resp.Error.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook sent!",
+		"msgId":   resp.MessageID,
+	})
+}
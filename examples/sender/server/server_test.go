@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestServer wires a Server to a receiver stub that records inbound headers
+func newTestServer(t *testing.T, status int) (*Server, *[]http.Header) {
+	t.Helper()
+
+	var received []http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Clone())
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(target.Close)
+
+	client, err := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return New(client, Config{APIKeys: []string{"key-1", "key-2"}}), &received
+}
+
+func TestHealth(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestCreateEvent(t *testing.T) {
+	srv, received := newTestServer(t, http.StatusOK)
+
+	body := `{"event":"order.created","payload":{"order_id":"1"},"idempotency_key":"idem-1","ordering_key":"cust-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer key-2")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp["event"] != "order.created" {
+		t.Errorf("Expected event 'order.created', got %v", resp["event"])
+	}
+	if len(*received) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(*received))
+	}
+	if got := (*received)[0].Get("Idempotency-Key"); got != "idem-1" {
+		t.Errorf("Expected Idempotency-Key 'idem-1', got '%s'", got)
+	}
+	if got := (*received)[0].Get("Webhook-Ordering-Key"); got != "cust-1" {
+		t.Errorf("Expected Webhook-Ordering-Key 'cust-1', got '%s'", got)
+	}
+}
+
+func TestCreateEvent_Duplicate(t *testing.T) {
+	hits := 0
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	t.Cleanup(target.Close)
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithDedupWindow(time.Minute))
+	srv := New(client, Config{APIKeys: []string{"key-1"}})
+
+	var msgIDs []any
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"order.created","payload":{"order_id":"1"}}`))
+		req.Header.Set("Authorization", "Bearer key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var resp map[string]any
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		msgIDs = append(msgIDs, resp["msgId"])
+		if len(msgIDs) == 2 && resp["duplicate"] != true {
+			t.Errorf("Expected second response to be marked duplicate, got %v", resp)
+		}
+	}
+	if msgIDs[0] != msgIDs[1] {
+		t.Errorf("Expected duplicate to report original msgId %v, got %v", msgIDs[0], msgIDs[1])
+	}
+	if hits != 1 {
+		t.Errorf("Expected 1 delivery, got %d", hits)
+	}
+}
+
+func TestCreateEvent_Auth(t *testing.T) {
+	srv, received := newTestServer(t, http.StatusOK)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "missing key", want: http.StatusUnauthorized},
+		{name: "wrong key", header: "X-API-Key", value: "nope", want: http.StatusUnauthorized},
+		{name: "x-api-key", header: "X-API-Key", value: "key-1", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"a.b","payload":{}}`))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+
+	if len(*received) != 1 {
+		t.Errorf("Expected only the authenticated request to be delivered, got %d", len(*received))
+	}
+}
+
+func TestCreateEvent_Errors(t *testing.T) {
+	t.Run("missing event", func(t *testing.T) {
+		srv, _ := newTestServer(t, http.StatusOK)
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"payload":{}}`))
+		req.Header.Set("X-API-Key", "key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("invalid event name", func(t *testing.T) {
+		srv, received := newTestServer(t, http.StatusOK)
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"Order Created","payload":{}}`))
+		req.Header.Set("X-API-Key", "key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		if len(*received) != 0 {
+			t.Errorf("Expected no delivery, got %d", len(*received))
+		}
+	})
+
+	t.Run("reserved event name", func(t *testing.T) {
+		srv, received := newTestServer(t, http.StatusOK)
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"hookshot.secret.rotated","payload":{}}`))
+		req.Header.Set("X-API-Key", "key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		if len(*received) != 0 {
+			t.Errorf("Expected no delivery, got %d", len(*received))
+		}
+	})
+
+	t.Run("receiver failure", func(t *testing.T) {
+		srv, _ := newTestServer(t, http.StatusBadRequest)
+		req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"a.b","payload":{}}`))
+		req.Header.Set("X-API-Key", "key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// splitRequest is the body accepted by PUT /v1/split
+type splitRequest struct {
+	Percent *int `json:"percent" binding:"required"`
+}
+
+func (s *Server) splitStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"canary_url": s.client.CanaryURL(),
+		"percent":    s.client.CanaryPercent(),
+	})
+}
+
+// updateSplit promotes (100), rolls back (0) or adjusts the canary share
+func (s *Server) updateSplit(c *gin.Context) {
+	var req splitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := s.client.SetCanaryPercent(*req.Percent); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, webhook.ErrNoCanary) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	s.splitStatus(c)
+}
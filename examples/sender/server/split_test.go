@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestSplit(t *testing.T) {
+	client, _ := webhook.NewClient("http://primary", testSecret, webhook.WithTrafficSplit("http://canary", 10))
+	srv := New(client, Config{APIKeys: []string{"key-1"}})
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"percent":100}`, http.StatusOK},
+		{`{"percent":0}`, http.StatusOK},
+		{`{"percent":150}`, http.StatusBadRequest},
+		{`{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/v1/split", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer key-1")
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.body, tt.want, rec.Code, rec.Body.String())
+		}
+	}
+	if client.CanaryPercent() != 0 {
+		t.Errorf("Expected the canary rolled back to 0%%, got %d%%", client.CanaryPercent())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/split", nil)
+	req.Header.Set("Authorization", "Bearer key-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"canary_url":"http://canary"`) {
+		t.Errorf("Expected split status, got %s", rec.Body.String())
+	}
+}
+
+func TestSplit_NoCanary(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/split", strings.NewReader(`{"percent":50}`))
+	req.Header.Set("Authorization", "Bearer key-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	deliveryLogSize   = 1000 // Lifecycle events kept for the status pages
+	statusSessionTTL  = 12 * time.Hour
+	statusSessionName = "hookshot_session"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var statusTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"when": func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+}).ParseFS(templateFS, "templates/*.html"))
+
+// deliveryLog keeps the latest lifecycle events, oldest first
+type deliveryLog struct {
+	mu      sync.RWMutex
+	records []deliveryRecord
+}
+
+// record is registered with webhook.Client.ObserveDeliveries
+func (l *deliveryLog) record(e webhook.DeliveryEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, newDeliveryRecord(e))
+	if len(l.records) > deliveryLogSize {
+		l.records = slices.Delete(l.records, 0, len(l.records)-deliveryLogSize)
+	}
+}
+
+// latest returns the newest event of each message matching keep, newest first
+func (l *deliveryLog) latest(keep func(deliveryRecord) bool) []deliveryRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	seen := make(map[string]bool)
+	var out []deliveryRecord
+	for i := len(l.records) - 1; i >= 0; i-- {
+		r := l.records[i]
+		if seen[r.MessageID] {
+			continue
+		}
+		seen[r.MessageID] = true
+		if keep(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// history returns every event for target, given in its redacted form, newest first
+func (l *deliveryLog) history(target string) []deliveryRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []deliveryRecord
+	for i := len(l.records) - 1; i >= 0; i-- {
+		if l.records[i].Target == target {
+			out = append(out, l.records[i])
+		}
+	}
+	return out
+}
+
+// endpointSummary is one target's row on the overview page
+type endpointSummary struct {
+	URL       string // Redacted, as delivery records hold it
+	Canary    bool
+	Delivered int
+	Failed    int
+}
+
+func (s *Server) endpointSummaries() []endpointSummary {
+	urls := s.client.Targets()
+	if canary := s.client.CanaryURL(); canary != "" {
+		urls = append(urls, canary)
+	}
+	var out []endpointSummary
+	for _, u := range urls {
+		sum := endpointSummary{URL: redact.String(u), Canary: u == s.client.CanaryURL()}
+		for _, r := range s.deliveries.latest(func(r deliveryRecord) bool { return r.Target == sum.URL }) {
+			switch r.Stage {
+			case webhook.StageSent:
+				sum.Delivered++
+			case webhook.StageFailed:
+				sum.Failed++
+			}
+		}
+		out = append(out, sum)
+	}
+	return out
+}
+
+// renderStatus writes a status page, buffering so template errors become a 500
+func renderStatus(c *gin.Context, code int, name string, data gin.H) {
+	var buf bytes.Buffer
+	if err := statusTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		c.String(http.StatusInternalServerError, "template error: %v", err)
+		return
+	}
+	c.Data(code, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// statusAuth admits requests carrying a live session cookie from statusLogin
+// and sends everyone else to the login page, so the admin key is entered once
+// and never travels in a status URL
+func (s *Server) statusAuth(c *gin.Context) {
+	if session, err := c.Cookie(statusSessionName); err == nil {
+		if key, ok := s.sessions.lookup(session); ok {
+			c.Set(apiKeyContextKey, key)
+			c.Next()
+			return
+		}
+	}
+	c.Redirect(http.StatusSeeOther, "/status/login")
+	c.Abort()
+}
+
+// statusLoginPage asks for an admin key
+func (s *Server) statusLoginPage(c *gin.Context) {
+	renderStatus(c, http.StatusOK, "login", gin.H{"Title": "Log in", "Login": true})
+}
+
+// statusLogin exchanges a posted admin key for an HttpOnly session cookie
+func (s *Server) statusLogin(c *gin.Context) {
+	key := c.PostForm("key")
+	if key == "" || !validKey(s.config.AdminKeys, key) {
+		renderStatus(c, http.StatusUnauthorized, "login", gin.H{"Title": "Log in", "Login": true, "Error": "Invalid admin key"})
+		return
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     statusSessionName,
+		Value:    s.sessions.issue(key),
+		Path:     "/status",
+		MaxAge:   int(statusSessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	c.Redirect(http.StatusSeeOther, "/status")
+}
+
+// statusLogout ends the session and clears its cookie
+func (s *Server) statusLogout(c *gin.Context) {
+	if session, err := c.Cookie(statusSessionName); err == nil {
+		s.sessions.revoke(session)
+	}
+	http.SetCookie(c.Writer, &http.Cookie{Name: statusSessionName, Path: "/status", MaxAge: -1, HttpOnly: true})
+	c.Redirect(http.StatusSeeOther, "/status/login")
+}
+
+// statusOverview lists endpoints and the latest state of recent messages
+func (s *Server) statusOverview(c *gin.Context) {
+	renderStatus(c, http.StatusOK, "overview", gin.H{
+		"Title":         "Deliveries",
+		"Endpoints":     s.endpointSummaries(),
+		"CanaryPercent": s.client.CanaryPercent(),
+		"Deliveries":    s.deliveries.latest(func(deliveryRecord) bool { return true }),
+	})
+}
+
+// statusEndpoint shows every recent lifecycle event for one target
+func (s *Server) statusEndpoint(c *gin.Context) {
+	target := c.Query("url")
+	known := slices.ContainsFunc(s.endpointSummaries(), func(e endpointSummary) bool { return e.URL == target })
+	if target == "" || !known {
+		c.String(http.StatusNotFound, "unknown endpoint")
+		return
+	}
+	renderStatus(c, http.StatusOK, "endpoint", gin.H{
+		"Title":  "Endpoint",
+		"URL":    target,
+		"Events": s.deliveries.history(target),
+	})
+}
+
+// statusFailed lists messages whose latest event is a terminal failure; with
+// no dead-letter queue this is where undeliverable messages surface
+func (s *Server) statusFailed(c *gin.Context) {
+	renderStatus(c, http.StatusOK, "failed", gin.H{
+		"Title": "Failed deliveries",
+		"Deliveries": s.deliveries.latest(func(r deliveryRecord) bool {
+			return r.Stage == webhook.StageFailed
+		}),
+	})
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusLogin posts key to the status login form
+func statusLogin(srv *Server, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/status/login", strings.NewReader(url.Values{"key": {key}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStatusPages(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusBadRequest)
+	srv.config.AdminKeys = []string{"admin-1"}
+	target := srv.client.Targets()[0]
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(`{"event":"order.created","payload":{}}`))
+	req.Header.Set("X-API-Key", "key-1")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	var sent map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &sent)
+	msgID, _ := sent["msgId"].(string)
+	if msgID == "" {
+		t.Fatalf("Expected a message ID, got %s", rec.Body.String())
+	}
+
+	if rec := statusLogin(srv, "wrong"); rec.Code != http.StatusUnauthorized || len(rec.Result().Cookies()) != 0 {
+		t.Fatalf("Expected a bad key refused without a session, got %d", rec.Code)
+	}
+	if rec := statusLogin(srv, "key-1"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected a publishing key refused, got %d", rec.Code)
+	}
+	rec = statusLogin(srv, "admin-1")
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusSeeOther || len(cookies) != 1 || !cookies[0].HttpOnly || cookies[0].Value == "admin-1" {
+		t.Fatalf("Expected an HttpOnly session cookie, got %d with %v", rec.Code, cookies)
+	}
+	session := cookies[0]
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.AddCookie(session)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, path := range []string{
+		"/status",
+		"/status/failed",
+		"/status/endpoint?url=" + url.QueryEscape(target),
+	} {
+		rec := get(path)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, path, rec.Code, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("Expected HTML for %s, got %s", path, ct)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, msgID) || !strings.Contains(body, "order.created") {
+			t.Errorf("Expected %s to list %s", path, msgID)
+		}
+		if strings.Contains(body, "admin-1") {
+			t.Errorf("Expected %s not to carry the key", path)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status?api_key=key-1", nil))
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/status/login" {
+		t.Errorf("Expected status pages to require a session, got %d", rec.Code)
+	}
+	if rec := get("/status/endpoint?url=http://elsewhere"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected unknown endpoint to get %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/status/logout", nil)
+	req.AddCookie(session)
+	srv.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	if rec := get("/status"); rec.Code != http.StatusSeeOther {
+		t.Errorf("Expected the session ended by logout, got %d", rec.Code)
+	}
+}
+
+func TestStatusPages_RedactedTarget(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+	client, _ := webhook.NewClient(receiver.URL+"/hook?api_key=s3cret", testSecret, webhook.WithMaxRetries(1))
+	srv := New(client, Config{AdminKeys: []string{"admin-1"}})
+	resp := client.Send(context.Background(), "order.created", nil)
+
+	session := statusLogin(srv, "admin-1").Result().Cookies()[0]
+	redacted := redact.String(receiver.URL + "/hook?api_key=s3cret")
+	for _, path := range []string{"/status", "/status/endpoint?url=" + url.QueryEscape(redacted)} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.AddCookie(session)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, resp.MessageID) || strings.Contains(body, "s3cret") {
+			t.Errorf("Expected %s to list %s under the redacted endpoint, got %d: %s", path, resp.MessageID, rec.Code, body)
+		}
+	}
+}
+
+func TestRequestLogger_MasksQueryKeys(t *testing.T) {
+	var log bytes.Buffer
+	gin.DefaultWriter = &log
+	defer func() { gin.DefaultWriter = os.Stdout }()
+
+	srv, _ := newTestServer(t, http.StatusOK)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status?api_key=key-1", nil))
+	if strings.Contains(log.String(), "key-1") || !strings.Contains(log.String(), "/status?api_key=") {
+		t.Errorf("Expected the key masked in the request log, got %q", log.String())
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// streamMessage is one event pushed to a /v1/stream subscriber. Body holds the
+// exact signed bytes, so clients verify it like a webhook delivery.
+type streamMessage struct {
+	ID        string `json:"id"`
+	Event     string `json:"event"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+	Body      string `json:"body"`
+}
+
+// subscriber is one WebSocket connection's filter, queue and rate window
+type subscriber struct {
+	events  []string // Exact names or "prefix.*" patterns; empty matches everything
+	send    chan []byte
+	rate    int // Messages per second
+	mu      sync.Mutex
+	window  time.Time
+	sent    int
+	dropped atomic.Uint64
+}
+
+// matches reports whether the subscriber asked for event
+func (sub *subscriber) matches(event string) bool {
+	return matchEvent(sub.events, event)
+}
+
+// matchEvent reports whether event matches one of patterns, or patterns is empty
+func matchEvent(patterns []string, event string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+		if pattern == event {
+			return true
+		}
+	}
+	return false
+}
+
+// offer queues msg unless the connection is over its rate or its queue is
+// full; dropped messages are counted rather than blocking the publisher
+func (sub *subscriber) offer(msg []byte, now time.Time) bool {
+	sub.mu.Lock()
+	if now.Sub(sub.window) >= time.Second {
+		sub.window, sub.sent = now, 0
+	}
+	over := sub.sent >= sub.rate
+	if !over {
+		sub.sent++
+	}
+	sub.mu.Unlock()
+
+	if !over {
+		select {
+		case sub.send <- msg:
+			return true
+		default:
+		}
+	}
+	sub.dropped.Add(1)
+	return false
+}
+
+// streamHub fans published events out to WebSocket subscribers
+type streamHub struct {
+	mu   sync.RWMutex
+	subs map[*subscriber]struct{}
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{subs: make(map[*subscriber]struct{})}
+}
+
+func (h *streamHub) add(sub *subscriber) {
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *streamHub) remove(sub *subscriber) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	h.mu.Unlock()
+}
+
+func (h *streamHub) publish(event string, msg []byte) {
+	now := time.Now()
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subs {
+		if sub.matches(event) {
+			sub.offer(msg, now)
+		}
+	}
+}
+
+// publish signs an accepted event with the client's secret and pushes it to
+// matching subscribers
+func (s *Server) publish(msgID string, payload webhook.Payload) {
+	if msgID == "" {
+		return // Rejected before a message ID was assigned
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	ts := time.Now()
+	msg, err := json.Marshal(streamMessage{
+		ID:        msgID,
+		Event:     payload.Event,
+		Timestamp: ts.Unix(),
+		Signature: s.client.Sign(msgID, ts, body),
+		Body:      string(body),
+	})
+	if err != nil {
+		return
+	}
+	s.stream.publish(payload.Event, msg)
+}
+
+// streamEvents parses the comma-separated events filter
+func streamEvents(raw string) ([]string, error) {
+	var events []string
+	for _, e := range strings.Split(raw, ",") {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+		if i := strings.Index(e, "*"); i >= 0 && (i != len(e)-1 || !strings.HasSuffix(e, ".*")) {
+			return nil, fmt.Errorf("invalid event pattern %q: wildcards must end a prefix, e.g. order.*", e)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true // Not a browser
+	}
+	if len(s.config.StreamOrigins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+	for _, o := range s.config.StreamOrigins {
+		if strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamSubscribe upgrades to a WebSocket and pushes matching events until
+// the client disconnects
+func (s *Server) streamSubscribe(c *gin.Context) {
+	events, err := streamEvents(c.Query("events"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upgrader := websocket.Upgrader{CheckOrigin: s.checkOrigin}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // Upgrade has already written the error response
+	}
+	defer conn.Close()
+
+	sub := &subscriber{events: events, send: make(chan []byte, s.config.StreamRate), rate: s.config.StreamRate}
+	s.stream.add(sub)
+	defer s.stream.remove(sub)
+
+	// Reads only detect the client closing; subscribers send nothing
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case msg := <-sub.send:
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		}
+	}
+}
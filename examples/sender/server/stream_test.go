@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialStream(t *testing.T, target *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(target.URL, "http")+"/v1/stream?"+query, nil)
+	if err != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		t.Fatalf("Dial() error = %v (status %d)", err, status)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// streamTicket fetches a single-use ticket for key-1
+func streamTicket(t *testing.T, target *httptest.Server) string {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, target.URL+"/v1/stream/ticket", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Ticket string `json:"ticket"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body); resp.StatusCode != http.StatusCreated || body.Ticket == "" {
+		t.Fatalf("Expected a stream ticket, got %d", resp.StatusCode)
+	}
+	return body.Ticket
+}
+
+func postEvent(t *testing.T, target *httptest.Server, body string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, target.URL+"/v1/events", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "key-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestStream(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+	target := httptest.NewServer(srv.Handler())
+	defer target.Close()
+
+	conn := dialStream(t, target, "ticket="+streamTicket(t, target)+"&events=order.*")
+	// The subscription registers after the handshake; wait until it is live
+	subscribers := func() int {
+		srv.stream.mu.RLock()
+		defer srv.stream.mu.RUnlock()
+		return len(srv.stream.subs)
+	}
+	for i := 0; i < 100 && subscribers() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	postEvent(t, target, `{"event":"payment.received","payload":{"id":"p1"}}`)
+	postEvent(t, target, `{"event":"order.created","payload":{"order_id":"1"}}`)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg streamMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if msg.Event != "order.created" {
+		t.Errorf("Expected the filtered order.created event, got %s", msg.Event)
+	}
+
+	key, _ := signing.DecodeSecret(testSecret)
+	want := signing.SignV1(key, msg.ID, time.Unix(msg.Timestamp, 0), []byte(msg.Body))
+	if msg.Signature != want {
+		t.Errorf("Expected signature %s, got %s", want, msg.Signature)
+	}
+	var payload struct {
+		Data map[string]string `json:"data"`
+	}
+	if json.Unmarshal([]byte(msg.Body), &payload); payload.Data["order_id"] != "1" {
+		t.Errorf("Expected the event payload in the body, got %s", msg.Body)
+	}
+}
+
+func TestStream_Unauthorized(t *testing.T) {
+	srv, _ := newTestServer(t, http.StatusOK)
+	target := httptest.NewServer(srv.Handler())
+	defer target.Close()
+
+	ws := "ws" + strings.TrimPrefix(target.URL, "http") + "/v1/stream?"
+	for _, query := range []string{"ticket=wrong", "api_key=key-1"} {
+		_, resp, err := websocket.DefaultDialer.Dial(ws+query, nil)
+		if err == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status %d for %s, got %v", http.StatusUnauthorized, query, resp)
+		}
+	}
+
+	// Tickets are single-use
+	ticket := streamTicket(t, target)
+	dialStream(t, target, "ticket="+ticket)
+	_, resp, err := websocket.DefaultDialer.Dial(ws+"ticket="+ticket, nil)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a redeemed ticket refused, got %v", resp)
+	}
+
+	_, resp, err = websocket.DefaultDialer.Dial(ws+"ticket="+streamTicket(t, target)+"&events=*.created", nil)
+	if err == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a bad pattern, got %v", http.StatusBadRequest, resp)
+	}
+}
+
+func TestSubscriber_RateLimit(t *testing.T) {
+	sub := &subscriber{send: make(chan []byte, 10), rate: 2}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		sub.offer([]byte("m"), now)
+	}
+	if len(sub.send) != 2 || sub.dropped.Load() != 1 {
+		t.Errorf("Expected 2 queued and 1 dropped, got %d and %d", len(sub.send), sub.dropped.Load())
+	}
+	if !sub.offer([]byte("m"), now.Add(time.Second)) {
+		t.Error("Expected the next window to accept again")
+	}
+}
+
+func TestTicketStore_Expiry(t *testing.T) {
+	now := time.Now()
+	store := newTicketStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	ticket := store.issue("key-1")
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.redeem(ticket); ok {
+		t.Error("Expected an expired ticket refused")
+	}
+}
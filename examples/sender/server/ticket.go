@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamTicketTTL is how long a stream ticket may wait to be redeemed
+const streamTicketTTL = 30 * time.Second
+
+// ticketStore holds random tickets standing in for an API key where browsers
+// cannot send headers, so the key itself never appears in a URL. Stream
+// tickets are redeemed once; status sessions are looked up until they expire.
+type ticketStore struct {
+	now func() time.Time
+	ttl time.Duration
+
+	mu      sync.Mutex
+	tickets map[string]issuedTicket
+}
+
+type issuedTicket struct {
+	key     string // The authenticated key, as apiKeyContextKey holds it
+	expires time.Time
+}
+
+func newTicketStore(ttl time.Duration) *ticketStore {
+	return &ticketStore{now: time.Now, ttl: ttl, tickets: make(map[string]issuedTicket)}
+}
+
+// issue mints a ticket for key and drops expired ones
+func (s *ticketStore) issue(key string) string {
+	ticket := base64.RawURLEncoding.EncodeToString(randomBytes(32))
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for t, it := range s.tickets {
+		if now.After(it.expires) {
+			delete(s.tickets, t)
+		}
+	}
+	s.tickets[ticket] = issuedTicket{key: key, expires: now.Add(s.ttl)}
+	return ticket
+}
+
+// redeem consumes ticket and returns the key it was issued for
+func (s *ticketStore) redeem(ticket string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.tickets[ticket]
+	if !ok {
+		return "", false
+	}
+	delete(s.tickets, ticket)
+	if s.now().After(it.expires) {
+		return "", false
+	}
+	return it.key, true
+}
+
+// lookup returns the key ticket was issued for, leaving it valid
+func (s *ticketStore) lookup(ticket string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it, ok := s.tickets[ticket]
+	if !ok || s.now().After(it.expires) {
+		return "", false
+	}
+	return it.key, true
+}
+
+// revoke drops ticket
+func (s *ticketStore) revoke(ticket string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tickets, ticket)
+}
+
+// issueStreamTicket answers POST /v1/stream/ticket with a ticket that opens
+// one /v1/stream or /v1/deliveries/stream connection
+func (s *Server) issueStreamTicket(c *gin.Context) {
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket":     s.tickets.issue(c.GetString(apiKeyContextKey)),
+		"expires_in": int(s.tickets.ttl.Seconds()),
+	})
+}
+
+// streamAuth accepts a ?ticket= from issueStreamTicket, for browsers, which
+// cannot set headers on WebSocket and EventSource requests, and otherwise
+// falls back to apiKeyAuth
+func (s *Server) streamAuth() gin.HandlerFunc {
+	keyAuth := apiKeyAuth(s.config.APIKeys, nil)
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" {
+			keyAuth(c)
+			return
+		}
+		key, ok := s.tickets.redeem(ticket)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired stream ticket"})
+			return
+		}
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
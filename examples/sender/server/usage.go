@@ -0,0 +1,55 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tenantFor names the caller in egress exports: managed keys by their ID,
+// static keys by a fingerprint so the secret never appears in a report
+func tenantFor(c *gin.Context) string {
+	if _, ok := c.Get(managedKeyContextKey); ok {
+		return c.GetString(apiKeyContextKey)
+	}
+	sum := sha256.Sum256([]byte(c.GetString(apiKeyContextKey)))
+	return "static_" + hex.EncodeToString(sum[:4])
+}
+
+// egressUsage exports bytes sent per day, tenant and endpoint as JSON, or CSV
+// with ?format=csv. ?from= and ?to= bound the UTC days (YYYY-MM-DD).
+func (s *Server) egressUsage(c *gin.Context) {
+	var bounds [2]time.Time
+	for i, param := range []string{"from", "to"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " date", "details": err.Error()})
+			return
+		}
+		bounds[i] = day
+	}
+	usage := s.client.EgressUsage(bounds[0], bounds[1])
+
+	if c.Query("format") == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="egress.csv"`)
+		c.Status(http.StatusOK)
+		webhook.WriteEgressCSV(c.Writer, usage)
+		return
+	}
+
+	rows := make([]gin.H, len(usage))
+	for i, u := range usage {
+		rows[i] = gin.H{"day": u.Day, "tenant": u.Tenant, "endpoint": u.Endpoint, "attempts": u.Attempts, "bytes": u.Bytes}
+	}
+	c.JSON(http.StatusOK, gin.H{"usage": rows})
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestEgressUsage(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	client, _ := webhook.NewClient(target.URL, testSecret, webhook.WithMaxRetries(1), webhook.WithEgressAccounting())
+	srv := New(client, Config{APIKeys: []string{"key-1"}})
+	_, managed := srv.keys.create("acme", nil)
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+	do(http.MethodPost, "/v1/events", "key-1", `{"event":"order.created","payload":{"id":1}}`)
+	do(http.MethodPost, "/v1/events", managed, `{"event":"order.created","payload":{"id":2}}`)
+	do(http.MethodPost, "/v1/events", managed, `{"event":"order.created","payload":{"id":3}}`)
+
+	rec := do(http.MethodGet, "/v1/usage/egress", "key-1", "")
+	var body struct {
+		Usage []struct {
+			Tenant   string `json:"tenant"`
+			Endpoint string `json:"endpoint"`
+			Attempts uint64 `json:"attempts"`
+			Bytes    uint64 `json:"bytes"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid response %s: %v", rec.Body.String(), err)
+	}
+	attempts := map[string]uint64{}
+	for _, u := range body.Usage {
+		if u.Endpoint != target.URL || u.Bytes == 0 {
+			t.Errorf("Unexpected row %+v", u)
+		}
+		if strings.Contains(u.Tenant, "key-1") {
+			t.Errorf("Expected static keys to be fingerprinted, got %q", u.Tenant)
+		}
+		attempts[u.Tenant] = u.Attempts
+	}
+	if keys := srv.APIKeys(); len(keys) != 1 || attempts[keys[0].ID] != 2 || len(attempts) != 2 {
+		t.Errorf("Expected one row per tenant with 2 managed-key attempts, got %s", rec.Body.String())
+	}
+
+	rec = do(http.MethodGet, "/v1/usage/egress?format=csv&from=2000-01-01", "key-1", "")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Expected CSV, got %q", ct)
+	}
+	if lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n"); len(lines) != 3 || lines[0] != "day,tenant,endpoint,attempts,bytes" {
+		t.Errorf("Unexpected CSV %q", rec.Body.String())
+	}
+
+	if rec := do(http.MethodGet, "/v1/usage/egress?from=yesterday", "key-1", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a bad date, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
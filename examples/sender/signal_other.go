@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import "github.com/sabry-awad97/Hookshot/webhook"
+
+// toggleDeliveryOnSignal is a no-op where SIGUSR2 does not exist
+func toggleDeliveryOnSignal(*webhook.Client) {}
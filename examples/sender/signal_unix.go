@@ -0,0 +1,30 @@
+//go:build unix
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// toggleDeliveryOnSignal flips the global kill switch on every SIGUSR2
+func toggleDeliveryOnSignal(client *webhook.Client) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR2)
+	for range signals {
+		if all, _ := client.DeliveryDisabled(); all {
+			log.Printf("SIGUSR2: resuming deliveries, flushing %d held", client.Held())
+			go func() {
+				for range client.EnableDelivery() {
+				}
+			}()
+			continue
+		}
+		log.Printf("SIGUSR2: halting all deliveries")
+		client.DisableDelivery()
+	}
+}
@@ -0,0 +1,204 @@
+package receiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxAllowlistSize bounds a fetched allowlist document
+const maxAllowlistSize = 1 << 20
+
+// WithIPAllowlist rejects requests from addresses outside cidrs with 403
+// before the body is read or verified. Bare IPs are accepted as single-host
+// ranges. The address is the connection's remote address, so deployments
+// behind a load balancer must restore the client address first.
+func WithIPAllowlist(cidrs ...string) Option {
+	return func(c *Config) {
+		c.AllowedCIDRs = append(c.AllowedCIDRs, cidrs...)
+	}
+}
+
+// WithIPAllowlistSource loads allowed ranges from url, such as a provider's
+// published list, on New and again once refresh has passed. The document is
+// either a JSON array of strings or plain text with one range per line and #
+// comments. A failed refresh keeps the last list. Static WithIPAllowlist
+// ranges are always allowed.
+func WithIPAllowlistSource(url string, refresh time.Duration) Option {
+	return func(c *Config) {
+		c.AllowlistURL = url
+		c.AllowlistRefresh = refresh
+	}
+}
+
+// allowlist holds the static and fetched ranges; a nil allowlist allows everyone
+type allowlist struct {
+	static  []netip.Prefix
+	url     string
+	refresh time.Duration
+	client  *http.Client
+	now     func() time.Time
+
+	mu         sync.RWMutex
+	fetched    []netip.Prefix
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+func newAllowlist(cfg Config) (*allowlist, error) {
+	if len(cfg.AllowedCIDRs) == 0 && cfg.AllowlistURL == "" {
+		return nil, nil
+	}
+	static, err := parsePrefixes(cfg.AllowedCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("receiver: invalid IP allowlist: %w", err)
+	}
+	a := &allowlist{
+		static:  static,
+		url:     cfg.AllowlistURL,
+		refresh: cfg.AllowlistRefresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+	if a.url != "" {
+		if err := a.load(context.Background()); err != nil {
+			return nil, fmt.Errorf("receiver: failed to load IP allowlist: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// allows reports whether addr, an "ip" or "ip:port", is inside a range,
+// starting a background refresh when the fetched list is stale
+func (a *allowlist) allows(addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+
+	for _, p := range a.static {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	a.mu.Lock()
+	fetched := a.fetched
+	if a.url != "" && a.refresh > 0 && !a.refreshing && a.now().Sub(a.fetchedAt) >= a.refresh {
+		a.refreshing = true
+		go a.backgroundRefresh()
+	}
+	a.mu.Unlock()
+
+	for _, p := range fetched {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *allowlist) backgroundRefresh() {
+	err := a.load(context.Background())
+	a.mu.Lock()
+	a.refreshing = false
+	if err != nil {
+		// Retry after another refresh interval rather than on every request
+		a.fetchedAt = a.now()
+	}
+	a.mu.Unlock()
+}
+
+// load fetches the source and replaces the fetched ranges
+func (a *allowlist) load(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("source returned status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAllowlistSize))
+	if err != nil {
+		return err
+	}
+	prefixes, err := parseAllowlist(body)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.fetched = prefixes
+	a.fetchedAt = a.now()
+	a.mu.Unlock()
+	return nil
+}
+
+// parseAllowlist reads a JSON array of ranges or one range per line
+func parseAllowlist(body []byte) ([]netip.Prefix, error) {
+	var entries []string
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, line := range strings.Split(trimmed, "\n") {
+			if i := strings.IndexByte(line, '#'); i >= 0 {
+				line = line[:i]
+			}
+			if line = strings.TrimSpace(line); line != "" {
+				entries = append(entries, line)
+			}
+		}
+	}
+	return parsePrefixes(entries)
+}
+
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if !strings.Contains(e, "/") {
+			ip, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, err
+			}
+			ip = ip.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
+
+// CheckSource returns ErrForbiddenSource when an IP allowlist is configured
+// and addr, an "ip" or "ip:port", is outside it. Adapters reading requests
+// themselves call it before reading the body.
+func (r *Receiver) CheckSource(addr string) error {
+	if r.allowlist == nil || r.allowlist.allows(addr) {
+		return nil
+	}
+	r.logger.Warn("receiver: request from disallowed address", "addr", addr)
+	return fmt.Errorf("%w: %s", ErrForbiddenSource, addr)
+}
@@ -0,0 +1,94 @@
+package receiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReceiver_IPAllowlist(t *testing.T) {
+	rcv, err := New(testSecret, WithIPAllowlist("10.0.0.0/8", "2001:db8::1"))
+	if err != nil {
+		t.Fatalf("Failed to create receiver: %v", err)
+	}
+	body := `{"event":"order.created","data":{}}`
+
+	for addr, want := range map[string]int{
+		"10.1.2.3:5555":         http.StatusOK,
+		"[2001:db8::1]:443":     http.StatusOK,
+		"[::ffff:10.0.0.9]:443": http.StatusOK,
+		"192.0.2.1:1234":        http.StatusForbidden,
+		"[2001:db8::2]:443":     http.StatusForbidden,
+	} {
+		req := signedRequest(t, body)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		rcv.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Expected status %d from %s, got %d", want, addr, rec.Code)
+		}
+	}
+
+	// Rejected before the signature is looked at
+	req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected unsigned junk to get %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	if _, err := New(testSecret, WithIPAllowlist("10.0.0.0/33")); err == nil {
+		t.Error("Expected an invalid range to be rejected")
+	}
+}
+
+func TestReceiver_IPAllowlistSource(t *testing.T) {
+	var mu sync.Mutex
+	list := "# provider ranges\n198.51.100.0/24\n"
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write([]byte(list))
+	}))
+	defer source.Close()
+
+	rcv, err := New(testSecret, WithIPAllowlistSource(source.URL, time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create receiver: %v", err)
+	}
+	now := time.Now()
+	rcv.allowlist.mu.Lock()
+	rcv.allowlist.now = func() time.Time { return now }
+	rcv.allowlist.mu.Unlock()
+
+	if rcv.CheckSource("198.51.100.7:80") != nil || rcv.CheckSource("203.0.113.5:80") == nil {
+		t.Fatal("Expected the fetched list to apply")
+	}
+
+	mu.Lock()
+	list = `["203.0.113.0/24"]`
+	mu.Unlock()
+	rcv.allowlist.mu.Lock()
+	rcv.allowlist.fetchedAt = now.Add(-2 * time.Minute)
+	rcv.allowlist.mu.Unlock()
+
+	// The stale check still answers from the old list and refreshes in the background
+	rcv.CheckSource("203.0.113.5:80")
+	deadline := time.Now().Add(2 * time.Second)
+	for rcv.CheckSource("203.0.113.5:80") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the refreshed list to apply")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rcv.CheckSource("198.51.100.7:80") == nil {
+		t.Error("Expected ranges dropped from the source to be refused")
+	}
+
+	source.Close()
+	if _, err := New(testSecret, WithIPAllowlistSource(source.URL, time.Minute)); err == nil {
+		t.Error("Expected an unreachable source to fail New")
+	}
+}
@@ -0,0 +1,98 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable marks handler errors caused by a briefly unavailable internal
+// processor (queue, database); the sender should retry later
+var ErrUnavailable = errors.New("receiver: processor unavailable")
+
+// RetryAfterError asks the sender to retry after a delay; it is answered with
+// 503 and a Retry-After header
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.After)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter wraps err so the sender is asked to retry after d
+func RetryAfter(err error, d time.Duration) error {
+	return &RetryAfterError{After: d, Err: err}
+}
+
+// BackpressureConfig configures the Backpressure middleware
+type BackpressureConfig struct {
+	Initial time.Duration // First retry delay (default: 1s)
+	Max     time.Duration // Retry delay cap (default: 5m)
+}
+
+// Backpressure tracks consecutive ErrUnavailable failures with exponential
+// backoff. While backing off it rejects events without calling the processor
+// and tells the sender exactly how long to wait, so sender and receiver retry
+// schedules cooperate instead of fighting.
+func Backpressure(cfg BackpressureConfig) Middleware {
+	if cfg.Initial == 0 {
+		cfg.Initial = time.Second
+	}
+	if cfg.Max == 0 {
+		cfg.Max = 5 * time.Minute
+	}
+
+	var mu sync.Mutex
+	var failures int
+	var until time.Time
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			now := time.Now()
+			mu.Lock()
+			if now.Before(until) {
+				wait := until.Sub(now)
+				mu.Unlock()
+				return RetryAfter(ErrUnavailable, wait)
+			}
+			mu.Unlock()
+
+			err := next(ctx, e)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrUnavailable) {
+				failures++
+				delay := time.Duration(float64(cfg.Initial) * math.Pow(2, float64(failures-1)))
+				if delay > cfg.Max || delay <= 0 {
+					delay = cfg.Max
+				}
+				until = time.Now().Add(delay)
+				return RetryAfter(err, delay)
+			}
+			if err == nil {
+				failures = 0
+			}
+			return err
+		}
+	}
+}
+
+// retryAfterSeconds renders d as a Retry-After value, rounding up to at least 1s
+func retryAfterSeconds(d time.Duration) string {
+	secs := int64(math.Ceil(d.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(secs, 10)
+}
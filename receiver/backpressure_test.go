@@ -0,0 +1,93 @@
+package receiver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestBackpressure(t *testing.T) {
+	rcv, _ := New(testSecret)
+	rcv.Use(Backpressure(BackpressureConfig{Initial: 2 * time.Second, Max: time.Minute}))
+
+	calls := 0
+	down := true
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		calls++
+		if down {
+			return fmt.Errorf("queue publish: %w", ErrUnavailable)
+		}
+		return nil
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+		return rec
+	}
+
+	// Processor failure: NACK with the receiver's own backoff delay
+	rec := send()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After '2', got '%s'", got)
+	}
+	if got := rec.Header().Get(webhook.BackoffHeader); got != "2" {
+		t.Errorf("Expected %s '2', got '%s'", webhook.BackoffHeader, got)
+	}
+
+	// While backing off the processor is not called again
+	rec = send()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d during backoff, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After during backoff")
+	}
+	if calls != 1 {
+		t.Errorf("Expected processor to be skipped during backoff, got %d calls", calls)
+	}
+}
+
+func TestBackpressure_Escalates(t *testing.T) {
+	mw := Backpressure(BackpressureConfig{Initial: time.Nanosecond, Max: time.Hour})
+
+	var last *RetryAfterError
+	h := mw(func(ctx context.Context, e *Event) error { return ErrUnavailable })
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond) // let the previous window lapse
+		err := h(context.Background(), &Event{})
+		ra, ok := err.(*RetryAfterError)
+		if !ok {
+			t.Fatalf("Expected RetryAfterError, got %v", err)
+		}
+		last = ra
+	}
+	if last.After != 4*time.Nanosecond {
+		t.Errorf("Expected delay to double per failure (4ns), got %v", last.After)
+	}
+}
+
+func TestRetryAfter_Explicit(t *testing.T) {
+	rcv, _ := New(testSecret)
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		return RetryAfter(ErrUnavailable, 1500*time.Millisecond)
+	})
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After rounded up to '2', got '%s'", got)
+	}
+}
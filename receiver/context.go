@@ -0,0 +1,91 @@
+package receiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+type eventContextKey struct{}
+
+// eventContext is the correlation data placed into handler contexts
+type eventContext struct {
+	messageID string
+	eventType string
+	timestamp time.Time
+	attempt   int
+	tenant    string
+	provider  string
+
+	correlationID string
+	causationID   string
+}
+
+// withEvent also carries e.Next() so webhooks sent while handling e join its chain
+func withEvent(ctx context.Context, e *Event) context.Context {
+	ctx = webhook.ContextWithCorrelation(ctx, e.Next())
+	return context.WithValue(ctx, eventContextKey{}, eventContext{
+		messageID: e.ID,
+		eventType: e.Type,
+		timestamp: e.Timestamp,
+		attempt:   e.Attempt,
+		tenant:    e.Tenant,
+		provider:  e.Provider,
+
+		correlationID: e.CorrelationID,
+		causationID:   e.CausationID,
+	})
+}
+
+func fromContext(ctx context.Context) eventContext {
+	ec, _ := ctx.Value(eventContextKey{}).(eventContext)
+	return ec
+}
+
+// MessageIDFromContext returns the message ID of the event being handled
+func MessageIDFromContext(ctx context.Context) string {
+	return fromContext(ctx).messageID
+}
+
+// EventTypeFromContext returns the event type being handled
+func EventTypeFromContext(ctx context.Context) string {
+	return fromContext(ctx).eventType
+}
+
+// TimestampFromContext returns the payload timestamp of the event being handled
+func TimestampFromContext(ctx context.Context) time.Time {
+	return fromContext(ctx).timestamp
+}
+
+// AttemptFromContext returns the sender's delivery attempt number, or 0 if unknown
+func AttemptFromContext(ctx context.Context) int {
+	return fromContext(ctx).attempt
+}
+
+// TenantFromContext returns the tenant resolved by the receiver's tenant function
+func TenantFromContext(ctx context.Context) string {
+	return fromContext(ctx).tenant
+}
+
+// ProviderFromContext returns the third-party provider of a fan-in event, or "" for Hookshot deliveries
+func ProviderFromContext(ctx context.Context) string {
+	return fromContext(ctx).provider
+}
+
+// CorrelationIDFromContext returns the correlation ID of the event being handled
+func CorrelationIDFromContext(ctx context.Context) string {
+	return fromContext(ctx).correlationID
+}
+
+// CausationIDFromContext returns the causation ID of the event being handled
+func CausationIDFromContext(ctx context.Context) string {
+	return fromContext(ctx).causationID
+}
+
+// TenantFromHeader resolves the tenant from a request header
+func TenantFromHeader(name string) func(e *Event) string {
+	return func(e *Event) string {
+		return e.Header.Get(name)
+	}
+}
@@ -0,0 +1,122 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestContextAccessors(t *testing.T) {
+	rcv, _ := New(testSecret, WithTenantFunc(TenantFromHeader("X-Tenant-ID")))
+
+	var got struct {
+		id, event, tenant string
+		attempt           int
+		ts                time.Time
+	}
+	rcv.Use(func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			// Middleware sees the same correlation data as handlers
+			if MessageIDFromContext(ctx) != e.ID {
+				t.Errorf("Expected middleware message ID '%s', got '%s'", e.ID, MessageIDFromContext(ctx))
+			}
+			return next(ctx, e)
+		}
+	})
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		got.id = MessageIDFromContext(ctx)
+		got.event = EventTypeFromContext(ctx)
+		got.tenant = TenantFromContext(ctx)
+		got.attempt = AttemptFromContext(ctx)
+		got.ts = TimestampFromContext(ctx)
+		return nil
+	})
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so the handler sees attempt 2
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		r.Header.Set("X-Tenant-ID", "acme")
+		rcv.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithDeterminism(webhook.Determinism{Scheduler: immediate{}}))
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if got.id != resp.MessageID {
+		t.Errorf("Expected message ID '%s', got '%s'", resp.MessageID, got.id)
+	}
+	if got.event != "order.created" {
+		t.Errorf("Expected event 'order.created', got '%s'", got.event)
+	}
+	if got.tenant != "acme" {
+		t.Errorf("Expected tenant 'acme', got '%s'", got.tenant)
+	}
+	if got.attempt != 2 {
+		t.Errorf("Expected attempt 2, got %d", got.attempt)
+	}
+	if got.ts.IsZero() {
+		t.Error("Expected payload timestamp")
+	}
+}
+
+func TestContextAccessors_Empty(t *testing.T) {
+	ctx := context.Background()
+	if MessageIDFromContext(ctx) != "" || AttemptFromContext(ctx) != 0 || TenantFromContext(ctx) != "" {
+		t.Error("Expected zero values outside a handler")
+	}
+}
+
+func TestCorrelation_PropagatesThroughHandlers(t *testing.T) {
+	var last *Event
+	end, _ := New(testSecret)
+	end.OnAll(func(ctx context.Context, e *Event) error {
+		last = e
+		return nil
+	})
+	endServer := httptest.NewServer(end)
+	defer endServer.Close()
+	toEnd, _ := webhook.NewClient(endServer.URL, testSecret)
+
+	var middle *Event
+	mid, _ := New(testSecret)
+	mid.OnAll(func(ctx context.Context, e *Event) error {
+		middle = e
+		return toEnd.Send(ctx, "invoice.created", map[string]any{}).Error
+	})
+	midServer := httptest.NewServer(mid)
+	defer midServer.Close()
+	toMid, _ := webhook.NewClient(midServer.URL, testSecret)
+
+	resp := toMid.Send(context.Background(), "order.created", map[string]any{})
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if middle.CorrelationID != "" || middle.CausationID != "" {
+		t.Errorf("Expected the first event to carry no correlation, got %q %q", middle.CorrelationID, middle.CausationID)
+	}
+	if last.CorrelationID != resp.MessageID || last.CausationID != middle.ID {
+		t.Errorf("Expected chain rooted at %s caused by %s, got %q %q", resp.MessageID, middle.ID, last.CorrelationID, last.CausationID)
+	}
+}
+
+// immediate fires retry delays without waiting
+type immediate struct{}
+
+func (immediate) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
@@ -0,0 +1,78 @@
+// Package echoadapter integrates the receiver with Echo applications
+package echoadapter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const eventKey = "hookshot.event"
+
+// Handler verifies the request, by a query token when the receiver accepts
+// them, and dispatches it through the receiver's handlers
+func Handler(r *receiver.Receiver) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := readBody(c, r)
+		if err != nil {
+			res := receiver.ErrorResult(err)
+			return c.JSON(res.Status, res.Body)
+		}
+
+		res := r.ProcessWithToken(c.Request().Context(), c.QueryParam(signing.TokenParam), body, c.Request().Header)
+		for k, vs := range res.Header {
+			c.Response().Header()[k] = vs
+		}
+		return c.JSON(res.Status, res.Body)
+	}
+}
+
+// Verify is middleware that only verifies the request and stores the event for
+// downstream Echo handlers, which read it back with EventFrom
+func Verify(r *receiver.Receiver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			body, err := readBody(c, r)
+			if err != nil {
+				res := receiver.ErrorResult(err)
+				return c.JSON(res.Status, res.Body)
+			}
+
+			event, err := r.VerifyWithToken(c.QueryParam(signing.TokenParam), body, c.Request().Header)
+			if err != nil {
+				res := receiver.ErrorResult(err)
+				return c.JSON(res.Status, res.Body)
+			}
+
+			c.Set(eventKey, event)
+			return next(c)
+		}
+	}
+}
+
+// EventFrom returns the event stored by Verify
+func EventFrom(c echo.Context) (*receiver.Event, bool) {
+	e, ok := c.Get(eventKey).(*receiver.Event)
+	return e, ok
+}
+
+func readBody(c echo.Context, r *receiver.Receiver) ([]byte, error) {
+	if err := r.CheckSource(c.Request().RemoteAddr); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, r.MaxBodySize()))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, receiver.ErrBodyTooLarge
+		}
+		return nil, fmt.Errorf("%w: %v", receiver.ErrInvalidPayload, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,119 @@
+package echoadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/labstack/echo/v4"
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	signer, _ := svix.NewWebhook(testSecret)
+	ts := time.Now()
+	sig, _ := signer.Sign("msg_test", ts, []byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("svix-id", "msg_test")
+	req.Header.Set("svix-timestamp", fmt.Sprintf("%d", ts.Unix()))
+	req.Header.Set("svix-signature", sig)
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+
+	var got string
+	rcv.On("order.created", func(ctx context.Context, e *receiver.Event) error {
+		got = e.ID
+		return nil
+	})
+
+	e := echo.New()
+	e.POST("/webhook", Handler(rcv))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, signedRequest(t, `{"event":"order.created","data":{}}`))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if got != "msg_test" {
+		t.Errorf("Expected handler to see 'msg_test', got '%s'", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+
+	e := echo.New()
+	e.POST("/webhook", func(c echo.Context) error {
+		ev, ok := EventFrom(c)
+		if !ok {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.String(http.StatusOK, ev.Type)
+	}, Verify(rcv))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+	if rec.Code != http.StatusOK || rec.Body.String() != "a.b" {
+		t.Errorf("Expected 200 'a.b', got %d '%s'", rec.Code, rec.Body.String())
+	}
+
+	req := signedRequest(t, `{"event":"a.b","data":{}}`)
+	req.Header.Set("svix-signature", "v1,invalid")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestQueryTokens(t *testing.T) {
+	rcv, _ := receiver.New(testSecret, receiver.WithQueryTokens())
+	var got string
+	rcv.On("order.created", func(ctx context.Context, e *receiver.Event) error {
+		got = e.ID
+		return nil
+	})
+
+	e := echo.New()
+	e.POST("/webhook", Handler(rcv))
+	e.POST("/verified", func(c echo.Context) error {
+		ev, _ := EventFrom(c)
+		return c.String(http.StatusOK, ev.ID)
+	}, Verify(rcv))
+
+	key, _ := signing.DecodeSecret(testSecret)
+	body := `{"event":"order.created","data":{}}`
+	tokenRequest := func(path string) *http.Request {
+		now := time.Now()
+		token := signing.SignToken(key, "msg_token", now, now.Add(time.Minute), []byte(body))
+		return httptest.NewRequest(http.MethodPost, path+"?"+signing.TokenParam+"="+token, strings.NewReader(body))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, tokenRequest("/webhook"))
+	if rec.Code != http.StatusOK || got != "msg_token" {
+		t.Errorf("Expected the token-only delivery handled as msg_token, got %d and %q", rec.Code, got)
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, tokenRequest("/verified"))
+	if rec.Code != http.StatusOK || rec.Body.String() != "msg_token" {
+		t.Errorf("Expected Verify to accept the token, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,77 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ErrUnsupportedEncoding is returned for a Content-Encoding the receiver cannot decode
+var ErrUnsupportedEncoding = errors.New("receiver: unsupported content encoding")
+
+// WithMaxDecodedSize caps the size of a decompressed body (default: MaxBodySize),
+// so a small compressed request cannot expand without bound
+func WithMaxDecodedSize(n int64) Option {
+	return func(c *Config) {
+		c.MaxDecodedSize = n
+	}
+}
+
+// WithEncodedSignatures verifies signatures over the compressed bytes as sent
+// rather than the decoded body. Verification then happens before
+// decompression, so unauthenticated requests are never inflated.
+func WithEncodedSignatures() Option {
+	return func(c *Config) {
+		c.SignEncoded = true
+	}
+}
+
+// contentEncoding returns the normalized Content-Encoding, "" for identity
+func contentEncoding(h http.Header) string {
+	enc := strings.ToLower(strings.TrimSpace(h.Get("Content-Encoding")))
+	if enc == "identity" {
+		return ""
+	}
+	return enc
+}
+
+// decodeBody decompresses a gzip or zstd body, failing with ErrBodyTooLarge
+// once the decoded size exceeds limit
+func decodeBody(enc string, body []byte, limit int64) ([]byte, error) {
+	var r io.Reader
+	switch enc {
+	case "":
+		return body, nil
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		defer zr.Close()
+		r = zr
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body), zstd.WithDecoderMaxMemory(uint64(limit)+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, enc)
+	}
+
+	decoded, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil && !errors.Is(err, zstd.ErrDecoderSizeExceeded) && !errors.Is(err, zstd.ErrWindowSizeExceeded) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if err != nil || int64(len(decoded)) > limit {
+		return nil, fmt.Errorf("%w: decoded body exceeds %d bytes", ErrBodyTooLarge, limit)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,136 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(b)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func zstdBytes(b []byte) []byte {
+	enc, _ := zstd.NewWriter(nil)
+	defer enc.Close()
+	return enc.EncodeAll(b, nil)
+}
+
+// encodedRequest sends wire as the body, signed over signed
+func encodedRequest(t *testing.T, encoding string, wire, signed []byte) *http.Request {
+	t.Helper()
+	signer, _ := svix.NewWebhook(testSecret)
+	ts := time.Now()
+	sig, _ := signer.Sign("msg_test", ts, signed)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(wire))
+	req.Header.Set("Content-Encoding", encoding)
+	req.Header.Set("svix-id", "msg_test")
+	req.Header.Set("svix-timestamp", fmt.Sprintf("%d", ts.Unix()))
+	req.Header.Set("svix-signature", sig)
+	return req
+}
+
+func TestReceiver_ContentEncoding(t *testing.T) {
+	body := []byte(`{"event":"order.created","data":{"order_id":"123"}}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+		wire     []byte
+		signed   []byte
+		opts     []Option
+		want     int
+	}{
+		{name: "gzip over decoded", encoding: "gzip", wire: gzipBytes(body), signed: body, want: http.StatusOK},
+		{name: "zstd over decoded", encoding: "zstd", wire: zstdBytes(body), signed: body, want: http.StatusOK},
+		{name: "identity", encoding: "identity", wire: body, signed: body, want: http.StatusOK},
+		{name: "gzip over encoded", encoding: "gzip", wire: gzipBytes(body), signed: gzipBytes(body), opts: []Option{WithEncodedSignatures()}, want: http.StatusOK},
+		{name: "encoded signature by default", encoding: "gzip", wire: gzipBytes(body), signed: gzipBytes(body), want: http.StatusUnauthorized},
+		{name: "corrupt", encoding: "gzip", wire: []byte("not gzip"), signed: body, want: http.StatusBadRequest},
+		{name: "unsupported", encoding: "br", wire: body, signed: body, want: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv, _ := New(testSecret, tt.opts...)
+			var got string
+			rcv.On("order.created", func(ctx context.Context, e *Event) error {
+				got = string(e.Body)
+				return nil
+			})
+
+			rec := httptest.NewRecorder()
+			rcv.ServeHTTP(rec, encodedRequest(t, tt.encoding, tt.wire, tt.signed))
+
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want == http.StatusOK && got != string(body) {
+				t.Errorf("Expected decoded body %s, got %s", body, got)
+			}
+		})
+	}
+}
+
+func TestReceiver_DecompressionBomb(t *testing.T) {
+	body := []byte(`{"event":"order.created","data":{"pad":"` + strings.Repeat("a", 1<<20) + `"}}`)
+
+	for _, tt := range []struct {
+		encoding string
+		wire     []byte
+	}{{"gzip", gzipBytes(body)}, {"zstd", zstdBytes(body)}} {
+		t.Run(tt.encoding, func(t *testing.T) {
+			rcv, _ := New(testSecret, WithMaxDecodedSize(64<<10))
+			if len(tt.wire) > 64<<10 {
+				t.Fatalf("Expected a small compressed body, got %d bytes", len(tt.wire))
+			}
+
+			rec := httptest.NewRecorder()
+			rcv.ServeHTTP(rec, encodedRequest(t, tt.encoding, tt.wire, body))
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+			}
+		})
+	}
+}
+
+func FuzzDecodeBody(f *testing.F) {
+	f.Add("gzip", gzipBytes([]byte(`{"event":"order.created"}`)))
+	f.Add("zstd", zstdBytes([]byte(`{"event":"order.created"}`)))
+	f.Add("zstd", zstdBytes(bytes.Repeat([]byte("a"), 4096)))
+	f.Add("x-gzip", []byte{0x1f, 0x8b, 0x08})
+	f.Add("br", []byte("data"))
+
+	const limit = 1024
+	f.Fuzz(func(t *testing.T, enc string, body []byte) {
+		enc = contentEncoding(http.Header{"Content-Encoding": {enc}})
+		if enc == "" {
+			return // Identity bodies are bounded by MaxBodySize, not here
+		}
+		decoded, err := decodeBody(enc, body, limit)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidPayload) && !errors.Is(err, ErrBodyTooLarge) && !errors.Is(err, ErrUnsupportedEncoding) {
+				t.Fatalf("Unclassified decode error: %v", err)
+			}
+			return
+		}
+		if len(decoded) > limit {
+			t.Fatalf("Decoded %d bytes past the %d byte limit", len(decoded), limit)
+		}
+	})
+}
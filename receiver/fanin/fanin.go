@@ -0,0 +1,117 @@
+// Package fanin aggregates verified webhooks from third-party providers into
+// one stream of receiver events
+package fanin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// Sink receives every normalized event
+type Sink func(ctx context.Context, e *receiver.Event) error
+
+// ToReceiver delivers events through a Receiver's middleware and handlers
+func ToReceiver(r *receiver.Receiver) Sink {
+	return r.Dispatch
+}
+
+// Envelope is the payload re-emitted by ToClient
+type Envelope struct {
+	Provider  string          `json:"provider"`
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp,omitzero"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ToClient re-emits events as Hookshot webhooks, using the provider's event
+// ID as the idempotency key so provider retries are delivered once
+func ToClient(c *webhook.Client) Sink {
+	return func(ctx context.Context, e *receiver.Event) error {
+		var opts []webhook.SendOption
+		if e.ID != "" {
+			opts = append(opts, webhook.WithIdempotencyKey(e.Provider+":"+e.ID))
+		}
+		resp := c.Send(ctx, e.Type, Envelope{Provider: e.Provider, ID: e.ID, Timestamp: e.Timestamp, Data: e.Data}, opts...)
+		return resp.Error
+	}
+}
+
+// Aggregator verifies inbound webhooks with the provider they are addressed
+// to and passes the normalized events to a sink
+type Aggregator struct {
+	sink        Sink
+	providers   map[string]Provider
+	maxBodySize int64
+}
+
+// New creates an aggregator over the given providers
+func New(sink Sink, providers ...Provider) *Aggregator {
+	a := &Aggregator{
+		sink:        sink,
+		providers:   make(map[string]Provider, len(providers)),
+		maxBodySize: 1 << 20,
+	}
+	for _, p := range providers {
+		a.providers[p.Name()] = p
+	}
+	return a
+}
+
+// Handler serves one provider's endpoint
+func (a *Aggregator) Handler(provider string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		a.serve(w, req, provider)
+	})
+}
+
+// ServeHTTP routes by the last path segment, e.g. /inbound/stripe
+func (a *Aggregator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	a.serve(w, req, path.Base(req.URL.Path))
+}
+
+func (a *Aggregator) serve(w http.ResponseWriter, req *http.Request, name string) {
+	p, ok := a.providers[name]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "Unknown provider"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, a.maxBodySize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			res := receiver.ErrorResult(receiver.ErrBodyTooLarge)
+			writeJSON(w, res.Status, res.Body)
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "Failed to read body"})
+		return
+	}
+
+	event, err := p.Verify(body, req.Header)
+	if err != nil {
+		res := receiver.ErrorResult(err)
+		writeJSON(w, res.Status, res.Body)
+		return
+	}
+
+	res := receiver.DispatchResult(event, a.sink(req.Context(), event))
+	for k, vs := range res.Header {
+		w.Header()[k] = vs
+	}
+	writeJSON(w, res.Status, res.Body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
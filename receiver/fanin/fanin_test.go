@@ -0,0 +1,231 @@
+package fanin
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func mac(secret string, parts ...string) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	for _, p := range parts {
+		m.Write([]byte(p))
+	}
+	return m.Sum(nil)
+}
+
+func stripeRequest(body string, ts time.Time, secret string) *http.Request {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/inbound/stripe", bytes.NewBufferString(body))
+	req.Header.Set("Stripe-Signature", "t="+t+",v1="+hex.EncodeToString(mac(secret, t, ".", body)))
+	return req
+}
+
+func githubRequest(body, event, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/inbound/github", bytes.NewBufferString(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "72d3162e")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac(secret, body)))
+	return req
+}
+
+func shopifyRequest(body, topic, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/inbound/shopify", bytes.NewBufferString(body))
+	req.Header.Set("X-Shopify-Topic", topic)
+	req.Header.Set("X-Shopify-Webhook-Id", "b54557e4")
+	req.Header.Set("X-Shopify-Triggered-At", "2024-01-02T03:04:05Z")
+	req.Header.Set("X-Shopify-Hmac-Sha256", base64.StdEncoding.EncodeToString(mac(secret, body)))
+	return req
+}
+
+func TestAggregator(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *http.Request
+		status   int
+		wantType string
+		wantID   string
+		provider string
+	}{
+		{
+			name:     "stripe",
+			req:      stripeRequest(`{"id":"evt_1","type":"invoice.paid","created":1700000000,"data":{"object":{}}}`, time.Now(), "whsec_stripe"),
+			status:   http.StatusOK,
+			wantType: "stripe.invoice.paid",
+			wantID:   "evt_1",
+			provider: "stripe",
+		},
+		{
+			name:     "github",
+			req:      githubRequest(`{"action":"opened","issue":{}}`, "issues", "gh-secret"),
+			status:   http.StatusOK,
+			wantType: "github.issues.opened",
+			wantID:   "72d3162e",
+			provider: "github",
+		},
+		{
+			name:     "github without action",
+			req:      githubRequest(`{"ref":"refs/heads/main"}`, "push", "gh-secret"),
+			status:   http.StatusOK,
+			wantType: "github.push",
+			wantID:   "72d3162e",
+			provider: "github",
+		},
+		{
+			name:     "shopify",
+			req:      shopifyRequest(`{"id":820982911946154508}`, "orders/create", "shop-secret"),
+			status:   http.StatusOK,
+			wantType: "shopify.orders.create",
+			wantID:   "b54557e4",
+			provider: "shopify",
+		},
+		{name: "stripe wrong secret", req: stripeRequest(`{"id":"evt_1"}`, time.Now(), "other"), status: http.StatusUnauthorized},
+		{name: "stripe stale", req: stripeRequest(`{"id":"evt_1"}`, time.Now().Add(-time.Hour), "whsec_stripe"), status: http.StatusUnauthorized},
+		{name: "github wrong secret", req: githubRequest(`{}`, "push", "other"), status: http.StatusUnauthorized},
+		{name: "shopify wrong secret", req: shopifyRequest(`{}`, "orders/create", "other"), status: http.StatusUnauthorized},
+		{name: "unknown provider", req: httptest.NewRequest(http.MethodPost, "/inbound/paypal", nil), status: http.StatusNotFound},
+		{name: "missing headers", req: httptest.NewRequest(http.MethodPost, "/inbound/github", bytes.NewBufferString(`{}`)), status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *receiver.Event
+			agg := New(func(ctx context.Context, e *receiver.Event) error {
+				got = e
+				return nil
+			}, Stripe("whsec_stripe"), GitHub("gh-secret"), Shopify("shop-secret"))
+
+			rec := httptest.NewRecorder()
+			agg.ServeHTTP(rec, tt.req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				if got != nil {
+					t.Error("Expected sink not to be called")
+				}
+				return
+			}
+			if got.Type != tt.wantType || got.ID != tt.wantID || got.Provider != tt.provider {
+				t.Errorf("Expected %s/%s from %s, got %s/%s from %s", tt.wantType, tt.wantID, tt.provider, got.Type, got.ID, got.Provider)
+			}
+		})
+	}
+}
+
+func TestAggregator_ToReceiver(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+	var provider string
+	rcv.On("github.push", func(ctx context.Context, e *receiver.Event) error {
+		provider = receiver.ProviderFromContext(ctx)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	New(ToReceiver(rcv), GitHub("gh-secret")).Handler("github").ServeHTTP(rec, githubRequest(`{}`, "push", "gh-secret"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if provider != "github" {
+		t.Errorf("Expected provider github in context, got %q", provider)
+	}
+}
+
+func TestAggregator_SinkError(t *testing.T) {
+	agg := New(func(ctx context.Context, e *receiver.Event) error {
+		return receiver.RetryAfter(errors.New("busy"), 30*time.Second)
+	}, GitHub("gh-secret"))
+
+	rec := httptest.NewRecorder()
+	agg.ServeHTTP(rec, githubRequest(`{}`, "push", "gh-secret"))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("Expected Retry-After 30, got %q", rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestToClient(t *testing.T) {
+	var header http.Header
+	var payload struct {
+		Event string   `json:"event"`
+		Data  Envelope `json:"data"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithMaxRetries(1))
+
+	rec := httptest.NewRecorder()
+	New(ToClient(client), Shopify("shop-secret")).ServeHTTP(rec, shopifyRequest(`{"id":1}`, "orders/create", "shop-secret"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if payload.Event != "shopify.orders.create" || payload.Data.Provider != "shopify" || payload.Data.ID != "b54557e4" {
+		t.Errorf("Unexpected re-emitted payload: %+v", payload)
+	}
+	if string(payload.Data.Data) != `{"id":1}` {
+		t.Errorf("Expected provider data to be preserved, got %s", payload.Data.Data)
+	}
+	if header.Get("Idempotency-Key") != "shopify:b54557e4" {
+		t.Errorf("Expected provider-scoped idempotency key, got %q", header.Get("Idempotency-Key"))
+	}
+}
+
+func FuzzProviders(f *testing.F) {
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	f.Add([]byte(`{"id":"evt_1","type":"invoice.paid","created":1,"data":{}}`), "t="+ts+",v1="+hex.EncodeToString(mac("stripe-secret", ts, ".", `{"id":"evt_1","type":"invoice.paid","created":1,"data":{}}`)), "issues")
+	f.Add([]byte(`{"action":"opened"}`), "sha256="+hex.EncodeToString(mac("github-secret", `{"action":"opened"}`)), "issues")
+	f.Add([]byte(`{}`), base64.StdEncoding.EncodeToString(mac("shopify-secret", `{}`)), "orders/create")
+	f.Add([]byte(`not json`), "t=,v1=", "")
+
+	providers := []Provider{Stripe("stripe-secret"), GitHub("github-secret"), Shopify("shopify-secret")}
+	f.Fuzz(func(t *testing.T, body []byte, sig, topic string) {
+		header := http.Header{
+			"Stripe-Signature":      {sig},
+			"X-Hub-Signature-256":   {sig},
+			"X-Github-Event":        {topic},
+			"X-Shopify-Hmac-Sha256": {sig},
+			"X-Shopify-Topic":       {topic},
+		}
+		for _, p := range providers {
+			e, err := p.Verify(body, header)
+			if err != nil {
+				if !errors.Is(err, receiver.ErrMissingHeaders) && !errors.Is(err, receiver.ErrVerification) && !errors.Is(err, receiver.ErrInvalidPayload) {
+					t.Fatalf("%s: unclassified error %v", p.Name(), err)
+				}
+				continue
+			}
+			if e.Provider != p.Name() {
+				t.Fatalf("%s: event attributed to %q", p.Name(), e.Provider)
+			}
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package fanin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// Provider verifies one third-party provider's webhooks and normalizes them
+// into receiver events
+type Provider interface {
+	Name() string
+	Verify(body []byte, header http.Header) (*receiver.Event, error)
+}
+
+// stripeTolerance matches the Stripe SDKs' default timestamp tolerance
+const stripeTolerance = 5 * time.Minute
+
+// Stripe verifies Stripe-Signature headers (HMAC-SHA256 over "{t}.{body}")
+// with an endpoint secret; event types are prefixed "stripe."
+func Stripe(secret string) Provider {
+	return stripeProvider{secret: []byte(secret), now: time.Now}
+}
+
+type stripeProvider struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func (p stripeProvider) Name() string { return "stripe" }
+
+func (p stripeProvider) Verify(body []byte, header http.Header) (*receiver.Event, error) {
+	sig := header.Get("Stripe-Signature")
+	if sig == "" {
+		return nil, receiver.ErrMissingHeaders
+	}
+
+	parsed, err := signing.ParseStripeSignature(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid Stripe-Signature header", receiver.ErrVerification)
+	}
+	if age := p.now().Sub(time.Unix(parsed.Timestamp, 0)); age > stripeTolerance || age < -stripeTolerance {
+		return nil, fmt.Errorf("%w: message timestamp outside tolerance", receiver.ErrVerification)
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(strconv.FormatInt(parsed.Timestamp, 10) + "."))
+	mac.Write(body)
+	if !signing.MatchAny(hex.EncodeToString(mac.Sum(nil)), parsed.V1) {
+		return nil, fmt.Errorf("%w: no matching v1 signature", receiver.ErrVerification)
+	}
+
+	var payload struct {
+		ID      string          `json:"id"`
+		Type    string          `json:"type"`
+		Created int64           `json:"created"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", receiver.ErrInvalidPayload, err)
+	}
+	return &receiver.Event{
+		ID:        payload.ID,
+		Type:      eventType(p.Name(), payload.Type),
+		Timestamp: time.Unix(payload.Created, 0),
+		Data:      payload.Data,
+		Provider:  p.Name(),
+		Header:    header,
+		Body:      body,
+	}, nil
+}
+
+// GitHub verifies X-Hub-Signature-256 headers; event types are
+// "github.{X-GitHub-Event}[.{action}]", e.g. github.issues.opened
+func GitHub(secret string) Provider {
+	return githubProvider{secret: []byte(secret)}
+}
+
+type githubProvider struct{ secret []byte }
+
+func (p githubProvider) Name() string { return "github" }
+
+func (p githubProvider) Verify(body []byte, header http.Header) (*receiver.Event, error) {
+	sig, event := header.Get("X-Hub-Signature-256"), header.Get("X-GitHub-Event")
+	if sig == "" || event == "" {
+		return nil, receiver.ErrMissingHeaders
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	if !signing.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), sig) {
+		return nil, fmt.Errorf("%w: X-Hub-Signature-256 mismatch", receiver.ErrVerification)
+	}
+
+	var payload struct {
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", receiver.ErrInvalidPayload, err)
+	}
+	name := event
+	if payload.Action != "" {
+		name += "." + payload.Action
+	}
+	return &receiver.Event{
+		ID:       header.Get("X-GitHub-Delivery"),
+		Type:     eventType(p.Name(), name),
+		Data:     body,
+		Provider: p.Name(),
+		Header:   header,
+		Body:     body,
+	}, nil
+}
+
+// Shopify verifies X-Shopify-Hmac-Sha256 headers; topics map to event types
+// with slashes as dots, e.g. orders/create becomes shopify.orders.create
+func Shopify(secret string) Provider {
+	return shopifyProvider{secret: []byte(secret)}
+}
+
+type shopifyProvider struct{ secret []byte }
+
+func (p shopifyProvider) Name() string { return "shopify" }
+
+func (p shopifyProvider) Verify(body []byte, header http.Header) (*receiver.Event, error) {
+	sig, topic := header.Get("X-Shopify-Hmac-Sha256"), header.Get("X-Shopify-Topic")
+	if sig == "" || topic == "" {
+		return nil, receiver.ErrMissingHeaders
+	}
+
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	if !signing.Equal(base64.StdEncoding.EncodeToString(mac.Sum(nil)), sig) {
+		return nil, fmt.Errorf("%w: X-Shopify-Hmac-Sha256 mismatch", receiver.ErrVerification)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("%w: body is not JSON", receiver.ErrInvalidPayload)
+	}
+
+	ts, _ := time.Parse(time.RFC3339, header.Get("X-Shopify-Triggered-At"))
+	return &receiver.Event{
+		ID:        header.Get("X-Shopify-Webhook-Id"),
+		Type:      eventType(p.Name(), topic),
+		Timestamp: ts,
+		Data:      body,
+		Provider:  p.Name(),
+		Header:    header,
+		Body:      body,
+	}, nil
+}
+
+// eventType namespaces a provider's event name under the provider
+func eventType(provider, name string) string {
+	return provider + "." + strings.ToLower(strings.ReplaceAll(name, "/", "."))
+}
@@ -0,0 +1,82 @@
+// Package fiberadapter integrates the receiver with Fiber applications
+package fiberadapter
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const eventKey = "hookshot.event"
+
+// Handler verifies the request, by a query token when the receiver accepts
+// them, and dispatches it through the receiver's handlers
+func Handler(r *receiver.Receiver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := r.CheckSource(c.IP()); err != nil {
+			res := receiver.ErrorResult(err)
+			return c.Status(res.Status).JSON(res.Body)
+		}
+		res := r.ProcessWithToken(c.UserContext(), c.Query(signing.TokenParam), requestBody(c), header(c))
+		for k, vs := range res.Header {
+			for _, v := range vs {
+				c.Append(k, v)
+			}
+		}
+		return c.Status(res.Status).JSON(res.Body)
+	}
+}
+
+// Verify is middleware that only verifies the request and stores the event for
+// downstream Fiber handlers, which read it back with EventFrom
+func Verify(r *receiver.Receiver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := r.CheckSource(c.IP()); err != nil {
+			res := receiver.ErrorResult(err)
+			return c.Status(res.Status).JSON(res.Body)
+		}
+		body := requestBody(c)
+		if int64(len(body)) > r.MaxBodySize() {
+			res := receiver.ErrorResult(receiver.ErrBodyTooLarge)
+			return c.Status(res.Status).JSON(res.Body)
+		}
+
+		event, err := r.VerifyWithToken(c.Query(signing.TokenParam), body, header(c))
+		if err != nil {
+			res := receiver.ErrorResult(err)
+			return c.Status(res.Status).JSON(res.Body)
+		}
+
+		c.Locals(eventKey, event)
+		return c.Next()
+	}
+}
+
+// EventFrom returns the event stored by Verify
+func EventFrom(c *fiber.Ctx) (*receiver.Event, bool) {
+	e, ok := c.Locals(eventKey).(*receiver.Event)
+	return e, ok
+}
+
+// requestBody copies the body out of fasthttp's buffer, which is reused once
+// the handler returns, so the Event built from it stays valid
+func requestBody(c *fiber.Ctx) []byte {
+	return bytes.Clone(c.Body())
+}
+
+// header converts fasthttp request headers to an http.Header, copying the
+// strings since they alias fasthttp's reused buffers
+func header(c *fiber.Ctx) http.Header {
+	h := make(http.Header)
+	for k, vs := range c.GetReqHeaders() {
+		for _, v := range vs {
+			h.Add(strings.Clone(k), strings.Clone(v))
+		}
+	}
+	return h
+}
@@ -0,0 +1,153 @@
+package fiberadapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/receiver"
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/gofiber/fiber/v2"
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func signedRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	signer, _ := svix.NewWebhook(testSecret)
+	ts := time.Now()
+	sig, _ := signer.Sign("msg_test", ts, []byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("svix-id", "msg_test")
+	req.Header.Set("svix-timestamp", fmt.Sprintf("%d", ts.Unix()))
+	req.Header.Set("svix-signature", sig)
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+
+	var got string
+	rcv.On("order.created", func(ctx context.Context, e *receiver.Event) error {
+		got = e.ID
+		return nil
+	})
+
+	app := fiber.New()
+	app.Post("/webhook", Handler(rcv))
+
+	resp, err := app.Test(signedRequest(t, `{"event":"order.created","data":{}}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+	}
+	if got != "msg_test" {
+		t.Errorf("Expected handler to see 'msg_test', got '%s'", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+
+	app := fiber.New()
+	app.Post("/webhook", Verify(rcv), func(c *fiber.Ctx) error {
+		e, ok := EventFrom(c)
+		if !ok {
+			return c.SendStatus(http.StatusInternalServerError)
+		}
+		return c.SendString(e.Type)
+	})
+
+	resp, _ := app.Test(signedRequest(t, `{"event":"a.b","data":{}}`))
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "a.b" {
+		t.Errorf("Expected 200 'a.b', got %d '%s'", resp.StatusCode, body)
+	}
+
+	req := signedRequest(t, `{"event":"a.b","data":{}}`)
+	req.Header.Set("svix-signature", "v1,invalid")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestHandler_EventOutlivesRequest(t *testing.T) {
+	rcv, _ := receiver.New(testSecret)
+
+	var events []*receiver.Event
+	rcv.OnAll(func(ctx context.Context, e *receiver.Event) error {
+		events = append(events, e)
+		return nil
+	})
+
+	app := fiber.New()
+	app.Post("/webhook", Handler(rcv))
+
+	// fasthttp reuses request buffers, so a retained Event must own its bytes
+	bodies := []string{`{"event":"order.created","data":{}}`, `{"event":"order.updated","data":{}}`}
+	for _, body := range bodies {
+		if resp, err := app.Test(signedRequest(t, body)); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+	if len(events) != len(bodies) {
+		t.Fatalf("Expected %d events, got %d", len(bodies), len(events))
+	}
+	for i, e := range events {
+		if string(e.Body) != bodies[i] || e.Header.Get("svix-id") != "msg_test" {
+			t.Errorf("Expected event %d intact, got body %s and id %q", i, e.Body, e.Header.Get("svix-id"))
+		}
+	}
+}
+
+func TestQueryTokens(t *testing.T) {
+	rcv, _ := receiver.New(testSecret, receiver.WithQueryTokens())
+	var got string
+	rcv.On("order.created", func(ctx context.Context, e *receiver.Event) error {
+		got = e.ID
+		return nil
+	})
+
+	app := fiber.New()
+	app.Post("/webhook", Handler(rcv))
+	app.Post("/verified", Verify(rcv), func(c *fiber.Ctx) error {
+		e, _ := EventFrom(c)
+		return c.SendString(e.ID)
+	})
+
+	key, _ := signing.DecodeSecret(testSecret)
+	body := `{"event":"order.created","data":{}}`
+	tokenRequest := func(path string) *http.Request {
+		now := time.Now()
+		token := signing.SignToken(key, "msg_token", now, now.Add(time.Minute), []byte(body))
+		return httptest.NewRequest(http.MethodPost, path+"?"+signing.TokenParam+"="+token, strings.NewReader(body))
+	}
+
+	resp, err := app.Test(tokenRequest("/webhook"))
+	if err != nil || resp.StatusCode != http.StatusOK || got != "msg_token" {
+		t.Errorf("Expected the token-only delivery handled as msg_token, got %v and %q", err, got)
+	}
+
+	resp, err = app.Test(tokenRequest("/verified"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(id) != "msg_token" {
+		t.Errorf("Expected Verify to accept the token, got %d: %s", resp.StatusCode, id)
+	}
+}
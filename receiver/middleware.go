@@ -0,0 +1,70 @@
+package receiver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Middleware wraps a verified-event Handler with cross-cutting behaviour
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the chain; the first registered runs outermost
+func (r *Receiver) Use(mw ...Middleware) *Receiver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw...)
+	return r
+}
+
+// chainMiddleware wraps h so that mw[0] is the outermost layer
+func chainMiddleware(h Handler, mw []Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// Logging logs every dispatched event with its duration and outcome
+func Logging(l *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			start := time.Now()
+			err := next(ctx, e)
+			attrs := []any{"event", e.Type, "msgId", e.ID, "attempt", e.Attempt, "duration", time.Since(start)}
+			if err != nil {
+				l.Warn("receiver: event failed", append(attrs, "error", err)...)
+				return err
+			}
+			l.Info("receiver: event handled", attrs...)
+			return nil
+		}
+	}
+}
+
+// Observe reports the duration and outcome of every event, e.g. to a metrics sink
+func Observe(fn func(e *Event, d time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			start := time.Now()
+			err := next(ctx, e)
+			fn(e, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// Recover converts handler panics into errors
+func Recover() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) (err error) {
+			defer func() {
+				if v := recover(); v != nil {
+					err = fmt.Errorf("receiver: handler panic: %v", v)
+				}
+			}()
+			return next(ctx, e)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tenantKey struct{}
+
+func TestUse_Order(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, e *Event) error {
+				order = append(order, name+">")
+				err := next(ctx, e)
+				order = append(order, "<"+name)
+				return err
+			}
+		}
+	}
+
+	rcv.Use(trace("outer"), trace("inner"))
+	rcv.On("a.b", func(ctx context.Context, e *Event) error {
+		order = append(order, "handler")
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+
+	want := "outer> inner> handler <inner <outer"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("Expected order '%s', got '%s'", want, got)
+	}
+}
+
+func TestUse_ContextValues(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	// Tenant extraction from a header, visible to handlers through the context
+	rcv.Use(func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			return next(context.WithValue(ctx, tenantKey{}, e.Header.Get("X-Tenant")), e)
+		}
+	})
+
+	var tenant string
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		tenant, _ = ctx.Value(tenantKey{}).(string)
+		return nil
+	})
+
+	req := signedRequest(t, `{"event":"a.b","data":{}}`)
+	req.Header.Set("X-Tenant", "acme")
+	rcv.ServeHTTP(httptest.NewRecorder(), req)
+
+	if tenant != "acme" {
+		t.Errorf("Expected tenant 'acme', got '%s'", tenant)
+	}
+}
+
+func TestUse_ShortCircuit(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	denied := errors.New("denied")
+	rcv.Use(func(next Handler) Handler {
+		return func(ctx context.Context, e *Event) error {
+			return denied
+		}
+	})
+
+	called := false
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		called = true
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+
+	if called {
+		t.Error("Expected handler not to run")
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestRecoverAndObserve(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	var observed error
+	rcv.Use(Observe(func(e *Event, d time.Duration, err error) {
+		observed = err
+	}), Recover())
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+
+	if observed == nil || !strings.Contains(observed.Error(), "panic") {
+		t.Errorf("Expected observed panic error, got %v", observed)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
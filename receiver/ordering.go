@@ -0,0 +1,171 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOutOfOrder is returned, wrapped by RetryAfter, for an event that arrives
+// ahead of a gap in its ordering key's sequence; the sender redelivers it
+var ErrOutOfOrder = errors.New("receiver: event ahead of a sequence gap")
+
+type orderingConfig struct {
+	gapTimeout  time.Duration
+	idleTimeout time.Duration
+	onSkip      func(key string, from, to uint64)
+}
+
+// OrderingOption configures NewOrderingBuffer
+type OrderingOption func(*orderingConfig)
+
+// WithGapTimeout sets how long events wait for a missing predecessor before
+// the gap is skipped (default: 30s). Senders must keep retrying for longer.
+func WithGapTimeout(d time.Duration) OrderingOption {
+	return func(c *orderingConfig) {
+		c.gapTimeout = d
+	}
+}
+
+// WithIdleTimeout forgets ordering keys that have seen no event for d
+// (default: 1h). A forgotten key's next event waits out the gap timeout.
+func WithIdleTimeout(d time.Duration) OrderingOption {
+	return func(c *orderingConfig) {
+		c.idleTimeout = d
+	}
+}
+
+// WithOnGapSkipped calls fn with the missing sequence numbers, from through
+// to, whenever a gap times out
+func WithOnGapSkipped(fn func(key string, from, to uint64)) OrderingOption {
+	return func(c *orderingConfig) {
+		c.onSkip = fn
+	}
+}
+
+// orderingStream tracks one ordering key
+type orderingStream struct {
+	mu       sync.Mutex
+	next     uint64    // Sequence the handler expects next
+	gapSince time.Time // When an event first arrived ahead of next; zero without a gap
+	lowest   uint64    // Lowest sequence refused since gapSince
+	lowestAt time.Time // When lowest was last refused
+	users    int       // Handle calls holding the stream; guarded by OrderingBuffer.mu
+	lastSeen time.Time // Guarded by OrderingBuffer.mu
+}
+
+// OrderingBuffer runs a handler in sequence order per ordering key. Senders
+// number events from 1 with EventBuilder.WithSequence; events without a key
+// or sequence pass straight through. No event is kept by the buffer: one that
+// cannot run yet is refused, so it stays with the sender until it can.
+type OrderingBuffer struct {
+	fn     Handler
+	config orderingConfig
+
+	mu      sync.Mutex
+	streams map[string]*orderingStream
+	swept   time.Time
+}
+
+// NewOrderingBuffer wraps fn so each ordering key's events reach it in order
+func NewOrderingBuffer(fn Handler, opts ...OrderingOption) *OrderingBuffer {
+	cfg := orderingConfig{gapTimeout: 30 * time.Second, idleTimeout: time.Hour}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &OrderingBuffer{fn: fn, config: cfg, streams: make(map[string]*orderingStream), swept: time.Now()}
+}
+
+// Handle is the Handler to register with the receiver. The expected event runs
+// immediately and its error is returned, so the sender retries it. An event
+// ahead of a gap is refused with ErrOutOfOrder and a Retry-After of the time
+// left on the gap, so the sender redelivers it. Once the gap has timed out,
+// the lowest event still being redelivered skips it. Events behind the stream,
+// redeliveries and events whose gap was skipped, are acknowledged and dropped.
+func (b *OrderingBuffer) Handle(ctx context.Context, e *Event) error {
+	if e.OrderingKey == "" || e.Sequence == 0 {
+		return b.fn(ctx, e)
+	}
+	s := b.acquire(e.OrderingKey)
+	defer b.done(s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Sequence < s.next {
+		return nil
+	}
+	if e.Sequence > s.next {
+		now := time.Now()
+		if !b.gapTimedOut(s, e.Sequence, now) {
+			wait := max(b.config.gapTimeout-now.Sub(s.gapSince), time.Second)
+			return RetryAfter(ErrOutOfOrder, wait)
+		}
+	}
+
+	if err := b.fn(ctx, e); err != nil {
+		return err
+	}
+	if e.Sequence > s.next && b.config.onSkip != nil {
+		b.config.onSkip(e.OrderingKey, s.next, e.Sequence-1)
+	}
+	s.next = e.Sequence + 1
+	s.gapSince, s.lowest = time.Time{}, 0
+	return nil
+}
+
+// Streams returns the number of ordering keys being tracked
+func (b *OrderingBuffer) Streams() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.streams)
+}
+
+// gapTimedOut records an event refused ahead of the gap and reports whether it
+// may skip the gap instead: the gap is older than the gap timeout and no lower
+// sequence is still being redelivered. s.mu must be held
+func (b *OrderingBuffer) gapTimedOut(s *orderingStream, seq uint64, now time.Time) bool {
+	switch {
+	case s.gapSince.IsZero():
+		s.gapSince, s.lowest, s.lowestAt = now, seq, now
+		return false
+	case seq <= s.lowest:
+		s.lowest, s.lowestAt = seq, now
+	case now.Sub(s.lowestAt) > 2*b.config.gapTimeout:
+		// The sender gave up on the lowest event; let the next one through
+		s.lowest, s.lowestAt = seq, now
+	}
+	return seq == s.lowest && now.Sub(s.gapSince) >= b.config.gapTimeout
+}
+
+// acquire returns key's stream, creating it, and evicts idle streams
+func (b *OrderingBuffer) acquire(key string) *orderingStream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if now.Sub(b.swept) >= b.config.idleTimeout {
+		for k, s := range b.streams {
+			if s.users == 0 && now.Sub(s.lastSeen) >= b.config.idleTimeout {
+				delete(b.streams, k)
+			}
+		}
+		b.swept = now
+	}
+
+	s, ok := b.streams[key]
+	if !ok {
+		s = &orderingStream{next: 1}
+		b.streams[key] = s
+	}
+	s.users++
+	s.lastSeen = now
+	return s
+}
+
+// done releases a stream taken by acquire
+func (b *OrderingBuffer) done(s *orderingStream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s.users--
+	s.lastSeen = time.Now()
+}
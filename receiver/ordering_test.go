@@ -0,0 +1,163 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// recorder collects the sequence numbers a handler saw
+type recorder struct {
+	mu   sync.Mutex
+	seqs []uint64
+	fail map[uint64]bool
+}
+
+func (r *recorder) handle(ctx context.Context, e *Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail[e.Sequence] {
+		delete(r.fail, e.Sequence)
+		return errors.New("temporary failure")
+	}
+	r.seqs = append(r.seqs, e.Sequence)
+	return nil
+}
+
+func (r *recorder) seen() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.seqs)
+}
+
+func sequenced(key string, seq uint64) *Event {
+	return &Event{ID: "msg_" + key, Type: "order.updated", OrderingKey: key, Sequence: seq}
+}
+
+func TestOrderingBuffer_RefusesEventsAheadOfGap(t *testing.T) {
+	rec := &recorder{}
+	buf := NewOrderingBuffer(rec.handle)
+	ctx := context.Background()
+
+	for _, seq := range []uint64{3, 2} {
+		err := buf.Handle(ctx, sequenced("cust-1", seq))
+		var ra *RetryAfterError
+		if !errors.As(err, &ra) || !errors.Is(err, ErrOutOfOrder) || ra.After <= 0 {
+			t.Fatalf("Expected event %d refused with a retry delay, got %v", seq, err)
+		}
+	}
+	buf.Handle(ctx, sequenced("cust-2", 1))
+	if got := rec.seen(); !slices.Equal(got, []uint64{1}) {
+		t.Fatalf("Expected cust-1 held back, got %v", got)
+	}
+
+	// The sender redelivers the refused events once their predecessor has run
+	for _, seq := range []uint64{1, 3, 2, 3, 2} {
+		buf.Handle(ctx, sequenced("cust-1", seq))
+	}
+	if got := rec.seen(); !slices.Equal(got, []uint64{1, 1, 2, 3}) {
+		t.Errorf("Expected cust-1 run in order, got %v", got)
+	}
+}
+
+func TestOrderingBuffer_GapTimeout(t *testing.T) {
+	rec := &recorder{}
+	var skipped [][2]uint64
+	buf := NewOrderingBuffer(rec.handle, WithGapTimeout(20*time.Millisecond), WithOnGapSkipped(func(key string, from, to uint64) {
+		skipped = append(skipped, [2]uint64{from, to})
+	}))
+	ctx := context.Background()
+
+	buf.Handle(ctx, sequenced("cust-1", 1))
+	buf.Handle(ctx, sequenced("cust-1", 5))
+	buf.Handle(ctx, sequenced("cust-1", 4))
+	time.Sleep(30 * time.Millisecond)
+
+	if err := buf.Handle(ctx, sequenced("cust-1", 5)); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("Expected 5 to wait for the lower 4, got %v", err)
+	}
+	if err := buf.Handle(ctx, sequenced("cust-1", 4)); err != nil {
+		t.Fatalf("Expected 4 to skip the timed out gap, got %v", err)
+	}
+	buf.Handle(ctx, sequenced("cust-1", 5))
+	if got := rec.seen(); !slices.Equal(got, []uint64{1, 4, 5}) || !slices.Equal(skipped, [][2]uint64{{2, 3}}) {
+		t.Errorf("Expected 2-3 skipped and 4, 5 run, got %v with skips %v", got, skipped)
+	}
+
+	buf.Handle(ctx, sequenced("cust-1", 2)) // Too late
+	if got := rec.seen(); len(got) != 3 {
+		t.Errorf("Expected a late event to be dropped, got %v", got)
+	}
+}
+
+func TestOrderingBuffer_Errors(t *testing.T) {
+	rec := &recorder{fail: map[uint64]bool{1: true}}
+	buf := NewOrderingBuffer(rec.handle)
+	ctx := context.Background()
+
+	if err := buf.Handle(ctx, sequenced("k", 1)); err == nil {
+		t.Fatal("Expected the in-order event's error to reach the sender")
+	}
+	if err := buf.Handle(ctx, sequenced("k", 2)); !errors.Is(err, ErrOutOfOrder) {
+		t.Errorf("Expected the stream not to advance past a failed event, got %v", err)
+	}
+	if err := buf.Handle(ctx, sequenced("k", 1)); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	buf.Handle(ctx, sequenced("k", 2))
+	if got := rec.seen(); !slices.Equal(got, []uint64{1, 2}) {
+		t.Errorf("Expected 1 then 2, got %v", got)
+	}
+}
+
+func TestOrderingBuffer_IdleTimeout(t *testing.T) {
+	rec := &recorder{}
+	buf := NewOrderingBuffer(rec.handle, WithIdleTimeout(10*time.Millisecond))
+	ctx := context.Background()
+
+	buf.Handle(ctx, sequenced("cust-1", 1))
+	buf.Handle(ctx, sequenced("cust-2", 1))
+	time.Sleep(20 * time.Millisecond)
+	buf.Handle(ctx, sequenced("cust-3", 1))
+	if n := buf.Streams(); n != 1 {
+		t.Errorf("Expected idle keys evicted, got %d streams", n)
+	}
+}
+
+func TestOrderingBuffer_Unsequenced(t *testing.T) {
+	rec := &recorder{}
+	buf := NewOrderingBuffer(rec.handle)
+	buf.Handle(context.Background(), &Event{ID: "msg_1", OrderingKey: "k"})
+	buf.Handle(context.Background(), &Event{ID: "msg_2", Sequence: 7})
+	if got := rec.seen(); !slices.Equal(got, []uint64{0, 7}) {
+		t.Errorf("Expected unkeyed or unsequenced events to pass through, got %v", got)
+	}
+}
+
+func TestReceiver_OrderingFields(t *testing.T) {
+	var got *Event
+	rcv, _ := New(testSecret)
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		got = e
+		return nil
+	})
+
+	req := signedRequest(t, `{"event":"order.updated","timestamp":"2024-01-15T10:30:00Z","data":{},"sequence":7}`)
+	req.Header.Set(webhook.OrderingKeyHeader, "cust-1")
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || got == nil {
+		t.Fatalf("Expected the event to be handled, got %d", rec.Code)
+	}
+	if got.OrderingKey != "cust-1" || got.Sequence != 7 {
+		t.Errorf("Expected ordering key cust-1 and sequence 7, got %q and %d", got.OrderingKey, got.Sequence)
+	}
+}
@@ -0,0 +1,101 @@
+package receiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TxHandler processes a verified event inside a database transaction
+type TxHandler func(ctx context.Context, tx *sql.Tx, e *Event) error
+
+type outboxConfig struct {
+	table  string
+	dollar bool
+}
+
+// OutboxOption configures HandleWithOutbox
+type OutboxOption func(*outboxConfig)
+
+// WithOutboxTable sets the processed-messages table (default: hookshot_processed_messages)
+func WithOutboxTable(name string) OutboxOption {
+	return func(c *outboxConfig) {
+		c.table = name
+	}
+}
+
+// WithDollarPlaceholders uses $1-style placeholders, e.g. for PostgreSQL
+func WithDollarPlaceholders() OutboxOption {
+	return func(c *outboxConfig) {
+		c.dollar = true
+	}
+}
+
+func newOutboxConfig(opts []OutboxOption) outboxConfig {
+	cfg := outboxConfig{table: "hookshot_processed_messages"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func (c outboxConfig) arg(n int) string {
+	if c.dollar {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// EnsureOutboxTable creates the processed-messages table if it does not exist
+func EnsureOutboxTable(ctx context.Context, db *sql.DB, opts ...OutboxOption) error {
+	cfg := newOutboxConfig(opts)
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+cfg.table+
+		" (message_id VARCHAR(255) PRIMARY KEY, processed_at TIMESTAMP NOT NULL)")
+	return err
+}
+
+// HandleWithOutbox runs fn at most once per message. In one transaction it
+// skips messages already recorded in the processed-messages table, records the
+// message ID and runs fn; fn's writes through tx commit together with the record.
+// Concurrent redeliveries race on the table's primary key, so one fails and the
+// sender retries it into the skip path.
+func HandleWithOutbox(db *sql.DB, fn TxHandler, opts ...OutboxOption) Handler {
+	cfg := newOutboxConfig(opts)
+	exists := "SELECT 1 FROM " + cfg.table + " WHERE message_id = " + cfg.arg(1)
+	insert := "INSERT INTO " + cfg.table + " (message_id, processed_at) VALUES (" + cfg.arg(1) + ", " + cfg.arg(2) + ")"
+
+	return func(ctx context.Context, e *Event) error {
+		key := e.ID
+		if e.Provider != "" {
+			key = e.Provider + ":" + e.ID
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("receiver: outbox: %w", err)
+		}
+		defer tx.Rollback()
+
+		var one int
+		switch err := tx.QueryRowContext(ctx, exists, key).Scan(&one); err {
+		case nil:
+			return nil // Already processed
+		case sql.ErrNoRows:
+		default:
+			return fmt.Errorf("receiver: outbox: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, key, time.Now().UTC()); err != nil {
+			return fmt.Errorf("receiver: outbox: %w", err)
+		}
+		if err := fn(ctx, tx, e); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("receiver: outbox: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,182 @@
+package receiver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is an in-memory stand-in for a SQL database with transactional
+// processed-message records and a side-effects counter
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[string]bool
+	effects int
+	queries []string
+}
+
+func (d *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: d}, nil }
+func (d *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	db *fakeDB
+	tx *fakeTx
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.tx = &fakeTx{conn: c, rows: map[string]bool{}}
+	return c.tx, nil
+}
+
+type fakeTx struct {
+	conn    *fakeConn
+	rows    map[string]bool
+	effects int
+}
+
+func (t *fakeTx) Commit() error {
+	db := t.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for k := range t.rows {
+		if db.rows[k] {
+			return errors.New("UNIQUE constraint failed")
+		}
+		db.rows[k] = true
+	}
+	db.effects += t.effects
+	t.conn.tx = nil
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.conn.tx = nil
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db, tx := s.conn.db, s.conn.tx
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, s.query)
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT INTO effects"):
+		tx.effects++
+	case strings.HasPrefix(s.query, "INSERT INTO"):
+		key := args[0].(string)
+		if db.rows[key] || tx.rows[key] {
+			return nil, errors.New("UNIQUE constraint failed")
+		}
+		tx.rows[key] = true
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.queries = append(db.queries, s.query)
+	return &fakeRows{found: db.rows[args[0].(string)]}, nil
+}
+
+type fakeRows struct{ found bool }
+
+func (r *fakeRows) Columns() []string { return []string{"1"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if !r.found {
+		return io.EOF
+	}
+	r.found = false
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestHandleWithOutbox(t *testing.T) {
+	fake := &fakeDB{rows: map[string]bool{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	if err := EnsureOutboxTable(context.Background(), db); err != nil {
+		t.Fatalf("EnsureOutboxTable() error = %v", err)
+	}
+
+	fail := true
+	h := HandleWithOutbox(db, func(ctx context.Context, tx *sql.Tx, e *Event) error {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO effects (order_id) VALUES (?)", e.ID); err != nil {
+			return err
+		}
+		if fail {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	e := &Event{ID: "msg_1", Type: "order.created"}
+
+	// A failing handler rolls back both its writes and the processed record
+	if err := h(context.Background(), e); err == nil {
+		t.Fatal("Expected handler error")
+	}
+	if fake.effects != 0 || fake.rows["msg_1"] {
+		t.Fatalf("Expected rollback, got effects=%d rows=%v", fake.effects, fake.rows)
+	}
+
+	fail = false
+	for i := 0; i < 3; i++ {
+		if err := h(context.Background(), e); err != nil {
+			t.Fatalf("Delivery %d: unexpected error %v", i+1, err)
+		}
+	}
+	if fake.effects != 1 {
+		t.Errorf("Expected side effects exactly once, got %d", fake.effects)
+	}
+
+	// Fan-in events are keyed by provider so IDs cannot collide across providers
+	if err := h(context.Background(), &Event{ID: "msg_1", Provider: "github"}); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.rows["github:msg_1"] || fake.effects != 2 {
+		t.Errorf("Expected provider-scoped record, got rows=%v effects=%d", fake.rows, fake.effects)
+	}
+}
+
+func TestHandleWithOutbox_Options(t *testing.T) {
+	fake := &fakeDB{rows: map[string]bool{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	h := HandleWithOutbox(db, func(context.Context, *sql.Tx, *Event) error { return nil },
+		WithOutboxTable("inbox"), WithDollarPlaceholders())
+	if err := h(context.Background(), &Event{ID: "msg_1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"SELECT 1 FROM inbox WHERE message_id = $1",
+		"INSERT INTO inbox (message_id, processed_at) VALUES ($1, $2)",
+	}
+	if strings.Join(fake.queries, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected queries %q, got %q", want, fake.queries)
+	}
+}
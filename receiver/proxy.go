@@ -0,0 +1,76 @@
+package receiver
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// Identity headers added to proxied requests. Inbound copies are stripped so
+// a client cannot claim verification by sending them itself.
+const (
+	VerifiedHeader  = "X-Webhook-Verified"
+	EventIDHeader   = "X-Webhook-Id"
+	EventTypeHeader = "X-Webhook-Event"
+	TenantHeader    = "X-Webhook-Tenant"
+
+	CorrelationHeader = "X-Webhook-Correlation-Id" // Chain to pass on; the event ID when it starts one
+)
+
+// Proxy returns a handler that verifies each request and forwards the raw,
+// unmodified body to upstream with identity headers, so services behind it get
+// signature verification without code changes. Registered handlers are not run.
+func (r *Receiver) Proxy(upstream *url.URL) http.Handler {
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			pr.SetXForwarded()
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := r.CheckSource(req.RemoteAddr); err != nil {
+			res := ErrorResult(err)
+			writeJSON(w, res.Status, res.Body)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, r.config.MaxBodySize))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				res := ErrorResult(ErrBodyTooLarge)
+				writeJSON(w, res.Status, res.Body)
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "Failed to read body"})
+			return
+		}
+
+		for _, h := range []string{VerifiedHeader, EventIDHeader, EventTypeHeader, TenantHeader, CorrelationHeader} {
+			req.Header.Del(h)
+		}
+
+		event, err := r.Verify(body, req.Header)
+		if err != nil {
+			r.logger.Warn("receiver: proxy rejected request", "error", err)
+			res := ErrorResult(err)
+			writeJSON(w, res.Status, res.Body)
+			return
+		}
+
+		req.Header.Set(VerifiedHeader, "true")
+		req.Header.Set(EventIDHeader, event.ID)
+		req.Header.Set(EventTypeHeader, event.Type)
+		req.Header.Set(CorrelationHeader, event.Next().CorrelationID)
+		if event.Tenant != "" {
+			req.Header.Set(TenantHeader, event.Tenant)
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		rp.ServeHTTP(w, req)
+	})
+}
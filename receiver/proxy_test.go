@@ -0,0 +1,75 @@
+package receiver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestReceiver_Proxy(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("queued"))
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL)
+	rcv, _ := New(testSecret, WithTenantFunc(TenantFromHeader("X-Tenant")))
+	proxy := rcv.Proxy(target)
+
+	body := `{"event":"order.created","data":{"id":"1"}}`
+	req := signedRequest(t, body)
+	req.Header.Set("X-Tenant", "acme")
+	rec := httptest.NewRecorder()
+	proxy.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "queued" {
+		t.Fatalf("Expected upstream response, got %d %q", rec.Code, rec.Body.String())
+	}
+	if gotBody != body {
+		t.Errorf("Expected raw body %s, got %s", body, gotBody)
+	}
+	for h, want := range map[string]string{
+		VerifiedHeader:  "true",
+		EventIDHeader:   "msg_test",
+		EventTypeHeader: "order.created",
+		TenantHeader:    "acme",
+
+		CorrelationHeader: "msg_test",
+	} {
+		if v := got.Header.Get(h); v != want {
+			t.Errorf("Expected %s %q, got %q", h, want, v)
+		}
+	}
+}
+
+func TestReceiver_Proxy_Rejects(t *testing.T) {
+	called := false
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL)
+	rcv, _ := New(testSecret)
+
+	req := signedRequest(t, `{"event":"order.created","data":{}}`)
+	req.Header.Set("svix-signature", "v1,forged")
+	req.Header.Set(VerifiedHeader, "true")
+	rec := httptest.NewRecorder()
+	rcv.Proxy(target).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if called {
+		t.Error("Expected unverified request not to reach upstream")
+	}
+}
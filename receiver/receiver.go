@@ -0,0 +1,501 @@
+package receiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/signing"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+// Sentinel errors for error inspection
+var (
+	ErrMissingHeaders  = errors.New("receiver: missing signature headers")
+	ErrVerification    = errors.New("receiver: verification failed")
+	ErrInvalidPayload  = errors.New("receiver: invalid payload")
+	ErrBodyTooLarge    = errors.New("receiver: body too large")
+	ErrBodyAltered     = errors.New("receiver: body does not match its content digest")
+	ErrForbiddenSource = errors.New("receiver: source address not allowed")
+)
+
+// Config holds the receiver configuration
+type Config struct {
+	Secret           string                        // Signing secret (whsec_...) or Ed25519 public key (whpk_...)
+	MaxBodySize      int64                         // Max accepted body size in bytes (default: 1MiB)
+	Logger           *slog.Logger                  // Optional structured logger
+	OnHandlerError   func(err error, event string) // Optional callback for failed handlers
+	Tolerance        time.Duration                 // Accepted timestamp drift (default: 5m)
+	Headers          []signing.HeaderNames         // Accepted signature header sets, in order of preference
+	TenantFunc       func(e *Event) string         // Optional tenant resolver
+	EndpointURL      string                        // Public URL senders target, required to verify v1h signatures
+	SignedHeaders    []string                      // Headers a v1h signature must cover
+	MaxDecodedSize   int64                         // Max decompressed body size for gzip or zstd requests (default: MaxBodySize)
+	SignEncoded      bool                          // Signatures cover the compressed bytes rather than the decoded body
+	QueryTokens      bool                          // Also accept hookshot_token query-parameter JWTs in place of signature headers
+	ContentDigest    bool                          // Require Webhook-Content-SHA256 and check it against the body
+	AllowedCIDRs     []string                      // Source ranges accepted; empty with no AllowlistURL allows all
+	AllowlistURL     string                        // Source of additional allowed ranges
+	AllowlistRefresh time.Duration                 // How often AllowlistURL is re-fetched (zero: only on New)
+	SecretEncoding   signing.SecretEncoding        // How Secret encodes its HMAC key (default: base64)
+}
+
+// Option is a functional option for configuring the Receiver
+type Option func(*Config)
+
+// WithMaxBodySize sets the maximum accepted request body size
+func WithMaxBodySize(n int64) Option {
+	return func(c *Config) {
+		c.MaxBodySize = n
+	}
+}
+
+// WithTolerance sets how far the signed timestamp may drift from the local clock
+func WithTolerance(d time.Duration) Option {
+	return func(c *Config) {
+		c.Tolerance = d
+	}
+}
+
+// WithAcceptedHeaders restricts which signature header sets are accepted, in
+// order of preference, and accepts custom names such as X-Signature; by default
+// both svix-* and Standard Webhooks headers are
+func WithAcceptedHeaders(names ...signing.HeaderNames) Option {
+	return func(c *Config) {
+		c.Headers = names
+	}
+}
+
+// WithSignedHeaders requires a v1h signature covering endpointURL, the URL
+// senders deliver to, and each of the required headers. Plain v1 signatures are
+// then rejected. It requires an HMAC (whsec_) secret.
+func WithSignedHeaders(endpointURL string, required ...string) Option {
+	return func(c *Config) {
+		c.EndpointURL = endpointURL
+		c.SignedHeaders = required
+	}
+}
+
+// WithContentDigest requires the Webhook-Content-SHA256 header and checks it
+// before the signature, so a body re-encoded by a proxy fails with
+// ErrBodyAltered rather than a generic verification error
+func WithContentDigest() Option {
+	return func(c *Config) {
+		c.ContentDigest = true
+	}
+}
+
+// WithTenantFunc sets how the tenant of an event is resolved, e.g. TenantFromHeader("X-Tenant-ID")
+func WithTenantFunc(fn func(e *Event) string) Option {
+	return func(c *Config) {
+		c.TenantFunc = fn
+	}
+}
+
+// WithLogger sets a custom structured logger
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// WithOnHandlerError sets a callback invoked for every failed handler
+func WithOnHandlerError(fn func(err error, event string)) Option {
+	return func(c *Config) {
+		c.OnHandlerError = fn
+	}
+}
+
+// Event is a verified inbound webhook
+type Event struct {
+	ID        string          // Message ID from the svix-id (or webhook-id) header
+	Type      string          // Event name
+	Timestamp time.Time       // Payload timestamp
+	Data      json.RawMessage // Raw event data
+	Attempt   int             // Sender's attempt number from Webhook-Attempt, 0 if absent
+	Tenant    string          // Tenant resolved by the configured tenant function
+	Provider  string          // Third-party provider for fan-in events, empty for Hookshot deliveries
+
+	CorrelationID string      // Chain the event belongs to, empty if the sender set none
+	CausationID   string      // Message ID of the event that caused this one
+	OrderingKey   string      // From Webhook-Ordering-Key, empty if the sender set none
+	Sequence      uint64      // Position among events sharing OrderingKey, 0 if unsequenced
+	Header        http.Header // Inbound request headers
+	Body          []byte      // Raw verified body
+}
+
+// Next returns the correlation for events caused by e: the same chain, or a
+// new one rooted at e, with e as the cause
+func (e *Event) Next() webhook.Correlation {
+	corr := webhook.Correlation{CorrelationID: e.CorrelationID, CausationID: e.ID}
+	if corr.CorrelationID == "" {
+		corr.CorrelationID = e.ID
+	}
+	return corr
+}
+
+// Decode unmarshals the event data into v
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Handler processes a verified event
+type Handler func(ctx context.Context, e *Event) error
+
+// Receiver verifies inbound webhooks and dispatches them to event handlers
+type Receiver struct {
+	config    Config
+	verifier  signing.Verifier
+	headerKey []byte // HMAC key for v1h signatures, set when EndpointURL is
+	tokenKey  []byte // HMAC key for query tokens, set when QueryTokens is
+	logger    *slog.Logger
+	allowlist *allowlist // Nil when every source is allowed
+
+	mu         sync.RWMutex
+	handlers   map[string][]Handler
+	wildcard   []Handler
+	middleware []Middleware
+}
+
+// New creates a new receiver using functional options
+func New(secret string, opts ...Option) (*Receiver, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("receiver: secret is required")
+	}
+
+	cfg := Config{
+		Secret:      secret,
+		MaxBodySize: 1 << 20,
+		Tolerance:   5 * time.Minute,
+		Headers:     []signing.HeaderNames{signing.SvixHeaders, signing.StandardHeaders},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.MaxDecodedSize == 0 {
+		cfg.MaxDecodedSize = cfg.MaxBodySize
+	}
+	if cfg.SecretEncoding != "" && cfg.SecretEncoding != signing.EncodingBase64 {
+		secret, err := signing.NormalizeSecret(cfg.Secret, cfg.SecretEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("receiver: %w", err)
+		}
+		cfg.Secret = secret
+	}
+
+	if len(cfg.Headers) == 0 {
+		return nil, fmt.Errorf("receiver: at least one header set is required")
+	}
+	for _, names := range cfg.Headers {
+		if err := names.Validate(); err != nil {
+			return nil, fmt.Errorf("receiver: %w", err)
+		}
+	}
+
+	verifier, err := signing.NewVerifier(cfg.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("receiver: failed to create verifier: %w", err)
+	}
+
+	tokenKey, err := newTokenKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var headerKey []byte
+	if cfg.EndpointURL != "" {
+		if strings.HasPrefix(cfg.Secret, signing.PublicKeyPrefix) || strings.HasPrefix(cfg.Secret, signing.SecretKeyPrefix) {
+			return nil, fmt.Errorf("receiver: signed headers require an HMAC (whsec_) secret")
+		}
+		if headerKey, err = signing.DecodeSecret(cfg.Secret); err != nil {
+			return nil, fmt.Errorf("receiver: failed to create verifier: %w", err)
+		}
+	}
+
+	allow, err := newAllowlist(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := redact.Logger(cfg.Logger)
+
+	return &Receiver{
+		allowlist: allow,
+		config:    cfg,
+		verifier:  verifier,
+		headerKey: headerKey,
+		tokenKey:  tokenKey,
+		logger:    logger,
+		handlers:  make(map[string][]Handler),
+	}, nil
+}
+
+// On registers a handler for a single event type
+func (r *Receiver) On(event string, h Handler) *Receiver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[event] = append(r.handlers[event], h)
+	return r
+}
+
+// OnAll registers a handler for every event type
+func (r *Receiver) OnAll(h Handler) *Receiver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wildcard = append(r.wildcard, h)
+	return r
+}
+
+// Verify checks the signature headers and decodes the body into an Event. A
+// gzip or zstd Content-Encoding is decoded before verification, or after it
+// with WithEncodedSignatures; Event.Body holds the decoded bytes.
+func (r *Receiver) Verify(body []byte, header http.Header) (*Event, error) {
+	enc := contentEncoding(header)
+	signed := body
+	if enc != "" && !r.config.SignEncoded {
+		decoded, err := decodeBody(enc, body, r.config.MaxDecodedSize)
+		if err != nil {
+			return nil, err
+		}
+		signed, body = decoded, decoded
+	}
+
+	var id, ts, sig string
+	found := false
+	for _, names := range r.config.Headers {
+		if id, ts, sig, found = names.Get(header); found {
+			break
+		}
+	}
+	if !found {
+		return nil, ErrMissingHeaders
+	}
+	if r.config.ContentDigest {
+		digest := header.Get(signing.ContentDigestHeader)
+		if digest == "" {
+			return nil, fmt.Errorf("%w: %s", ErrMissingHeaders, signing.ContentDigestHeader)
+		}
+		if digest != signing.ContentDigest(signed) {
+			return nil, ErrBodyAltered
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp header", ErrVerification)
+	}
+	timestamp := time.Unix(unix, 0)
+
+	if age := time.Since(timestamp); age > r.config.Tolerance {
+		return nil, fmt.Errorf("%w: message timestamp too old", ErrVerification)
+	} else if age < -r.config.Tolerance {
+		return nil, fmt.Errorf("%w: message timestamp too new", ErrVerification)
+	}
+
+	if r.headerKey != nil {
+		err = signing.VerifyV1H(r.headerKey, id, timestamp, r.config.EndpointURL, header, signed, sig, r.config.SignedHeaders)
+	} else {
+		err = r.verifier.Verify(id, timestamp, signed, sig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerification, redact.Error(err))
+	}
+
+	if enc != "" && r.config.SignEncoded {
+		if body, err = decodeBody(enc, body, r.config.MaxDecodedSize); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.newEvent(id, body, header)
+}
+
+// newEvent decodes a verified body into an Event
+func (r *Receiver) newEvent(id string, body []byte, header http.Header) (*Event, error) {
+	var payload struct {
+		Event     string          `json:"event"`
+		Timestamp time.Time       `json:"timestamp"`
+		Data      json.RawMessage `json:"data"`
+
+		CorrelationID string `json:"correlation_id"`
+		CausationID   string `json:"causation_id"`
+		Sequence      uint64 `json:"sequence"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	attempt, _ := strconv.Atoi(header.Get(webhook.AttemptHeader))
+
+	e := &Event{
+		ID:        id,
+		Type:      payload.Event,
+		Timestamp: payload.Timestamp,
+		Data:      payload.Data,
+		Attempt:   attempt,
+		Header:    header,
+		Body:      body,
+
+		CorrelationID: payload.CorrelationID,
+		CausationID:   payload.CausationID,
+		OrderingKey:   header.Get(webhook.OrderingKeyHeader),
+		Sequence:      payload.Sequence,
+	}
+	if r.config.TenantFunc != nil {
+		e.Tenant = r.config.TenantFunc(e)
+	}
+	return e, nil
+}
+
+// Dispatch runs the middleware chain and every handler registered for the event.
+// The event's correlation data is available to both through the *FromContext accessors.
+func (r *Receiver) Dispatch(ctx context.Context, e *Event) error {
+	r.mu.RLock()
+	chain := r.middleware
+	r.mu.RUnlock()
+
+	return chainMiddleware(r.dispatch, chain)(withEvent(ctx, e), e)
+}
+
+// Result is the outcome of processing an inbound webhook
+type Result struct {
+	Status int            // HTTP status to answer with
+	Body   map[string]any // JSON response body
+	Event  *Event         // Verified event, nil when verification failed
+	Err    error          // Verification or handler error
+	Header http.Header    // Extra response headers, e.g. Retry-After
+}
+
+// Process verifies a raw body and its headers and dispatches the resulting
+// event. It is the framework-agnostic core shared by ServeHTTP and adapters.
+func (r *Receiver) Process(ctx context.Context, body []byte, header http.Header) Result {
+	if int64(len(body)) > r.config.MaxBodySize {
+		return ErrorResult(ErrBodyTooLarge)
+	}
+
+	event, err := r.Verify(body, header)
+	if err != nil {
+		return ErrorResult(err)
+	}
+
+	return DispatchResult(event, r.Dispatch(ctx, event))
+}
+
+// DispatchResult maps the outcome of dispatching a verified event to the
+// response the receiver answers with
+func DispatchResult(event *Event, err error) Result {
+	if err != nil {
+		var ra *RetryAfterError
+		if errors.As(err, &ra) {
+			return Result{
+				Status: http.StatusServiceUnavailable,
+				Header: http.Header{"Retry-After": {retryAfterSeconds(ra.After)}, webhook.BackoffHeader: {retryAfterSeconds(ra.After)}},
+				Body:   map[string]any{"error": "Temporarily unavailable", "msgId": event.ID},
+				Event:  event,
+				Err:    err,
+			}
+		}
+		if errors.Is(err, ErrUnavailable) {
+			return Result{Status: http.StatusServiceUnavailable, Body: map[string]any{"error": "Temporarily unavailable", "msgId": event.ID}, Event: event, Err: err}
+		}
+		return Result{Status: http.StatusInternalServerError, Body: map[string]any{"error": "Handler failed", "msgId": event.ID}, Event: event, Err: err}
+	}
+
+	return Result{
+		Status: http.StatusOK,
+		Body: map[string]any{
+			"success": true,
+			"event":   event.Type,
+			"msgId":   event.ID,
+		},
+		Event: event,
+	}
+}
+
+// ErrorResult maps a verification error to the response the receiver answers with
+func ErrorResult(err error) Result {
+	switch {
+	case errors.Is(err, ErrForbiddenSource):
+		return Result{Status: http.StatusForbidden, Body: map[string]any{"error": "Source not allowed"}, Err: err}
+	case errors.Is(err, ErrBodyTooLarge):
+		return Result{Status: http.StatusRequestEntityTooLarge, Body: map[string]any{"error": ErrBodyTooLarge.Error()}, Err: err}
+	case errors.Is(err, ErrUnsupportedEncoding):
+		return Result{Status: http.StatusUnsupportedMediaType, Body: map[string]any{"error": ErrUnsupportedEncoding.Error(), "details": redact.Error(err)}, Err: err}
+	case errors.Is(err, ErrMissingHeaders):
+		return Result{Status: http.StatusUnauthorized, Body: map[string]any{"error": "Missing Svix headers"}, Err: err}
+	case errors.Is(err, ErrBodyAltered):
+		return Result{Status: http.StatusBadRequest, Body: map[string]any{"error": "Body altered in transit"}, Err: err}
+	case errors.Is(err, ErrInvalidPayload):
+		return Result{Status: http.StatusBadRequest, Body: map[string]any{"error": "Invalid payload", "details": redact.Error(err)}, Err: err}
+	default:
+		return Result{Status: http.StatusUnauthorized, Body: map[string]any{"error": "Verification failed", "details": redact.Error(err)}, Err: err}
+	}
+}
+
+// MaxBodySize returns the configured body size limit for adapters reading the request themselves
+func (r *Receiver) MaxBodySize() int64 {
+	return r.config.MaxBodySize
+}
+
+// ServeHTTP verifies the request and dispatches the resulting event
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := r.CheckSource(req.RemoteAddr); err != nil {
+		res := ErrorResult(err)
+		writeJSON(w, res.Status, res.Body)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, r.config.MaxBodySize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			res := ErrorResult(ErrBodyTooLarge)
+			writeJSON(w, res.Status, res.Body)
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "Failed to read body"})
+		return
+	}
+
+	res := r.ProcessWithToken(req.Context(), req.URL.Query().Get(signing.TokenParam), body, req.Header)
+	for k, vs := range res.Header {
+		w.Header()[k] = vs
+	}
+	writeJSON(w, res.Status, res.Body)
+}
+
+// dispatch runs every matching handler; a failing handler does not stop the rest
+func (r *Receiver) dispatch(ctx context.Context, e *Event) error {
+	r.mu.RLock()
+	handlers := make([]Handler, 0, len(r.handlers[e.Type])+len(r.wildcard))
+	handlers = append(handlers, r.handlers[e.Type]...)
+	handlers = append(handlers, r.wildcard...)
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			r.logger.Warn("receiver: handler failed", "event", e.Type, "msgId", e.ID, "error", err)
+			if r.config.OnHandlerError != nil {
+				r.config.OnHandlerError(err, e.Type)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,424 @@
+package receiver
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+	"github.com/sabry-awad97/Hookshot/webhook"
+
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+// signedRequest builds a request signed with testSecret
+func signedRequest(t testing.TB, body string) *http.Request {
+	t.Helper()
+
+	signer, err := svix.NewWebhook(testSecret)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	ts := time.Now()
+	sig, _ := signer.Sign("msg_test", ts, []byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("svix-id", "msg_test")
+	req.Header.Set("svix-timestamp", fmt.Sprintf("%d", ts.Unix()))
+	req.Header.Set("svix-signature", sig)
+	return req
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(""); err == nil {
+		t.Error("Expected error for missing secret")
+	}
+	if _, err := New(testSecret); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := New(testSecret, WithAcceptedHeaders(signing.HeaderNames{ID: "X-Id"})); err == nil {
+		t.Error("Expected error for incomplete header names")
+	}
+}
+
+func TestReceiver_EndToEnd(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	var got struct {
+		OrderID string `json:"order_id"`
+	}
+	var wildcard string
+	rcv.On("order.created", func(ctx context.Context, e *Event) error {
+		return e.Decode(&got)
+	}).OnAll(func(ctx context.Context, e *Event) error {
+		wildcard = e.Type
+		return nil
+	})
+
+	server := httptest.NewServer(rcv)
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret)
+	resp := client.Send(context.Background(), "order.created", map[string]any{"order_id": "12345"})
+
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if got.OrderID != "12345" {
+		t.Errorf("Expected order_id '12345', got '%s'", got.OrderID)
+	}
+	if wildcard != "order.created" {
+		t.Errorf("Expected wildcard handler to see 'order.created', got '%s'", wildcard)
+	}
+}
+
+func TestReceiver_Rejections(t *testing.T) {
+	rcv, _ := New(testSecret)
+
+	t.Run("missing headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		rcv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := signedRequest(t, `{"event":"a.b","data":{}}`)
+		req.Body = io.NopCloser(strings.NewReader(`{"event":"a.b","data":{"x":1}}`))
+		rec := httptest.NewRecorder()
+		rcv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		small, _ := New(testSecret, WithMaxBodySize(8))
+		req := signedRequest(t, `{"event":"a.b","data":{}}`)
+		rec := httptest.NewRecorder()
+		small.ServeHTTP(rec, req)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+		}
+	})
+}
+
+func TestReceiver_HandlerErrors(t *testing.T) {
+	var reported []string
+	rcv, _ := New(testSecret, WithOnHandlerError(func(err error, event string) {
+		reported = append(reported, event)
+	}))
+
+	ranSecond := false
+	rcv.On("a.b", func(ctx context.Context, e *Event) error {
+		return errors.New("boom")
+	}).On("a.b", func(ctx context.Context, e *Event) error {
+		ranSecond = true
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if !ranSecond {
+		t.Error("Expected failing handler not to break the chain")
+	}
+	if len(reported) != 1 || reported[0] != "a.b" {
+		t.Errorf("Expected one reported error for 'a.b', got %v", reported)
+	}
+}
+
+func TestReceiver_Ed25519(t *testing.T) {
+	sk, pk, err := signing.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair failed: %v", err)
+	}
+
+	rcv, err := New(pk)
+	if err != nil {
+		t.Fatalf("Failed to create receiver: %v", err)
+	}
+	var got string
+	rcv.OnAll(func(ctx context.Context, e *Event) error {
+		got = e.Type
+		return nil
+	})
+
+	var sigHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sigHeader = r.Header.Get("svix-signature")
+		rcv.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, err := webhook.NewClient(server.URL, sk)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	resp := client.Send(context.Background(), "order.created", map[string]any{"id": "1"})
+
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if got != "order.created" {
+		t.Errorf("Expected handler to see 'order.created', got '%s'", got)
+	}
+	if !strings.HasPrefix(sigHeader, "v1a,") {
+		t.Errorf("Expected v1a signature, got '%s'", sigHeader)
+	}
+
+	// An HMAC-signed request must not pass an Ed25519 receiver
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, signedRequest(t, `{"event":"a.b","data":{}}`))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestReceiver_Tolerance(t *testing.T) {
+	rcv, _ := New(testSecret, WithTolerance(time.Minute))
+
+	req := signedRequest(t, `{"event":"a.b","data":{}}`)
+	req.Header.Set("svix-timestamp", fmt.Sprintf("%d", time.Now().Add(-2*time.Minute).Unix()))
+	rec := httptest.NewRecorder()
+	rcv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for stale timestamp, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "too old") {
+		t.Errorf("Expected 'too old' in response, got %s", rec.Body.String())
+	}
+}
+
+func TestReceiver_StandardWebhooks(t *testing.T) {
+	var header http.Header
+	rcv, _ := New(testSecret)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		rcv.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithStandardWebhooks())
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	for _, h := range []string{"webhook-id", "webhook-timestamp", "webhook-signature"} {
+		if header.Get(h) == "" {
+			t.Errorf("Expected %s header", h)
+		}
+	}
+	if header.Get("svix-id") != "" {
+		t.Error("Expected no svix-id header in Standard Webhooks mode")
+	}
+
+	// A receiver restricted to svix headers rejects Standard Webhooks requests
+	svixOnly, _ := New(testSecret, WithAcceptedHeaders(signing.SvixHeaders))
+	server.Config.Handler = svixOnly
+	resp = client.Send(context.Background(), "order.created", map[string]any{})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+}
+
+func TestReceiver_CustomHeaders(t *testing.T) {
+	names := signing.HeaderNames{ID: "X-Request-Id", Timestamp: "X-Request-Timestamp", Signature: "X-Signature"}
+	var header http.Header
+	var body []byte
+	rcv, _ := New(testSecret, WithAcceptedHeaders(names))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		res := rcv.Process(r.Context(), body, r.Header)
+		w.WriteHeader(res.Status)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithHeaderNames(names))
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if header.Get("X-Signature") == "" || header.Get("svix-signature") != "" {
+		t.Errorf("Expected only custom signature headers, got %v", header)
+	}
+
+	// The signed content is unchanged, so the same delivery verifies under svix-* names
+	id, ts, sig, _ := names.Get(header)
+	renamed := http.Header{}
+	renamed.Set("svix-id", id)
+	renamed.Set("svix-timestamp", ts)
+	renamed.Set("svix-signature", sig)
+	defaults, _ := New(testSecret)
+	if _, err := defaults.Verify(body, renamed); err != nil {
+		t.Errorf("Expected renamed headers to verify, got %v", err)
+	}
+}
+
+func TestReceiver_SignedHeaders(t *testing.T) {
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	url := server.URL + "/webhook"
+
+	strict, err := New(testSecret, WithSignedHeaders(url, "Idempotency-Key"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server.Config.Handler = strict
+
+	signed, _ := webhook.NewClient(url, testSecret, webhook.WithSignedHeaders(), webhook.WithMaxRetries(1))
+	if resp := signed.Send(context.Background(), "order.created", map[string]any{}, webhook.WithIdempotencyKey("order-1")); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	// Plain v1 signatures are rejected once signed headers are required
+	plain, _ := webhook.NewClient(url, testSecret, webhook.WithMaxRetries(1))
+	if resp := plain.Send(context.Background(), "order.created", map[string]any{}); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for v1-only delivery, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// A delivery signed for another endpoint does not verify here
+	misdirected, _ := webhook.NewClient(server.URL+"/other", testSecret, webhook.WithSignedHeaders(), webhook.WithMaxRetries(1))
+	if resp := misdirected.Send(context.Background(), "order.created", map[string]any{}, webhook.WithIdempotencyKey("order-1")); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for misdirected delivery, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	_, publicKey, _ := signing.GenerateKeyPair()
+	if _, err := New(publicKey, WithSignedHeaders(url)); err == nil {
+		t.Error("Expected error for Ed25519 key with signed headers")
+	}
+}
+
+func TestReceiver_ContentDigest(t *testing.T) {
+	rcv, _ := New(testSecret, WithContentDigest())
+
+	var errs []error
+	var rewrite bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if rewrite {
+			// A middlebox re-encoding the JSON with indentation
+			body = []byte(strings.ReplaceAll(string(body), ",", ", "))
+		}
+		res := rcv.Process(req.Context(), body, req.Header)
+		errs = append(errs, res.Err)
+		w.WriteHeader(res.Status)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL, testSecret, webhook.WithContentDigest(), webhook.WithMaxRetries(1))
+	if resp := client.Send(context.Background(), "order.created", map[string]any{"a": 1, "b": 2}); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	rewrite = true
+	resp := client.Send(context.Background(), "order.created", map[string]any{"a": 1, "b": 2})
+	if resp.StatusCode != http.StatusBadRequest || !errors.Is(errs[1], ErrBodyAltered) {
+		t.Errorf("Expected ErrBodyAltered with status 400, got %v (status %d)", errs[1], resp.StatusCode)
+	}
+
+	rewrite = false
+	plain, _ := webhook.NewClient(server.URL, testSecret, webhook.WithMaxRetries(1))
+	plain.Send(context.Background(), "order.created", map[string]any{})
+	if !errors.Is(errs[2], ErrMissingHeaders) {
+		t.Errorf("Expected ErrMissingHeaders without a digest, got %v", errs[2])
+	}
+}
+
+func FuzzVerify(f *testing.F) {
+	req := signedRequest(f, `{"event":"order.created","data":{}}`)
+	f.Add([]byte(`{"event":"order.created","data":{}}`), req.Header.Get("svix-timestamp"), req.Header.Get("svix-signature"))
+	f.Add([]byte(`{}`), "1700000000", "v1,b2xk v1a,abc")
+	f.Add([]byte{}, "-1", "")
+	f.Add([]byte(`null`), "99999999999999999999", "v1,,")
+
+	rcv, _ := New(testSecret)
+	f.Fuzz(func(t *testing.T, body []byte, ts, sig string) {
+		header := http.Header{"Svix-Id": {"msg_test"}, "Svix-Timestamp": {ts}, "Svix-Signature": {sig}}
+		if _, err := rcv.Verify(body, header); err == nil && sig != req.Header.Get("svix-signature") {
+			t.Fatalf("Signature %q verified for a body it was not issued for", sig)
+		}
+	})
+}
+
+func FuzzDecodePayload(f *testing.F) {
+	f.Add([]byte(`{"event":"order.created","timestamp":"2024-01-01T00:00:00Z","data":{"a":1}}`))
+	f.Add([]byte(`{"event":1,"timestamp":"not a time"}`))
+	f.Add([]byte(`{"data":`))
+	f.Add([]byte(`[]`))
+
+	rcv, _ := New(testSecret)
+	signer, _ := svix.NewWebhook(testSecret)
+	f.Fuzz(func(t *testing.T, body []byte) {
+		// Sign every input so decoding, not verification, is exercised
+		ts := time.Now()
+		sig, _ := signer.Sign("msg_test", ts, body)
+		header := http.Header{"Svix-Id": {"msg_test"}, "Svix-Timestamp": {fmt.Sprintf("%d", ts.Unix())}, "Svix-Signature": {sig}}
+
+		e, err := rcv.Verify(body, header)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidPayload) {
+				t.Fatalf("Expected ErrInvalidPayload for a verified body, got %v", err)
+			}
+			return
+		}
+		if res := DispatchResult(e, rcv.Dispatch(context.Background(), e)); res.Status != http.StatusOK {
+			t.Fatalf("Expected dispatch without handlers to succeed, got %d", res.Status)
+		}
+	})
+}
+
+func TestReceiver_SecretEncodings(t *testing.T) {
+	key := []byte("raw-key-bytes-from-a-vault")
+	hexSecret := hex.EncodeToString(key)
+
+	for _, tc := range []struct {
+		name string
+		rcv  func() (*Receiver, error)
+	}{
+		{"raw key", func() (*Receiver, error) { return NewWithKey(key) }},
+		{"hex secret", func() (*Receiver, error) { return New(hexSecret, WithSecretEncoding(signing.EncodingHex)) }},
+		{"raw secret", func() (*Receiver, error) { return New(string(key), WithSecretEncoding(signing.EncodingRaw)) }},
+	} {
+		rcv, err := tc.rcv()
+		if err != nil {
+			t.Fatalf("%s: Failed to create receiver: %v", tc.name, err)
+		}
+		server := httptest.NewServer(rcv)
+		hexClient, _ := webhook.NewClient(server.URL, hexSecret, webhook.WithSecretEncoding(signing.EncodingHex))
+		keyClient, _ := webhook.NewClientWithKey(server.URL, key)
+		for _, client := range []*webhook.Client{hexClient, keyClient} {
+			if resp := client.Send(context.Background(), "order.created", map[string]any{}); !resp.Success {
+				t.Errorf("%s: Expected verification to succeed, got %v", tc.name, resp.Error)
+			}
+		}
+		server.Close()
+	}
+
+	if _, err := New(hexSecret); !errors.Is(err, signing.ErrInvalidSecret) || !strings.Contains(err.Error(), "hex") {
+		t.Errorf("Expected a hex secret without the hex encoding to be pinpointed, got %v", err)
+	}
+	if _, err := webhook.NewClientWithKey("http://localhost", nil); err == nil {
+		t.Error("Expected an empty key to be rejected")
+	}
+}
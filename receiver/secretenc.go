@@ -0,0 +1,23 @@
+package receiver
+
+import (
+	"fmt"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// WithSecretEncoding declares how the HMAC secret passed to New encodes its
+// key bytes, e.g. signing.EncodingHex. Ed25519 public keys are unaffected.
+func WithSecretEncoding(enc signing.SecretEncoding) Option {
+	return func(c *Config) {
+		c.SecretEncoding = enc
+	}
+}
+
+// NewWithKey creates a receiver verifying with raw HMAC key bytes
+func NewWithKey(key []byte, opts ...Option) (*Receiver, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("receiver: key is required")
+	}
+	return New(signing.EncodeSecret(key), opts...)
+}
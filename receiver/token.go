@@ -0,0 +1,85 @@
+package receiver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// WithQueryTokens also accepts deliveries authenticated by a hookshot_token
+// query parameter (see webhook.WithQueryToken) instead of signature headers.
+// It requires an HMAC (whsec_) secret.
+func WithQueryTokens() Option {
+	return func(c *Config) {
+		c.QueryTokens = true
+	}
+}
+
+func newTokenKey(cfg Config) ([]byte, error) {
+	if !cfg.QueryTokens {
+		return nil, nil
+	}
+	if strings.HasPrefix(cfg.Secret, signing.PublicKeyPrefix) || strings.HasPrefix(cfg.Secret, signing.SecretKeyPrefix) {
+		return nil, fmt.Errorf("receiver: query tokens require an HMAC (whsec_) secret")
+	}
+	key, err := signing.DecodeSecret(cfg.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("receiver: failed to create verifier: %w", err)
+	}
+	return key, nil
+}
+
+// VerifyToken checks a query token against the body and decodes it into an
+// Event, for adapters that read the query string themselves. The token's jti
+// becomes the event ID.
+func (r *Receiver) VerifyToken(token string, body []byte, header http.Header) (*Event, error) {
+	if r.tokenKey == nil {
+		return nil, fmt.Errorf("%w: query tokens are not enabled", ErrVerification)
+	}
+	if int64(len(body)) > r.config.MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	if enc := contentEncoding(header); enc != "" {
+		decoded, err := decodeBody(enc, body, r.config.MaxDecodedSize)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+
+	claims, err := signing.VerifyToken(r.tokenKey, token, body, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	return r.newEvent(claims.MessageID, body, header)
+}
+
+// ProcessWithToken is Process for adapters that read the query string
+// themselves: a non-empty hookshot_token authenticates the delivery in place
+// of signature headers when query tokens are enabled, as in ServeHTTP
+func (r *Receiver) ProcessWithToken(ctx context.Context, token string, body []byte, header http.Header) Result {
+	if token != "" && r.tokenKey != nil {
+		return r.processToken(ctx, token, body, header)
+	}
+	return r.Process(ctx, body, header)
+}
+
+// VerifyWithToken is Verify with the query-token fallback of ProcessWithToken
+func (r *Receiver) VerifyWithToken(token string, body []byte, header http.Header) (*Event, error) {
+	if token != "" && r.tokenKey != nil {
+		return r.VerifyToken(token, body, header)
+	}
+	return r.Verify(body, header)
+}
+
+func (r *Receiver) processToken(ctx context.Context, token string, body []byte, header http.Header) Result {
+	event, err := r.VerifyToken(token, body, header)
+	if err != nil {
+		return ErrorResult(err)
+	}
+	return DispatchResult(event, r.Dispatch(ctx, event))
+}
@@ -0,0 +1,83 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+	"github.com/sabry-awad97/Hookshot/webhook"
+)
+
+func TestReceiver_QueryTokens(t *testing.T) {
+	rcv, err := New(testSecret, WithQueryTokens())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *Event
+	rcv.On("order.created", func(ctx context.Context, e *Event) error {
+		got = e
+		return nil
+	})
+
+	var header http.Header
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, query = r.Header.Clone(), r.URL.RawQuery
+		rcv.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client, _ := webhook.NewClient(server.URL+"/hooks?tenant=acme", testSecret, webhook.WithQueryToken(time.Minute))
+	resp := client.Send(context.Background(), "order.created", map[string]string{"order_id": "1"})
+	if !resp.Success {
+		t.Fatalf("Expected success, got %v", resp.Error)
+	}
+
+	if header.Get("svix-signature") != "" || header.Get("svix-id") != "" {
+		t.Errorf("Expected no signature headers, got %v", header)
+	}
+	if !strings.Contains(query, "tenant=acme") || !strings.Contains(query, signing.TokenParam+"=") {
+		t.Errorf("Expected the token alongside existing query parameters, got %q", query)
+	}
+	if got == nil || got.ID != resp.MessageID {
+		t.Errorf("Expected event ID %s from the token, got %+v", resp.MessageID, got)
+	}
+}
+
+func TestReceiver_QueryTokens_Rejected(t *testing.T) {
+	key, _ := signing.DecodeSecret(testSecret)
+	body := `{"event":"order.created","data":{}}`
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		token string
+		want  int
+	}{
+		{name: "valid", opts: []Option{WithQueryTokens()}, token: signing.SignToken(key, "msg_1", now, now.Add(time.Minute), []byte(body)), want: http.StatusOK},
+		{name: "expired", opts: []Option{WithQueryTokens()}, token: signing.SignToken(key, "msg_1", now.Add(-time.Hour), now.Add(-time.Minute), []byte(body)), want: http.StatusUnauthorized},
+		{name: "other body", opts: []Option{WithQueryTokens()}, token: signing.SignToken(key, "msg_1", now, now.Add(time.Minute), []byte("{}")), want: http.StatusUnauthorized},
+		{name: "not enabled", token: signing.SignToken(key, "msg_1", now, now.Add(time.Minute), []byte(body)), want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv, _ := New(testSecret, tt.opts...)
+			rec := httptest.NewRecorder()
+			rcv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hooks?"+signing.TokenParam+"="+tt.token, strings.NewReader(body)))
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	secretKey, _, _ := signing.GenerateKeyPair()
+	if _, err := New(secretKey, WithQueryTokens()); err == nil {
+		t.Error("Expected error for a non-HMAC secret")
+	}
+}
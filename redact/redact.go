@@ -0,0 +1,161 @@
+// Package redact masks signing secrets, signatures and credentials in strings,
+// headers and log records, so error messages, stored attempts and logs cannot
+// leak them
+package redact
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces every redacted value
+const Mask = "[REDACTED]"
+
+// patterns match secrets inside free text; group 1 is kept as context
+var patterns = []*regexp.Regexp{
+	regexp.MustCompile(`(whsec_|whsk_)[A-Za-z0-9+/=_-]+`),                                          // Signing secrets
+	regexp.MustCompile(`\b(v1[a-z]?,)[A-Za-z0-9+/=_-]{16,}`),                                       // Versioned signatures
+	regexp.MustCompile(`\b(v[01]=)[0-9a-fA-F]{32,}`),                                               // Stripe-style signatures
+	regexp.MustCompile(`\b(sha(?:1|256)=)[0-9a-fA-F]{32,}`),                                        // X-Hub-Signature values
+	regexp.MustCompile(`(?i)\b((?:Bearer|Basic|Token) )[A-Za-z0-9._~+/=-]+`),                       // Authorization credentials
+	regexp.MustCompile(`(?i)((?:hookshot_token|api_key|access_token|token|ticket|key)=)[^&\s"']+`), // Query credentials
+	regexp.MustCompile(`()\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),                    // JWTs
+}
+
+// sensitiveHeaders have their whole value masked
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"Svix-Signature":      true,
+	"Webhook-Signature":   true,
+	"Stripe-Signature":    true,
+	"X-Hub-Signature":     true,
+	"X-Hub-Signature-256": true,
+	"X-Signature":         true,
+}
+
+// String masks secrets, signatures and credentials found in s
+func String(s string) string {
+	for _, p := range patterns {
+		s = p.ReplaceAllString(s, "${1}"+Mask)
+	}
+	return s
+}
+
+// SensitiveHeader reports whether the named header's value is always masked
+func SensitiveHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	return sensitiveHeaders[name] || strings.HasSuffix(name, "-Signature") || strings.HasSuffix(name, "-Token")
+}
+
+// Header returns a copy of h with sensitive values masked and the rest passed
+// through String
+func Header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		masked := make([]string, len(values))
+		for i, v := range values {
+			if SensitiveHeader(name) {
+				masked[i] = Mask
+			} else {
+				masked[i] = String(v)
+			}
+		}
+		out[name] = masked
+	}
+	return out
+}
+
+// Error returns err's message passed through String, or "" for nil
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	return String(err.Error())
+}
+
+// handler redacts every record before passing it on
+type handler struct {
+	next slog.Handler
+}
+
+// Handler wraps next so messages and attribute values, including errors,
+// headers and nested groups, are redacted before they are written
+func Handler(next slog.Handler) slog.Handler {
+	if h, ok := next.(handler); ok {
+		return h
+	}
+	return handler{next: next}
+}
+
+// Logger returns l with its handler wrapped by Handler, or a redacting default logger for nil
+func Logger(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return slog.New(Handler(l.Handler()))
+}
+
+func (h handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, String(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = attr(a)
+	}
+	return handler{next: h.next.WithAttrs(masked)}
+}
+
+func (h handler) WithGroup(name string) slog.Handler {
+	return handler{next: h.next.WithGroup(name)}
+}
+
+// attr redacts one attribute, masking sensitive header names outright
+func attr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		if SensitiveHeader(a.Key) {
+			return slog.String(a.Key, Mask)
+		}
+		return slog.String(a.Key, String(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		masked := make([]any, len(group))
+		for i, g := range group {
+			masked[i] = attr(g)
+		}
+		return slog.Group(a.Key, masked...)
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return slog.String(a.Key, Error(x))
+		case http.Header:
+			return slog.Any(a.Key, Header(x))
+		case []string:
+			masked := make([]string, len(x))
+			for i, s := range x {
+				masked[i] = String(s)
+			}
+			return slog.Any(a.Key, masked)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
@@ -0,0 +1,90 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"secret whsec_C2FtcGxlX3NlY3JldA== rejected", "secret whsec_" + Mask + " rejected"},
+		{"got v1,K5oZfzN95Z9UVu1EsfQmfVNQhnkZ2pj9o9NDN/H/pI4=", "got v1," + Mask},
+		{"t=1,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd", "t=1,v1=" + Mask},
+		{"sha256=0123456789abcdef0123456789abcdef", "sha256=" + Mask},
+		{"Authorization: Bearer abc.def-123", "Authorization: Bearer " + Mask},
+		{"GET /hook?hookshot_token=abc123&x=1", "GET /hook?hookshot_token=" + Mask + "&x=1"},
+		{"GET /v1/stream?ticket=abc123&events=order.*", "GET /v1/stream?ticket=" + Mask + "&events=order.*"},
+		{"token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", "token " + Mask},
+		{"status 500: internal error", "status 500: internal error"},
+	}
+	for _, tt := range tests {
+		if got := String(tt.in); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Svix-Signature", "v1,anything")
+	h.Set("X-Custom-Token", "opaque")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Note", "key whsec_abc")
+
+	got := Header(h)
+	if got.Get("Svix-Signature") != Mask || got.Get("X-Custom-Token") != Mask {
+		t.Errorf("Expected sensitive headers masked, got %v", got)
+	}
+	if got.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected Content-Type untouched, got %q", got.Get("Content-Type"))
+	}
+	if got.Get("X-Note") != "key whsec_"+Mask {
+		t.Errorf("Expected secret in value masked, got %q", got.Get("X-Note"))
+	}
+	if h.Get("Svix-Signature") != "v1,anything" {
+		t.Errorf("Expected original header unchanged")
+	}
+}
+
+func TestError(t *testing.T) {
+	if Error(nil) != "" {
+		t.Errorf("Expected empty string for nil error")
+	}
+	if got := Error(errors.New("bad secret whsec_abc")); got != "bad secret whsec_"+Mask {
+		t.Errorf("Expected masked error, got %q", got)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := Logger(slog.New(slog.NewTextHandler(&buf, nil))).With("secret", "whsec_abc")
+	logger.Info("signing with whsec_def",
+		"authorization", "Bearer xyz",
+		"err", errors.New("echoed v1,K5oZfzN95Z9UVu1EsfQmfVNQhnkZ2pj9"),
+		slog.Group("req", "url", "https://example.com/hook?token=abc"),
+		"headers", http.Header{"Stripe-Signature": {"t=1,v1=abc"}},
+	)
+
+	out := buf.String()
+	for _, leak := range []string{"whsec_abc", "whsec_def", "xyz", "K5oZfzN95Z9UVu1EsfQmfVNQhnkZ2pj9", "token=abc", "v1=abc"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("Expected %q redacted, got %s", leak, out)
+		}
+	}
+	if !strings.Contains(out, "req.url=") {
+		t.Errorf("Expected the group to be kept, got %s", out)
+	}
+}
+
+func TestHandler_WrapsOnce(t *testing.T) {
+	h := Handler(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if Handler(h) != h {
+		t.Errorf("Expected wrapping a redacting handler to return it unchanged")
+	}
+}
@@ -0,0 +1,226 @@
+// Package retry implements the retry semantics Hookshot uses for webhook
+// delivery: capped exponential backoff with injectable jitter, an attempt
+// limit, permanent errors and operation-requested delays. It is usable on its
+// own for any call that should retry the same way.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// jitterFactor spreads each delay uniformly over ±50%
+const jitterFactor = 0.5
+
+// Scheduler waits out the delay between attempts
+type Scheduler interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+// Policy configures Do. Zero fields take the defaults shown.
+type Policy struct {
+	MaxAttempts     uint64                               // Total attempts including the first (default: 3)
+	InitialInterval time.Duration                        // Delay after the first failure (default: 1s)
+	MaxInterval     time.Duration                        // Cap on computed delays (default: 30s)
+	Multiplier      float64                              // Growth factor between delays (default: 1.5)
+	MaxDelay        time.Duration                        // Cap on delays requested by a Delayer (default: MaxInterval)
+	Jitter          func() float64                       // Randomization source in [0, 1); 0.5 means none (default: math/rand)
+	Scheduler       Scheduler                            // Delay timer (default: real timers)
+	OnRetry         func(err error, delay time.Duration) // Called before each wait
+}
+
+// Permanent is implemented by errors that must not be retried
+type Permanent interface {
+	Permanent() bool
+}
+
+// Delayer is implemented by errors that ask for a specific delay before the
+// next attempt, e.g. from a Retry-After header. The delay replaces the
+// computed one but still counts against MaxAttempts.
+type Delayer interface {
+	RetryDelay() time.Duration
+}
+
+// MarkPermanent wraps err so Do stops retrying; errors.Is and errors.As see through it
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// WithDelay wraps err so Do waits d, capped at Policy.MaxDelay, before the next attempt
+func WithDelay(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &delayError{err, d}
+}
+
+// IsPermanent reports whether any error in err's chain is Permanent
+func IsPermanent(err error) bool {
+	var p Permanent
+	return errors.As(err, &p) && p.Permanent()
+}
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string   { return e.err.Error() }
+func (e *permanentError) Unwrap() error   { return e.err }
+func (e *permanentError) Permanent() bool { return true }
+
+type delayError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *delayError) Error() string             { return e.err.Error() }
+func (e *delayError) Unwrap() error             { return e.err }
+func (e *delayError) RetryDelay() time.Duration { return e.delay }
+
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialInterval == 0 {
+		p.InitialInterval = time.Second
+	}
+	if p.MaxInterval == 0 {
+		p.MaxInterval = 30 * time.Second
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = backoff.DefaultMultiplier
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = p.MaxInterval
+	}
+	if p.Jitter == nil {
+		p.Jitter = rand.Float64
+	}
+	if p.Scheduler == nil {
+		p.Scheduler = RealScheduler{}
+	}
+	return p
+}
+
+// Do calls op until it succeeds, returns a Permanent error, MaxAttempts is
+// reached or ctx is done. attempt is 1-based. It returns nil on success and
+// otherwise the last error op returned, or ctx's error if op never ran.
+func Do(ctx context.Context, p Policy, op func(ctx context.Context, attempt int) error) error {
+	p = p.withDefaults()
+
+	exp := backoff.NewExponentialBackOff()
+	exp.InitialInterval = p.InitialInterval
+	exp.MaxInterval = p.MaxInterval
+	exp.Multiplier = p.Multiplier
+	exp.MaxElapsedTime = 0      // bounded by MaxAttempts instead
+	exp.RandomizationFactor = 0 // jitter is applied from p.Jitter
+
+	delays := &delayBackOff{BackOff: &jitterBackOff{BackOff: exp, jitter: p.Jitter}}
+	b := backoff.WithContext(backoff.WithMaxRetries(delays, p.MaxAttempts-1), ctx)
+
+	var last error
+	attempt := 0
+	operation := func() error {
+		attempt++
+		last = op(ctx, attempt)
+		switch {
+		case last == nil:
+			return nil
+		case IsPermanent(last):
+			return backoff.Permanent(last)
+		}
+		var d Delayer
+		if errors.As(last, &d) {
+			delays.set(min(d.RetryDelay(), p.MaxDelay))
+		}
+		return last
+	}
+
+	var notify backoff.Notify
+	if p.OnRetry != nil {
+		notify = p.OnRetry
+	}
+	err := backoff.RetryNotifyWithTimer(operation, b, notify, &schedulerTimer{scheduler: p.Scheduler})
+	if err != nil && last != nil {
+		return last
+	}
+	return err
+}
+
+// Budget returns how many attempts fit in remaining if each may take up to
+// perAttempt, following the un-jittered delay schedule. It is at least 1 and
+// at most MaxAttempts.
+func (p Policy) Budget(remaining, perAttempt time.Duration) uint64 {
+	p = p.withDefaults()
+	var used time.Duration
+	delay := p.InitialInterval
+	for n := uint64(1); n <= p.MaxAttempts; n++ {
+		if used += perAttempt; used > remaining {
+			return max(n-1, 1)
+		}
+		used += delay
+		delay = min(time.Duration(float64(delay)*p.Multiplier), p.MaxInterval)
+	}
+	return p.MaxAttempts
+}
+
+// RealScheduler waits with real timers; it is the default Scheduler
+type RealScheduler struct{}
+
+func (RealScheduler) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// schedulerTimer adapts a Scheduler to backoff.Timer
+type schedulerTimer struct {
+	scheduler Scheduler
+	c         <-chan time.Time
+}
+
+func (t *schedulerTimer) Start(d time.Duration) { t.c = t.scheduler.After(d) }
+func (t *schedulerTimer) Stop()                 {}
+func (t *schedulerTimer) C() <-chan time.Time   { return t.c }
+
+// jitterBackOff applies randomization from an injectable source on top of a
+// non-randomized exponential backoff
+type jitterBackOff struct {
+	backoff.BackOff
+	jitter func() float64
+}
+
+func (b *jitterBackOff) NextBackOff() time.Duration {
+	next := b.BackOff.NextBackOff()
+	if next == backoff.Stop {
+		return next
+	}
+	delta := jitterFactor * float64(next)
+	min := float64(next) - delta
+	max := float64(next) + delta
+	return time.Duration(min + b.jitter()*(max-min+1))
+}
+
+// delayBackOff replaces the next computed delay with a requested one while
+// still advancing the wrapped schedule, so attempt limits apply unchanged
+type delayBackOff struct {
+	backoff.BackOff
+	delay    time.Duration
+	hasDelay bool
+}
+
+func (b *delayBackOff) set(d time.Duration) {
+	b.delay, b.hasDelay = d, true
+}
+
+func (b *delayBackOff) NextBackOff() time.Duration {
+	next := b.BackOff.NextBackOff()
+	if next == backoff.Stop || !b.hasDelay {
+		return next
+	}
+	b.hasDelay = false
+	return b.delay
+}
@@ -0,0 +1,167 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScheduler records requested delays and fires immediately
+type fakeScheduler struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (s *fakeScheduler) After(d time.Duration) <-chan time.Time {
+	s.mu.Lock()
+	s.delays = append(s.delays, d)
+	s.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func testPolicy() (Policy, *fakeScheduler) {
+	sched := &fakeScheduler{}
+	return Policy{
+		MaxAttempts: 4,
+		MaxInterval: 2 * time.Second,
+		Jitter:      func() float64 { return 0.5 }, // no randomization
+		Scheduler:   sched,
+	}, sched
+}
+
+var errTransient = errors.New("transient")
+
+func TestDo(t *testing.T) {
+	p, sched := testPolicy()
+
+	var attempts []int
+	err := Do(context.Background(), p, func(ctx context.Context, attempt int) error {
+		attempts = append(attempts, attempt)
+		if attempt < 4 {
+			return errTransient
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if !slices.Equal(attempts, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected attempts 1-4, got %v", attempts)
+	}
+	if want := []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second}; !slices.Equal(sched.delays, want) {
+		t.Errorf("Expected delays %v, got %v", want, sched.delays)
+	}
+}
+
+func TestDo_Exhausted(t *testing.T) {
+	p, _ := testPolicy()
+	calls := 0
+	err := Do(context.Background(), p, func(context.Context, int) error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 4 {
+		t.Errorf("Expected last error after 4 attempts, got %v after %d", err, calls)
+	}
+}
+
+func TestDo_Permanent(t *testing.T) {
+	p, sched := testPolicy()
+	calls := 0
+	err := Do(context.Background(), p, func(context.Context, int) error {
+		calls++
+		return MarkPermanent(errTransient)
+	})
+	if !errors.Is(err, errTransient) || !IsPermanent(err) || calls != 1 || len(sched.delays) != 0 {
+		t.Errorf("Expected a single permanent failure, got %v after %d calls", err, calls)
+	}
+}
+
+// quotaError classifies itself through the exported interfaces
+type quotaError struct{}
+
+func (quotaError) Error() string             { return "quota exceeded" }
+func (quotaError) RetryDelay() time.Duration { return 90 * time.Second }
+
+func TestDo_Delayer(t *testing.T) {
+	p, sched := testPolicy()
+	p.MaxDelay = time.Minute
+
+	Do(context.Background(), p, func(_ context.Context, attempt int) error {
+		switch attempt {
+		case 1:
+			return WithDelay(errTransient, 5*time.Second)
+		case 2:
+			return quotaError{}
+		case 3:
+			return errTransient
+		}
+		return nil
+	})
+
+	// Requested delays replace, not reset, the exponential schedule
+	if want := []time.Duration{5 * time.Second, time.Minute, 2 * time.Second}; !slices.Equal(sched.delays, want) {
+		t.Errorf("Expected delays %v, got %v", want, sched.delays)
+	}
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	p, _ := testPolicy()
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, p, func(context.Context, int) error {
+		calls++
+		cancel()
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) || calls != 1 {
+		t.Errorf("Expected to stop after cancellation, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDo_Defaults(t *testing.T) {
+	var retried []time.Duration
+	p := Policy{
+		Jitter:    func() float64 { return 0.5 },
+		Scheduler: &fakeScheduler{},
+		OnRetry:   func(err error, d time.Duration) { retried = append(retried, d) },
+	}
+	calls := 0
+	Do(context.Background(), p, func(context.Context, int) error {
+		calls++
+		return errTransient
+	})
+	if calls != 3 || !slices.Equal(retried, []time.Duration{time.Second, 1500 * time.Millisecond}) {
+		t.Errorf("Expected 3 attempts with default delays, got %d calls and %v", calls, retried)
+	}
+	if MarkPermanent(nil) != nil || WithDelay(nil, time.Second) != nil {
+		t.Error("Expected nil errors to stay nil")
+	}
+}
+
+func TestPolicy_Budget(t *testing.T) {
+	p := Policy{MaxAttempts: 5, MaxInterval: 2 * time.Second}
+
+	tests := []struct {
+		remaining, perAttempt time.Duration
+		want                  uint64
+	}{
+		{time.Hour, time.Second, 5},
+		{time.Second, 10 * time.Second, 1},         // never less than one attempt
+		{3 * time.Second, time.Second, 2},          // 1s + 1s delay + 1s
+		{5500 * time.Millisecond, time.Second, 3},  // + 1.5s delay + 1s
+		{8499 * time.Millisecond, time.Second, 3},  // the fourth needs 2s delay + 1s more
+		{11500 * time.Millisecond, time.Second, 5}, // delays capped at MaxInterval
+	}
+	for _, tt := range tests {
+		if got := p.Budget(tt.remaining, tt.perAttempt); got != tt.want {
+			t.Errorf("Budget(%v, %v): expected %d, got %d", tt.remaining, tt.perAttempt, tt.want, got)
+		}
+	}
+}
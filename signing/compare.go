@@ -0,0 +1,20 @@
+package signing
+
+import "crypto/subtle"
+
+// Equal reports whether two signatures or MACs match, in time that depends
+// only on their lengths. Every comparison of secret-derived values goes
+// through it or MatchAny.
+func Equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// MatchAny reports whether expected equals any candidate. Every candidate is
+// compared, so the position of a match does not leak through timing.
+func MatchAny(expected string, candidates []string) bool {
+	match := 0
+	for _, c := range candidates {
+		match |= subtle.ConstantTimeCompare([]byte(c), []byte(expected))
+	}
+	return match == 1
+}
@@ -0,0 +1,12 @@
+package signing
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	if !Equal("v1,abc", "v1,abc") || Equal("v1,abc", "v1,abd") || Equal("v1,abc", "v1,ab") {
+		t.Error("Equal() gave a wrong result")
+	}
+	if !MatchAny("b", []string{"a", "b", "c"}) || MatchAny("d", []string{"a", "b"}) || MatchAny("a", nil) {
+		t.Error("MatchAny() gave a wrong result")
+	}
+}
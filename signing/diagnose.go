@@ -0,0 +1,244 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Diagnosis explains why a captured delivery does or does not verify
+type Diagnosis struct {
+	Headers   HeaderNames // Header set the message was found under
+	MsgID     string
+	Timestamp string
+	Received  []Signature // Signatures parsed from the header
+	Expected  []string    // Signatures the secret produces for the body as received (signing secrets only)
+	Verified  bool        // Whether the delivery verifies exactly as received
+	Findings  []string    // What broke verification, most specific first
+}
+
+// diagnosisTolerance mirrors the receiver's default timestamp tolerance
+const diagnosisTolerance = 5 * time.Minute
+
+// Diagnose recomputes the signatures for a captured raw body and headers and
+// reports which header normalizations, body byte changes or key mistakes
+// explain a mismatch. secret may be a whsec_ secret, a whsk_ key or a whpk_ key.
+func Diagnose(secret string, body []byte, h http.Header) (*Diagnosis, error) {
+	verify, err := diagnosisVerifier(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Diagnosis{}
+	found := false
+	for _, names := range []HeaderNames{SvixHeaders, StandardHeaders} {
+		if id, ts, sig, ok := names.Get(h); ok {
+			d.Headers, d.MsgID, d.Timestamp = names, id, ts
+			d.Received, _ = ParseSignatures(sig)
+			found = true
+			break
+		}
+	}
+	if !found {
+		d.Findings = append(d.Findings, missingHeaders(h))
+		return d, nil
+	}
+
+	unix, err := strconv.ParseInt(strings.TrimSpace(d.Timestamp), 10, 64)
+	if err != nil {
+		d.Findings = append(d.Findings, fmt.Sprintf("%s %q is not a unix timestamp", d.Headers.Timestamp, d.Timestamp))
+		return d, nil
+	}
+	ts := time.Unix(unix, 0)
+	sigHeader := h.Get(d.Headers.Signature)
+
+	if signer, err := NewSigner(secret); err == nil {
+		d.Expected = []string{signer.Sign(d.MsgID, ts, body)}
+	}
+
+	scheme := SchemeV1
+	if strings.HasPrefix(secret, PublicKeyPrefix) || strings.HasPrefix(secret, SecretKeyPrefix) {
+		scheme = SchemeV1a
+	}
+	if !hasVersion(d.Received, scheme) {
+		d.Findings = append(d.Findings, fmt.Sprintf("%s carries no %s signature (got %s); the secret only verifies %s",
+			d.Headers.Signature, scheme, versions(d.Received), scheme))
+	}
+
+	if age := time.Since(ts); age > diagnosisTolerance || age < -diagnosisTolerance {
+		d.Findings = append(d.Findings, fmt.Sprintf("timestamp is %s from now; receivers reject it regardless of the signature",
+			age.Round(time.Second)))
+	}
+
+	if verify(nil, d.MsgID, ts, body, sigHeader) {
+		d.Verified = true
+		return d, nil
+	}
+
+	for _, c := range candidates(secret, d, h, ts, body) {
+		if verify(c.key, c.msgID, c.timestamp, c.body, c.header) {
+			d.Findings = append(d.Findings, c.finding)
+			return d, nil
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	d.Findings = append(d.Findings,
+		"no header, body or key normalization reproduces the signature; the secret is wrong or the body was rewritten",
+		fmt.Sprintf("signed content is %q followed by %d body bytes (sha256 %x); compare with the sender's copy",
+			d.MsgID+"."+strconv.FormatInt(unix, 10)+".", len(body), sum))
+	return d, nil
+}
+
+// verifyFunc checks one candidate message; a non-nil key overrides the HMAC key
+type verifyFunc func(key []byte, msgID string, timestamp time.Time, body []byte, header string) bool
+
+func diagnosisVerifier(secret string) (verifyFunc, error) {
+	if strings.HasPrefix(secret, PublicKeyPrefix) || strings.HasPrefix(secret, SecretKeyPrefix) {
+		v, err := NewVerifier(secret)
+		if err != nil {
+			return nil, err
+		}
+		return func(_ []byte, msgID string, timestamp time.Time, body []byte, header string) bool {
+			return v.Verify(msgID, timestamp, body, header) == nil
+		}, nil
+	}
+
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return func(k []byte, msgID string, timestamp time.Time, body []byte, header string) bool {
+		if k == nil {
+			k = key
+		}
+		return VerifyV1(k, msgID, timestamp, body, header) == nil
+	}, nil
+}
+
+type candidate struct {
+	finding   string
+	key       []byte
+	msgID     string
+	timestamp time.Time
+	body      []byte
+	header    string
+}
+
+// candidates lists plausible re-encodings of the delivery, each describing the
+// change that would have to have happened in transit for it to verify
+func candidates(secret string, d *Diagnosis, h http.Header, ts time.Time, body []byte) []candidate {
+	sig := h.Get(d.Headers.Signature)
+	base := candidate{msgID: d.MsgID, timestamp: ts, body: body, header: sig}
+	var out []candidate
+	add := func(finding string, change func(c *candidate)) {
+		c := base
+		c.finding = finding
+		change(&c)
+		out = append(out, c)
+	}
+
+	// Header normalizations
+	if vs := h.Values(d.Headers.Signature); len(vs) > 1 {
+		add(fmt.Sprintf("%s appears %d times and only the first is read; join the values with spaces", d.Headers.Signature, len(vs)),
+			func(c *candidate) { c.header = strings.Join(vs, " ") })
+	}
+	if strings.Contains(sig, ", ") {
+		add(fmt.Sprintf("%s entries are comma-joined, as a proxy merging repeated headers does; separate them with spaces", d.Headers.Signature),
+			func(c *candidate) { c.header = strings.ReplaceAll(sig, ", ", " ") })
+	}
+	if trimmed := strings.TrimSpace(d.MsgID); trimmed != d.MsgID {
+		add(fmt.Sprintf("%s has surrounding whitespace that the sender did not sign", d.Headers.ID),
+			func(c *candidate) { c.msgID = trimmed })
+	}
+	if ts.Unix() > 1e11 {
+		add(fmt.Sprintf("%s is in milliseconds; signatures cover the timestamp in seconds", d.Headers.Timestamp),
+			func(c *candidate) { c.timestamp = time.Unix(ts.Unix()/1000, 0) })
+	}
+
+	// Body byte changes
+	if bytes.HasSuffix(body, []byte("\r\n")) {
+		add("body gained a trailing CRLF after signing", func(c *candidate) { c.body = body[:len(body)-2] })
+	}
+	if bytes.HasSuffix(body, []byte("\n")) {
+		add("body gained a trailing newline (0x0a) after signing", func(c *candidate) { c.body = body[:len(body)-1] })
+	} else {
+		add("body lost its trailing newline (0x0a) after signing", func(c *candidate) { c.body = append(bytes.Clone(body), '\n') })
+	}
+	if bytes.Contains(body, []byte("\r\n")) {
+		add("body line endings were converted from LF to CRLF", func(c *candidate) {
+			c.body = bytes.ReplaceAll(body, []byte("\r\n"), []byte("\n"))
+		})
+	} else if bytes.Contains(body, []byte("\n")) {
+		add("body line endings were converted from CRLF to LF", func(c *candidate) {
+			c.body = bytes.ReplaceAll(body, []byte("\n"), []byte("\r\n"))
+		})
+	}
+	if bom := []byte("\xef\xbb\xbf"); bytes.HasPrefix(body, bom) {
+		add("body gained a UTF-8 byte order mark after signing", func(c *candidate) { c.body = body[len(bom):] })
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil && !bytes.Equal(compact.Bytes(), body) {
+		add("body JSON was re-indented after signing; verify the raw bytes, not re-encoded JSON", func(c *candidate) {
+			c.body = compact.Bytes()
+		})
+	}
+	var v any
+	if json.Unmarshal(body, &v) == nil {
+		if reencoded, err := json.Marshal(v); err == nil && !bytes.Equal(reencoded, body) && !bytes.Equal(reencoded, compact.Bytes()) {
+			add("body JSON was decoded and re-encoded (keys reordered or escapes changed); verify the raw bytes", func(c *candidate) {
+				c.body = reencoded
+			})
+		}
+	}
+
+	// Key mistakes; only HMAC keys can be recomputed from an alternative encoding
+	if !strings.HasPrefix(secret, PublicKeyPrefix) && !strings.HasPrefix(secret, SecretKeyPrefix) {
+		add("the sender used the secret string itself as the HMAC key instead of its base64-decoded bytes", func(c *candidate) {
+			c.key = []byte(secret)
+		})
+		if bare := strings.TrimPrefix(secret, SecretPrefix); bare != secret {
+			add(fmt.Sprintf("the sender used the secret without its %s prefix as the HMAC key", SecretPrefix), func(c *candidate) {
+				c.key = []byte(bare)
+			})
+		}
+	}
+	return out
+}
+
+func missingHeaders(h http.Header) string {
+	var missing []string
+	for _, names := range []HeaderNames{SvixHeaders, StandardHeaders} {
+		for _, name := range []string{names.ID, names.Timestamp, names.Signature} {
+			if h.Get(name) == "" {
+				missing = append(missing, name)
+			}
+		}
+	}
+	return "no complete header set; missing " + strings.Join(missing, ", ")
+}
+
+func hasVersion(sigs []Signature, version string) bool {
+	for _, s := range sigs {
+		if s.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func versions(sigs []Signature) string {
+	if len(sigs) == 0 {
+		return "none"
+	}
+	var out []string
+	for _, s := range sigs {
+		out = append(out, s.Version)
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,135 @@
+package signing
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const diagnoseSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
+
+func diagnoseHeader(names HeaderNames, msgID string, ts time.Time, sig string) http.Header {
+	h := make(http.Header)
+	h.Set(names.ID, msgID)
+	h.Set(names.Timestamp, strconv.FormatInt(ts.Unix(), 10))
+	h.Set(names.Signature, sig)
+	return h
+}
+
+func TestDiagnose(t *testing.T) {
+	ts := time.Now()
+	signed := []byte(`{"type":"order.created","data":{"id":"123"}}`)
+	sig := SignV1(testKey, "msg_1", ts, signed)
+
+	tests := []struct {
+		name     string
+		body     []byte
+		header   http.Header
+		verified bool
+		finding  string
+	}{
+		{
+			name:     "verifies as received",
+			body:     signed,
+			header:   diagnoseHeader(SvixHeaders, "msg_1", ts, sig),
+			verified: true,
+		},
+		{
+			name:     "standard headers",
+			body:     signed,
+			header:   diagnoseHeader(StandardHeaders, "msg_1", ts, sig),
+			verified: true,
+		},
+		{
+			name:    "trailing newline added",
+			body:    append([]byte(string(signed)), '\n'),
+			header:  diagnoseHeader(SvixHeaders, "msg_1", ts, sig),
+			finding: "gained a trailing newline",
+		},
+		{
+			name:    "re-indented JSON",
+			body:    []byte("{\n  \"type\": \"order.created\",\n  \"data\": {\n    \"id\": \"123\"\n  }\n}"),
+			header:  diagnoseHeader(SvixHeaders, "msg_1", ts, sig),
+			finding: "re-indented",
+		},
+		{
+			name:    "comma-joined signatures",
+			body:    signed,
+			header:  diagnoseHeader(SvixHeaders, "msg_1", ts, sig+", v1,c3RhbGU="),
+			finding: "comma-joined",
+		},
+		{
+			name:    "raw secret as key",
+			body:    signed,
+			header:  diagnoseHeader(SvixHeaders, "msg_1", ts, SignV1([]byte(diagnoseSecret), "msg_1", ts, signed)),
+			finding: "secret string itself",
+		},
+		{
+			name:    "wrong secret",
+			body:    signed,
+			header:  diagnoseHeader(SvixHeaders, "msg_1", ts, SignV1([]byte("other"), "msg_1", ts, signed)),
+			finding: "secret is wrong",
+		},
+		{
+			name:    "missing headers",
+			body:    signed,
+			header:  http.Header{},
+			finding: "missing svix-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Diagnose(diagnoseSecret, tt.body, tt.header)
+			if err != nil {
+				t.Fatalf("Diagnose() error = %v", err)
+			}
+			if d.Verified != tt.verified {
+				t.Errorf("Expected verified %v, got %v (findings: %v)", tt.verified, d.Verified, d.Findings)
+			}
+			if tt.finding != "" && !strings.Contains(strings.Join(d.Findings, "\n"), tt.finding) {
+				t.Errorf("Expected a finding containing %q, got %v", tt.finding, d.Findings)
+			}
+		})
+	}
+}
+
+func TestDiagnose_Expected(t *testing.T) {
+	ts := time.Now()
+	body := []byte(`{}`)
+	d, err := Diagnose(diagnoseSecret, body, diagnoseHeader(SvixHeaders, "msg_1", ts, "v1,bm9wZQ=="))
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if len(d.Expected) != 1 || d.Expected[0] != SignV1(testKey, "msg_1", ts, body) {
+		t.Errorf("Expected recomputed v1 signature, got %v", d.Expected)
+	}
+}
+
+func TestDiagnose_Ed25519(t *testing.T) {
+	secretKey, publicKey, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	priv, _ := DecodeSecretKey(secretKey)
+	ts := time.Now()
+	body := []byte(`{"type":"user.signup"}`)
+	header := diagnoseHeader(SvixHeaders, "msg_1", ts, SignV1a(priv, "msg_1", ts, body))
+
+	d, err := Diagnose(publicKey, append(body, '\n'), header)
+	if err != nil {
+		t.Fatalf("Diagnose() error = %v", err)
+	}
+	if d.Verified || len(d.Expected) != 0 {
+		t.Errorf("Expected unverified with no recomputed signature for a public key, got %+v", d)
+	}
+	if !strings.Contains(strings.Join(d.Findings, "\n"), "trailing newline") {
+		t.Errorf("Expected trailing newline finding, got %v", d.Findings)
+	}
+
+	if _, err := Diagnose("whsec_not base64!", body, header); err == nil {
+		t.Error("Expected error for invalid secret")
+	}
+}
@@ -0,0 +1,16 @@
+package signing
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// ContentDigestHeader carries the base64 SHA-256 of the body as signed, so
+// receivers can tell a body altered in transit from a bad signature
+const ContentDigestHeader = "Webhook-Content-SHA256"
+
+// ContentDigest returns the ContentDigestHeader value for body
+func ContentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
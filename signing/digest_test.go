@@ -0,0 +1,11 @@
+package signing
+
+import "testing"
+
+func TestContentDigest(t *testing.T) {
+	// echo -n '{"a":1}' | openssl dgst -sha256 -binary | base64
+	want := "AVq9f1zFei3ZS3WQ8ErYCEJzkF7jPsXOvq5iJ2qX+GI="
+	if got := ContentDigest([]byte(`{"a":1}`)); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
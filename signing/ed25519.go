@@ -0,0 +1,96 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SchemeV1a is the svix Ed25519 asymmetric scheme
+const SchemeV1a = "v1a"
+
+// Key prefixes for Ed25519 secret (signing) and public (verification) keys
+const (
+	SecretKeyPrefix = "whsk_"
+	PublicKeyPrefix = "whpk_"
+)
+
+// GenerateKeyPair creates an Ed25519 key pair encoded as whsk_/whpk_ strings.
+// The secret key stays with the sender; the public key is shared with receivers.
+func GenerateKeyPair() (secretKey, publicKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("signing: failed to generate key: %w", err)
+	}
+	return EncodeSecretKey(priv), EncodePublicKey(pub), nil
+}
+
+// EncodeSecretKey renders an Ed25519 private key as whsk_{base64}
+func EncodeSecretKey(priv ed25519.PrivateKey) string {
+	return SecretKeyPrefix + base64.StdEncoding.EncodeToString(priv)
+}
+
+// EncodePublicKey renders an Ed25519 public key as whpk_{base64}
+func EncodePublicKey(pub ed25519.PublicKey) string {
+	return PublicKeyPrefix + base64.StdEncoding.EncodeToString(pub)
+}
+
+// DecodeSecretKey parses a whsk_ key; both 64-byte private keys and 32-byte seeds are accepted
+func DecodeSecretKey(s string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, SecretKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+	}
+	switch len(raw) {
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	default:
+		return nil, fmt.Errorf("%w: ed25519 secret key must be %d or %d bytes, got %d", ErrInvalidSecret, ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// DecodePublicKey parses a whpk_ key
+func DecodePublicKey(s string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, PublicKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: ed25519 public key must be %d bytes, got %d", ErrInvalidSecret, ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// SignV1a returns the "v1a,{base64}" Ed25519 signature for a message
+func SignV1a(priv ed25519.PrivateKey, msgID string, timestamp time.Time, body []byte) string {
+	sig := ed25519.Sign(priv, SignedContent(msgID, timestamp, body))
+	return SchemeV1a + "," + base64.StdEncoding.EncodeToString(sig)
+}
+
+// VerifyV1a checks that header contains a v1a signature valid under pub
+func VerifyV1a(pub ed25519.PublicKey, msgID string, timestamp time.Time, body []byte, header string) error {
+	sigs, err := ParseSignatures(header)
+	if err != nil {
+		return err
+	}
+
+	content := SignedContent(msgID, timestamp, body)
+	for _, s := range sigs {
+		if s.Version != SchemeV1a {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(s.Value)
+		if err != nil || len(raw) != ed25519.SignatureSize {
+			continue
+		}
+		if ed25519.Verify(pub, content, raw) {
+			return nil
+		}
+	}
+	return ErrNoMatchingSignature
+}
@@ -0,0 +1,58 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEd25519_RoundTrip(t *testing.T) {
+	sk, pk, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair failed: %v", err)
+	}
+
+	signer, err := NewSigner(sk)
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	if signer.Version() != SchemeV1a {
+		t.Errorf("Expected version %s, got %s", SchemeV1a, signer.Version())
+	}
+
+	verifier, err := NewVerifier(pk)
+	if err != nil {
+		t.Fatalf("NewVerifier failed: %v", err)
+	}
+
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"a":1}`)
+	sig := signer.Sign("msg_1", ts, body)
+
+	if err := verifier.Verify("msg_1", ts, body, sig); err != nil {
+		t.Errorf("Expected valid signature, got %v", err)
+	}
+	if err := verifier.Verify("msg_1", ts, []byte(`{"a":2}`), sig); !errors.Is(err, ErrNoMatchingSignature) {
+		t.Errorf("Expected ErrNoMatchingSignature for tampered body, got %v", err)
+	}
+	if err := verifier.Verify("msg_2", ts, body, sig); !errors.Is(err, ErrNoMatchingSignature) {
+		t.Errorf("Expected ErrNoMatchingSignature for different ID, got %v", err)
+	}
+
+	// A v1 HMAC signature in the same header must not satisfy a v1a verifier
+	if err := verifier.Verify("msg_1", ts, body, SignV1(testKey, "msg_1", ts, body)); !errors.Is(err, ErrNoMatchingSignature) {
+		t.Errorf("Expected ErrNoMatchingSignature for v1-only header, got %v", err)
+	}
+}
+
+func TestDecodeKeys_Invalid(t *testing.T) {
+	if _, err := DecodePublicKey("whpk_AAAA"); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Expected ErrInvalidSecret for short public key, got %v", err)
+	}
+	if _, err := DecodeSecretKey("whsk_AAAA"); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Expected ErrInvalidSecret for short secret key, got %v", err)
+	}
+	if _, err := NewVerifier("whpk_!!"); err == nil {
+		t.Error("Expected error for malformed public key")
+	}
+}
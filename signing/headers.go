@@ -0,0 +1,39 @@
+package signing
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// HeaderNames names the message ID, timestamp and signature headers
+type HeaderNames struct {
+	ID        string
+	Timestamp string
+	Signature string
+}
+
+// Header sets emitted and accepted by Hookshot
+var (
+	// SvixHeaders are the svix-prefixed headers (the default)
+	SvixHeaders = HeaderNames{ID: "svix-id", Timestamp: "svix-timestamp", Signature: "svix-signature"}
+	// StandardHeaders follow the Standard Webhooks specification
+	StandardHeaders = HeaderNames{ID: "webhook-id", Timestamp: "webhook-timestamp", Signature: "webhook-signature"}
+)
+
+// Validate checks that the three names are set and distinct
+func (n HeaderNames) Validate() error {
+	if n.ID == "" || n.Timestamp == "" || n.Signature == "" {
+		return errors.New("signing: header names must all be set")
+	}
+	if strings.EqualFold(n.ID, n.Timestamp) || strings.EqualFold(n.ID, n.Signature) || strings.EqualFold(n.Timestamp, n.Signature) {
+		return errors.New("signing: header names must be distinct")
+	}
+	return nil
+}
+
+// Get returns the three header values, and whether all of them are present
+func (n HeaderNames) Get(h http.Header) (id, timestamp, signature string, ok bool) {
+	id, timestamp, signature = h.Get(n.ID), h.Get(n.Timestamp), h.Get(n.Signature)
+	return id, timestamp, signature, id != "" && timestamp != "" && signature != ""
+}
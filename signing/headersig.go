@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SchemeV1H is HMAC-SHA256 over the v1 signed content plus the target URL and
+// selected request headers, so a captured body and signature cannot be
+// replayed to another endpoint or with altered metadata
+const SchemeV1H = "v1h"
+
+// SignedHeadersHeader lists the headers a v1h signature covers, lowercase and comma-separated
+const SignedHeadersHeader = "Webhook-Signed-Headers"
+
+// CanonicalHeaders renders the metadata covered by a v1h signature:
+// "url:{targetURL}\n", then "{name}:{value}\n" for each header in order, then "\n"
+func CanonicalHeaders(targetURL string, h http.Header, names []string) []byte {
+	var b strings.Builder
+	b.WriteString("url:" + targetURL + "\n")
+	for _, name := range names {
+		b.WriteString(strings.ToLower(name) + ":" + strings.TrimSpace(h.Get(name)) + "\n")
+	}
+	b.WriteString("\n")
+	return []byte(b.String())
+}
+
+// SignV1H returns the "v1h,{base64}" signature over the canonical headers
+// followed by the v1 signed content
+func SignV1H(key []byte, msgID string, timestamp time.Time, targetURL string, h http.Header, names []string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(CanonicalHeaders(targetURL, h, names))
+	mac.Write(SignedContent(msgID, timestamp, body))
+	return SchemeV1H + "," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignedHeaderNames parses the SignedHeadersHeader value
+func SignedHeaderNames(h http.Header) []string {
+	var out []string
+	for _, name := range strings.Split(h.Get(SignedHeadersHeader), ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// VerifyV1H checks that header contains a v1h signature over the message as
+// delivered to targetURL, and that every required header is among those signed
+func VerifyV1H(key []byte, msgID string, timestamp time.Time, targetURL string, h http.Header, body []byte, header string, required []string) error {
+	names := SignedHeaderNames(h)
+	for _, req := range required {
+		if !containsFold(names, req) {
+			return fmt.Errorf("%w: %s is not covered by the signature", ErrInvalidHeader, req)
+		}
+	}
+
+	sigs, err := ParseSignatures(header)
+	if err != nil {
+		return err
+	}
+
+	expected := SignV1H(key, msgID, timestamp, targetURL, h, names, body)
+	for _, s := range sigs {
+		if s.Version == SchemeV1H && Equal(s.String(), expected) {
+			return nil
+		}
+	}
+	return ErrNoMatchingSignature
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
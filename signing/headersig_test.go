@@ -0,0 +1,60 @@
+package signing
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyV1H(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"order.created"}`)
+	names := []string{"Content-Type", "Idempotency-Key"}
+
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	h.Set("Idempotency-Key", "order-123")
+	h.Set(SignedHeadersHeader, "content-type,idempotency-key")
+	sig := SignV1H(testKey, "msg_1", ts, "https://example.com/hooks", h, names, body)
+
+	with := func(name, value string) http.Header {
+		c := h.Clone()
+		c.Set(name, value)
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		header   http.Header
+		required []string
+		wantErr  error
+	}{
+		{name: "valid", url: "https://example.com/hooks", header: h},
+		{name: "valid with required", url: "https://example.com/hooks", header: h, required: []string{"idempotency-key"}},
+		{name: "other endpoint", url: "https://example.com/admin", header: h, wantErr: ErrNoMatchingSignature},
+		{name: "altered idempotency key", url: "https://example.com/hooks", header: with("Idempotency-Key", "order-456"), wantErr: ErrNoMatchingSignature},
+		{name: "dropped signed header", url: "https://example.com/hooks", header: with(SignedHeadersHeader, "content-type"), wantErr: ErrNoMatchingSignature},
+		{name: "required header unsigned", url: "https://example.com/hooks", header: h, required: []string{"Webhook-Ordering-Key"}, wantErr: ErrInvalidHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyV1H(testKey, "msg_1", ts, tt.url, tt.header, body, "v1,ignored "+sig, tt.required)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCanonicalHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", " application/json ")
+	got := string(CanonicalHeaders("https://example.com/hooks", h, []string{"Content-Type", "Idempotency-Key"}))
+	want := "url:https://example.com/hooks\ncontent-type:application/json\nidempotency-key:\n\n"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
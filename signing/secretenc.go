@@ -0,0 +1,98 @@
+package signing
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// SecretEncoding names how an HMAC secret string encodes its key bytes
+type SecretEncoding string
+
+const (
+	EncodingBase64 SecretEncoding = "base64" // whsec_-prefixed or bare standard base64 (default)
+	EncodingHex    SecretEncoding = "hex"    // Hex digits, as some providers issue
+	EncodingRaw    SecretEncoding = "raw"    // The string's bytes are the key
+)
+
+// EncodeSecret returns the whsec_ form of raw key bytes
+func EncodeSecret(key []byte) string {
+	return SecretPrefix + base64.StdEncoding.EncodeToString(key)
+}
+
+// DecodeSecretAs decodes secret under enc, with errors that name the likely
+// mismatch, e.g. a hex secret given where base64 is expected
+func DecodeSecretAs(secret string, enc SecretEncoding) ([]byte, error) {
+	var key []byte
+	switch enc {
+	case EncodingBase64, "":
+		bare, prefixed := strings.CutPrefix(secret, SecretPrefix)
+		if !prefixed && looksHex(bare) {
+			return nil, fmt.Errorf("%w: secret looks hex-encoded but the %s scheme expects base64; use the %s encoding", ErrInvalidSecret, SecretPrefix, EncodingHex)
+		}
+		var err error
+		if key, err = base64.StdEncoding.DecodeString(bare); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSecret, base64Hint(bare, err))
+		}
+	case EncodingHex:
+		if strings.HasPrefix(secret, SecretPrefix) {
+			return nil, fmt.Errorf("%w: secret has the %s prefix, which marks base64, but %s was requested", ErrInvalidSecret, SecretPrefix, enc)
+		}
+		var err error
+		if key, err = hex.DecodeString(strings.TrimPrefix(secret, "0x")); err != nil {
+			if strings.ContainsAny(secret, "+/=") {
+				return nil, fmt.Errorf("%w: secret looks base64-encoded but %s was requested", ErrInvalidSecret, enc)
+			}
+			return nil, fmt.Errorf("%w: secret is not valid hex: %v", ErrInvalidSecret, err)
+		}
+	case EncodingRaw:
+		if strings.HasPrefix(secret, SecretPrefix) {
+			return nil, fmt.Errorf("%w: secret has the %s prefix, which marks base64, but %s was requested", ErrInvalidSecret, SecretPrefix, enc)
+		}
+		key = []byte(secret)
+	default:
+		return nil, fmt.Errorf("%w: unknown secret encoding %q", ErrInvalidSecret, enc)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidSecret)
+	}
+	return key, nil
+}
+
+// NormalizeSecret rewrites an HMAC secret given in enc to its whsec_ form.
+// Ed25519 keys (whsk_, whpk_) are returned unchanged.
+func NormalizeSecret(secret string, enc SecretEncoding) (string, error) {
+	if strings.HasPrefix(secret, SecretKeyPrefix) || strings.HasPrefix(secret, PublicKeyPrefix) {
+		return secret, nil
+	}
+	key, err := DecodeSecretAs(secret, enc)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSecret(key), nil
+}
+
+// looksHex reports whether s is long, even-length and only hex digits, which
+// a base64 secret almost never is
+func looksHex(s string) bool {
+	if len(s) < 32 || len(s)%2 != 0 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// base64Hint explains why s failed to decode as standard base64
+func base64Hint(s string, err error) string {
+	switch {
+	case strings.ContainsAny(s, "-_"):
+		return "secret looks base64url-encoded but standard base64 (+ and /) is expected"
+	case strings.ContainsAny(s, " \t\r\n"):
+		return "secret contains whitespace"
+	case len(s)%4 != 0:
+		return "secret is missing base64 padding"
+	default:
+		return err.Error()
+	}
+}
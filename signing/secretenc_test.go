@@ -0,0 +1,59 @@
+package signing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSecretAs(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	for _, tc := range []struct {
+		secret string
+		enc    SecretEncoding
+	}{
+		{EncodeSecret(key), EncodingBase64},
+		{strings.TrimPrefix(EncodeSecret(key), SecretPrefix), ""},
+		{"30313233343536373839616263646566" + "30313233343536373839616263646566", EncodingHex},
+		{"0x" + "30313233343536373839616263646566" + "30313233343536373839616263646566", EncodingHex},
+		{string(key), EncodingRaw},
+	} {
+		got, err := DecodeSecretAs(tc.secret, tc.enc)
+		if err != nil || !bytes.Equal(got, key) {
+			t.Errorf("Expected %q as %q to decode, got %q %v", tc.secret, tc.enc, got, err)
+		}
+	}
+}
+
+func TestDecodeSecretAs_Mismatches(t *testing.T) {
+	for _, tc := range []struct {
+		secret string
+		enc    SecretEncoding
+		hint   string
+	}{
+		{"3031323334353637383961626364656630313233343536373839616263646566", EncodingBase64, "looks hex-encoded"},
+		{"whsec_abc-def_ghi", EncodingBase64, "base64url"},
+		{"whsec_abc", EncodingBase64, "padding"},
+		{"whsec_MDEyMzQ1Njc4OWFiY2RlZg==", EncodingHex, "marks base64"},
+		{"MDEyMzQ1Njc4OWFiY2RlZg==", EncodingHex, "looks base64-encoded"},
+		{"whsec_MDEyMzQ1Njc4OWFiY2RlZg==", EncodingRaw, "marks base64"},
+		{"abc", "base32", "unknown secret encoding"},
+	} {
+		_, err := DecodeSecretAs(tc.secret, tc.enc)
+		if !errors.Is(err, ErrInvalidSecret) || !strings.Contains(err.Error(), tc.hint) {
+			t.Errorf("Expected %q as %q to fail with %q, got %v", tc.secret, tc.enc, tc.hint, err)
+		}
+	}
+}
+
+func TestNormalizeSecret(t *testing.T) {
+	got, err := NormalizeSecret("00ff", EncodingHex)
+	if err != nil || got != EncodeSecret([]byte{0x00, 0xff}) {
+		t.Errorf("Expected whsec_ form, got %q %v", got, err)
+	}
+	if got, _ := NormalizeSecret("whsk_abc", EncodingHex); got != "whsk_abc" {
+		t.Errorf("Expected Ed25519 keys to pass through, got %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"time"
+)
+
+// Signer produces one versioned signature for a message
+type Signer interface {
+	Version() string
+	Sign(msgID string, timestamp time.Time, body []byte) string
+}
+
+// Verifier checks a signature header against a message
+type Verifier interface {
+	Verify(msgID string, timestamp time.Time, body []byte, header string) error
+}
+
+// NewSigner returns a v1a signer for whsk_ keys and a v1 signer otherwise
+func NewSigner(secret string) (Signer, error) {
+	if strings.HasPrefix(secret, SecretKeyPrefix) {
+		priv, err := DecodeSecretKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519Signer{priv}, nil
+	}
+
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return hmacSigner{key}, nil
+}
+
+// NewVerifier returns a v1a verifier for whpk_ (or whsk_) keys and a v1 verifier otherwise
+func NewVerifier(secret string) (Verifier, error) {
+	switch {
+	case strings.HasPrefix(secret, PublicKeyPrefix):
+		pub, err := DecodePublicKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519Verifier{pub}, nil
+	case strings.HasPrefix(secret, SecretKeyPrefix):
+		priv, err := DecodeSecretKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519Verifier{priv.Public().(ed25519.PublicKey)}, nil
+	}
+
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	return hmacSigner{key}, nil
+}
+
+type hmacSigner struct{ key []byte }
+
+func (s hmacSigner) Version() string { return SchemeV1 }
+
+func (s hmacSigner) Sign(msgID string, timestamp time.Time, body []byte) string {
+	return SignV1(s.key, msgID, timestamp, body)
+}
+
+func (s hmacSigner) Verify(msgID string, timestamp time.Time, body []byte, header string) error {
+	return VerifyV1(s.key, msgID, timestamp, body, header)
+}
+
+type ed25519Signer struct{ priv ed25519.PrivateKey }
+
+func (s ed25519Signer) Version() string { return SchemeV1a }
+
+func (s ed25519Signer) Sign(msgID string, timestamp time.Time, body []byte) string {
+	return SignV1a(s.priv, msgID, timestamp, body)
+}
+
+type ed25519Verifier struct{ pub ed25519.PublicKey }
+
+func (v ed25519Verifier) Verify(msgID string, timestamp time.Time, body []byte, header string) error {
+	return VerifyV1a(v.pub, msgID, timestamp, body, header)
+}
@@ -0,0 +1,89 @@
+package signing
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testKey, _ = DecodeSecret("whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc=")
+
+func TestDecodeSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "prefixed", secret: "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc=", wantErr: false},
+		{name: "bare", secret: "C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc=", wantErr: false},
+		{name: "not base64", secret: "whsec_not base64!", wantErr: true},
+		{name: "empty", secret: "whsec_", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeSecret(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSecret) {
+				t.Errorf("Expected ErrInvalidSecret, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyV1(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	body := []byte(`{"a":1}`)
+	sig := SignV1(testKey, "msg_1", ts, body)
+
+	tests := []struct {
+		name    string
+		body    []byte
+		header  string
+		wantErr error
+	}{
+		{name: "single", body: body, header: sig},
+		{name: "multiple with rotation", body: body, header: "v1,b2xk " + sig},
+		{name: "unknown versions skipped", body: body, header: "v2,abc " + sig},
+		{name: "tampered body", body: []byte(`{"a":2}`), header: sig, wantErr: ErrNoMatchingSignature},
+		{name: "empty header", body: body, header: "", wantErr: ErrInvalidHeader},
+		{name: "malformed entries", body: body, header: "v1 ,abc", wantErr: ErrInvalidHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyV1(testKey, "msg_1", ts, tt.body, tt.header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyV1() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzParseSignatures(f *testing.F) {
+	f.Add(SignV1(testKey, "msg_1", time.Unix(1700000000, 0), []byte(`{}`)))
+	f.Add("v1,b2xk v1a,c2ln v1h,aGRy")
+	f.Add("v1, ,abc v1,,,")
+	f.Add("\x00v1,\xff")
+
+	f.Fuzz(func(t *testing.T, header string) {
+		sigs, err := ParseSignatures(header)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidHeader) || sigs != nil {
+				t.Fatalf("Unexpected result %v, %v", sigs, err)
+			}
+			return
+		}
+		for _, s := range sigs {
+			if s.Version == "" || s.Value == "" {
+				t.Fatalf("Expected no empty entries, got %+v", s)
+			}
+		}
+		// A header that is not our signature must never verify
+		if VerifyV1(testKey, "msg_1", time.Unix(1700000000, 0), []byte(`{"fuzz":1}`), header) == nil {
+			t.Fatalf("Header %q verified without the right signature", header)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package signing
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StripeSignature is a parsed Stripe-Signature header, "t=...,v1=...[,v1=...]"
+type StripeSignature struct {
+	Timestamp int64    // Unix seconds from t=
+	V1        []string // Hex HMAC-SHA256 signatures from v1=
+}
+
+// ParseStripeSignature parses a Stripe-Signature header. Unknown schemes
+// (e.g. v0=) are ignored; a missing or repeated t=, a non-numeric timestamp
+// or no v1= entry is ErrInvalidHeader.
+func ParseStripeSignature(header string) (StripeSignature, error) {
+	var out StripeSignature
+	var ts string
+	seenTS := false
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "t":
+			if seenTS {
+				return StripeSignature{}, ErrInvalidHeader
+			}
+			ts, seenTS = v, true
+		case "v1":
+			if v != "" {
+				out.V1 = append(out.V1, v)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(out.V1) == 0 {
+		return StripeSignature{}, ErrInvalidHeader
+	}
+	out.Timestamp = unix
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package signing
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseStripeSignature(t *testing.T) {
+	got, err := ParseStripeSignature("t=1700000000, v1=abc,v0=old,v1=def")
+	if err != nil {
+		t.Fatalf("ParseStripeSignature() error = %v", err)
+	}
+	if got.Timestamp != 1700000000 || !slices.Equal(got.V1, []string{"abc", "def"}) {
+		t.Errorf("Unexpected result %+v", got)
+	}
+
+	for _, header := range []string{"", "v1=abc", "t=1,t=2,v1=abc", "t=x,v1=abc", "t=1", "t=1,v1="} {
+		if _, err := ParseStripeSignature(header); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("%q: expected ErrInvalidHeader, got %v", header, err)
+		}
+	}
+}
+
+func FuzzParseStripeSignature(f *testing.F) {
+	f.Add("t=1700000000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd")
+	f.Add("t=1,v1=a,v1=b,v0=c")
+	f.Add(",,=,t=,v1")
+	f.Add("t=-9223372036854775808,v1=\xff")
+
+	f.Fuzz(func(t *testing.T, header string) {
+		got, err := ParseStripeSignature(header)
+		if err != nil {
+			return
+		}
+		for _, v := range got.V1 {
+			if v == "" {
+				t.Fatalf("Expected no empty v1 entries in %q", header)
+			}
+		}
+	})
+}
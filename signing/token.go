@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenParam is the query parameter carrying a delivery token
+const TokenParam = "hookshot_token"
+
+// Token errors
+var (
+	ErrInvalidToken = errors.New("signing: invalid token")
+	ErrTokenExpired = errors.New("signing: token expired")
+)
+
+// tokenHeader is the fixed JOSE header: only HS256 is issued or accepted
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenClaims are the claims of a delivery token
+type TokenClaims struct {
+	MessageID  string `json:"jti"`
+	IssuedAt   int64  `json:"iat"`
+	ExpiresAt  int64  `json:"exp"`
+	BodySHA256 string `json:"body_sha256"` // Unpadded base64url SHA-256 of the body
+}
+
+// SignToken issues a short-lived HS256 JWT binding msgID and the body hash,
+// for receivers that can read a query parameter but not custom headers
+func SignToken(key []byte, msgID string, issued, expires time.Time, body []byte) string {
+	sum := sha256.Sum256(body)
+	claims, _ := json.Marshal(TokenClaims{
+		MessageID:  msgID,
+		IssuedAt:   issued.Unix(),
+		ExpiresAt:  expires.Unix(),
+		BodySHA256: base64.RawURLEncoding.EncodeToString(sum[:]),
+	})
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return unsigned + "." + tokenMAC(key, unsigned)
+}
+
+// VerifyToken checks the token's signature, expiry at now and body hash
+func VerifyToken(key []byte, token string, body []byte, now time.Time) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	if parts[0] != tokenHeader {
+		return nil, fmt.Errorf("%w: unsupported header", ErrInvalidToken)
+	}
+	if !Equal(parts[2], tokenMAC(key, parts[0]+"."+parts[1])) {
+		return nil, ErrNoMatchingSignature
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	var claims TokenClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	sum := sha256.Sum256(body)
+	if claims.BodySHA256 != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		return nil, fmt.Errorf("%w: body hash mismatch", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+func tokenMAC(key []byte, unsigned string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToken(t *testing.T) {
+	key := testKey
+	issued := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"order.created"}`)
+	token := SignToken(key, "msg_1", issued, issued.Add(time.Minute), body)
+
+	claims, err := VerifyToken(key, token, body, issued.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if claims.MessageID != "msg_1" || claims.IssuedAt != issued.Unix() || claims.ExpiresAt != issued.Add(time.Minute).Unix() {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+
+	parts := strings.Split(token, ".")
+	tests := []struct {
+		name  string
+		key   []byte
+		token string
+		body  []byte
+		now   time.Time
+		want  error
+	}{
+		{name: "expired", key: key, token: token, body: body, now: issued.Add(time.Minute), want: ErrTokenExpired},
+		{name: "tampered body", key: key, token: token, body: []byte(`{}`), now: issued, want: ErrInvalidToken},
+		{name: "wrong key", key: []byte("other"), token: token, body: body, now: issued, want: ErrNoMatchingSignature},
+		{name: "alg none", key: key, token: "eyJhbGciOiJub25lIn0." + parts[1] + ".", body: body, now: issued, want: ErrInvalidToken},
+		{name: "malformed", key: key, token: "abc", body: body, now: issued, want: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyToken(tt.key, tt.token, tt.body, tt.now); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func FuzzVerifyToken(f *testing.F) {
+	issued := time.Unix(1700000000, 0)
+	body := []byte(`{}`)
+	f.Add(SignToken(testKey, "msg_1", issued, issued.Add(time.Minute), body))
+	f.Add("a.b.c")
+	f.Add("..")
+	f.Add("eyJhbGciOiJub25lIn0.e30.")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		claims, err := VerifyToken(testKey, token, body, issued)
+		if err == nil && token != SignToken(testKey, claims.MessageID, time.Unix(claims.IssuedAt, 0), time.Unix(claims.ExpiresAt, 0), body) {
+			t.Fatalf("Token %q verified without being issued by SignToken", token)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package signing
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+func TestVectors(t *testing.T) {
+	vectors, err := Vectors()
+	if err != nil {
+		t.Fatalf("Failed to load vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("Expected embedded vectors")
+	}
+
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			if err := VerifyVector(v); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestVectors_SvixCompatible cross-checks v1 vectors against the svix SDK
+func TestVectors_SvixCompatible(t *testing.T) {
+	vectors, _ := Vectors()
+
+	for _, v := range vectors {
+		if v.Scheme != SchemeV1 {
+			continue
+		}
+		t.Run(v.Name, func(t *testing.T) {
+			wh, err := svix.NewWebhook(v.Secret)
+			if err != nil {
+				t.Fatalf("svix rejected secret: %v", err)
+			}
+			header := http.Header{}
+			header.Set("svix-id", v.MsgID)
+			header.Set("svix-timestamp", strconv.FormatInt(v.Timestamp, 10))
+			header.Set("svix-signature", v.Signature)
+			if err := wh.VerifyIgnoringTimestamp([]byte(v.Body), header); err != nil {
+				t.Errorf("svix failed to verify vector: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyVector_Mismatch(t *testing.T) {
+	v := Vector{
+		Name:          "bad",
+		Scheme:        SchemeV1,
+		Secret:        "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc=",
+		MsgID:         "msg_1",
+		Timestamp:     time.Unix(1700000000, 0).Unix(),
+		Body:          "{}",
+		SignedContent: "msg_1.1700000000.{}",
+		Signature:     "v1,AAAA",
+	}
+	if err := VerifyVector(v); err == nil {
+		t.Error("Expected mismatch error")
+	}
+}
+
+func TestVectors_CoverEveryScheme(t *testing.T) {
+	vectors, _ := Vectors()
+	seen := make(map[string]bool)
+	for _, v := range vectors {
+		seen[v.Scheme] = true
+	}
+	for _, scheme := range []string{SchemeV1, SchemeV1a, SchemeV1H} {
+		if !seen[scheme] {
+			t.Errorf("Expected vectors for scheme %s", scheme)
+		}
+	}
+}
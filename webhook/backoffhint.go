@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackoffHeader lets receivers request a retry delay, in seconds, on 5xx
+// responses. Retry-After is honored the same way when it is absent.
+const BackoffHeader = "Webhook-Backoff-Seconds"
+
+// WithMaxBackoffHint bounds how long a receiver-requested delay may be
+// (default: MaxInterval); longer hints are clamped to it
+func WithMaxBackoffHint(d time.Duration) Option {
+	return func(c *Config) {
+		c.MaxBackoffHint = d
+	}
+}
+
+// backoffHint reads the receiver's requested delay from a response
+func backoffHint(h http.Header, now time.Time) (time.Duration, bool) {
+	if v := strings.TrimSpace(h.Get(BackoffHeader)); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+	}
+
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoffHint(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{name: "none", header: http.Header{}},
+		{name: "backoff seconds", header: http.Header{BackoffHeader: {"7"}}, want: 7 * time.Second, ok: true},
+		{name: "fractional", header: http.Header{BackoffHeader: {"1.5"}}, want: 1500 * time.Millisecond, ok: true},
+		{name: "retry-after seconds", header: http.Header{"Retry-After": {"12"}}, want: 12 * time.Second, ok: true},
+		{name: "retry-after date", header: http.Header{"Retry-After": {now.Add(90 * time.Second).Format(http.TimeFormat)}}, want: 90 * time.Second, ok: true},
+		{name: "backoff header wins", header: http.Header{BackoffHeader: {"3"}, "Retry-After": {"60"}}, want: 3 * time.Second, ok: true},
+		{name: "negative", header: http.Header{BackoffHeader: {"-1"}}},
+		{name: "garbage", header: http.Header{"Retry-After": {"soon"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := backoffHint(tt.header, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Expected %v/%v, got %v/%v", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestClient_HonorsBackoffHint(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set(BackoffHeader, "4")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set(BackoffHeader, "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	det, sched := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret,
+		WithDeterminism(det), WithMaxRetries(5), WithMaxBackoffHint(10*time.Second))
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	want := []time.Duration{4 * time.Second, 10 * time.Second}
+	if len(sched.delays) != len(want) {
+		t.Fatalf("Expected delays %v, got %v", want, sched.delays)
+	}
+	for i := range want {
+		if sched.delays[i] != want[i] {
+			t.Errorf("Expected delay %d to be %v, got %v", i, want[i], sched.delays[i])
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// BatchEvent names the deliveries sent by SendBatch
+const BatchEvent = ReservedPrefix + "batch"
+
+// ErrPartialBatch marks a batch some of whose items the receiver did not accept
+var ErrPartialBatch = errors.New("webhook: batch partially accepted")
+
+// BatchItem is one payload in a batch body, with its own message ID
+type BatchItem struct {
+	ID string `json:"id"`
+	Payload
+}
+
+// BatchResults is the per-item response a receiver may return for a batch,
+// typically with 207 Multi-Status. Any 2xx without it accepts every item.
+type BatchResults struct {
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult is the receiver's verdict on one batch item
+type BatchResult struct {
+	ID     string `json:"id"`
+	Status int    `json:"status"` // 2xx accepts, 4xx rejects for good, anything else retries
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchItemResult is the outcome of one item of a SendBatch call
+type BatchItemResult struct {
+	MessageID  string
+	Event      string
+	Success    bool
+	StatusCode int   // Item status from the receiver, or the batch status without per-item results
+	Error      error // Latest reason the item was not accepted
+	Duplicate  bool  // Suppressed by the dedup window and left out of the batch; MessageID is the original send's
+}
+
+// BatchResponse contains the result of a SendBatch call. The embedded
+// Response describes the batch messages; Success is set only when every item
+// was accepted.
+type BatchResponse struct {
+	Response
+	Items []BatchItemResult // In the order of the payloads
+}
+
+// batchState tracks the items of a batch across retries
+type batchState struct {
+	items   []BatchItem
+	encoded [][]byte          // Item bodies after transforms, aligned with items
+	results []BatchItemResult // Aligned with items
+	pending []int             // Indexes of items still to be accepted
+}
+
+// SendBatch delivers payloads as one signed BatchEvent message whose data is
+// the list of BatchItems. When the receiver answers with BatchResults, items
+// rejected with a 4xx fail for good and only those that failed otherwise, or
+// are missing from the results, are sent again. Each such round is a new
+// message with its own ID and a body of the remaining items; item IDs never
+// change, so receivers deduplicate by item ID. Retries of the whole batch
+// after a network error or 5xx keep the message ID. All rounds share the
+// client's retry budget.
+//
+// Every item goes through the same pipeline as SendPayload: name policies,
+// enrichment, body transforms (applied to each item, its id included),
+// payload metrics and the dedup window, whose duplicates are left out of the
+// batch and reported in their BatchItemResult.
+func (c *Client) SendBatch(ctx context.Context, payloads []Payload, opts ...SendOption) BatchResponse {
+	var so sendOptions
+	for _, opt := range opts {
+		opt(&so)
+	}
+	if len(payloads) == 0 {
+		return BatchResponse{Response: Response{Error: fmt.Errorf("%w: batch has no payloads", ErrInvalidEvent)}}
+	}
+
+	n := len(payloads)
+	b := &batchState{items: make([]BatchItem, n), encoded: make([][]byte, n), results: make([]BatchItemResult, n)}
+	dedupHashes := make([][sha256.Size]byte, n)
+	for i, payload := range payloads {
+		id := c.det.NewID()
+		payload, encoded, err := c.encode(ctx, payload, func(p Payload) any { return BatchItem{ID: id, Payload: p} })
+		if err != nil {
+			c.releaseBatch(b, dedupHashes)
+			return BatchResponse{Response: Response{Error: fmt.Errorf("item %d: %w", i, err)}}
+		}
+		b.items[i], b.encoded[i] = BatchItem{ID: id, Payload: payload}, encoded
+		b.results[i] = BatchItemResult{MessageID: id, Event: payload.Event}
+
+		if c.dedup != nil {
+			if dedupHashes[i], err = dedupKey(payload); err != nil {
+				c.releaseBatch(b, dedupHashes)
+				return BatchResponse{Response: Response{Error: fmt.Errorf("item %d: webhook: failed to marshal payload: %w", i, err)}}
+			}
+			if original, dup := c.dedup.claim(dedupHashes[i], id, c.det.Now()); dup {
+				c.logger.Info("webhook: duplicate payload suppressed", "event", payload.Event, "original", original)
+				b.results[i].MessageID, b.results[i].Duplicate = original, true
+				continue
+			}
+		}
+		b.pending = append(b.pending, i)
+	}
+	if len(b.pending) == 0 {
+		return BatchResponse{Response: Response{Duplicate: true}, Items: slices.Clone(b.results)}
+	}
+	body, err := b.body(c)
+	if err != nil {
+		c.releaseBatch(b, dedupHashes)
+		return BatchResponse{Response: Response{Error: fmt.Errorf("webhook: failed to marshal payload: %w", err)}}
+	}
+
+	msgID := c.det.NewID()
+	if so.idempotencyKey != "" {
+		msgID = idempotentMessageID(so.idempotencyKey)
+	}
+	if so.assigned != nil {
+		so.assigned(msgID)
+	}
+	d := delivery{
+		body:   body,
+		msgID:  msgID,
+		event:  BatchEvent,
+		tenant: TenantFromContext(ctx),
+		header: make(http.Header),
+		batch:  b,
+	}
+	c.transition(d, StateAccepted, 0)
+
+	resp := c.dispatch(ctx, d, so)
+	if !resp.Deferred && !resp.Parked {
+		c.releaseBatch(b, dedupHashes)
+	}
+	return BatchResponse{Response: resp, Items: slices.Clone(b.results)}
+}
+
+// releaseBatch forgets the dedup claims of items that were not accepted, so
+// re-emitting them goes through
+func (c *Client) releaseBatch(b *batchState, hashes [][sha256.Size]byte) {
+	if c.dedup == nil {
+		return
+	}
+	for i, r := range b.results {
+		if !r.Success && !r.Duplicate && b.items[i].ID != "" {
+			c.dedup.release(hashes[i], r.MessageID)
+		}
+	}
+}
+
+// body encodes the pending items as a BatchEvent payload, embedding each
+// item's transformed bytes unchanged
+func (b *batchState) body(c *Client) ([]byte, error) {
+	items := make([]json.RawMessage, len(b.pending))
+	for i, idx := range b.pending {
+		items[i] = b.encoded[idx]
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Payload{Event: BatchEvent, Timestamp: c.det.Now(), Data: items}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// settle applies a 2xx batch response to the pending items. more reports that
+// some are worth sending again; err is set unless every item was accepted.
+func (b *batchState) settle(status int, body []byte) (more bool, err error) {
+	var parsed BatchResults
+	if json.Unmarshal(body, &parsed) != nil || parsed.Results == nil {
+		for _, idx := range b.pending {
+			b.results[idx].Success, b.results[idx].StatusCode, b.results[idx].Error = true, status, nil
+		}
+		b.pending = nil
+		return false, b.failure()
+	}
+
+	verdicts := make(map[string]BatchResult, len(parsed.Results))
+	for _, r := range parsed.Results {
+		verdicts[r.ID] = r
+	}
+	var retry []int
+	for _, idx := range b.pending {
+		res := &b.results[idx]
+		v, ok := verdicts[res.MessageID]
+		res.StatusCode = v.Status
+		switch {
+		case !ok:
+			res.Error = fmt.Errorf("%w: item missing from batch results", ErrServerError)
+			retry = append(retry, idx)
+		case v.Status >= 200 && v.Status < 300:
+			res.Success, res.Error = true, nil
+		case v.Status >= 400 && v.Status < 500:
+			res.Error = fmt.Errorf("%w: status %d: %s", ErrClientError, v.Status, v.Error)
+		default:
+			res.Error = fmt.Errorf("%w: status %d: %s", ErrServerError, v.Status, v.Error)
+			retry = append(retry, idx)
+		}
+	}
+	b.pending = retry
+	return len(retry) > 0, b.failure()
+}
+
+// failure summarises the items not accepted so far, or returns nil
+func (b *batchState) failure() error {
+	if b.accepted() {
+		return nil
+	}
+	failed, sent := 0, 0
+	for _, r := range b.results {
+		if r.Duplicate {
+			continue
+		}
+		sent++
+		if !r.Success {
+			failed++
+		}
+	}
+	return fmt.Errorf("%w: %d of %d items not accepted", ErrPartialBatch, failed, sent)
+}
+
+// accepted reports whether every item sent was accepted
+func (b *batchState) accepted() bool {
+	for _, r := range b.results {
+		if !r.Success && !r.Duplicate {
+			return false
+		}
+	}
+	return true
+}
+
+// nextRound ends the current batch message, which the receiver took, and
+// replaces it with a new one carrying only the pending items
+func (c *Client) nextRound(d *delivery, attempts []Attempt) {
+	c.emitOutcome(StageSent, *d, attempts)
+
+	// The items marshalled once already
+	d.body, _ = d.batch.body(c)
+	d.msgID = c.det.NewID()
+	c.transition(*d, StateAccepted, 0)
+	c.seal(d)
+}
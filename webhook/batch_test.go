@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// batchRequest is the message ID and item IDs of one batch request
+type batchRequest struct {
+	msgID string
+	items []string
+}
+
+// batchServer answers each batch request with the next reply, then with 200
+func batchServer(t *testing.T, replies ...func(w http.ResponseWriter, items []string)) (*httptest.Server, *[]batchRequest) {
+	t.Helper()
+	var got []batchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload struct {
+			Event string      `json:"event"`
+			Data  []BatchItem `json:"data"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Event != BatchEvent {
+			t.Errorf("Expected a %s payload, got %s", BatchEvent, body)
+		}
+		req := batchRequest{msgID: r.Header.Get("svix-id")}
+		for _, item := range payload.Data {
+			req.items = append(req.items, item.ID)
+		}
+		got = append(got, req)
+		if n := len(got) - 1; n < len(replies) {
+			replies[n](w, req.items)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &got
+}
+
+// reply writes per-item results with the given statuses, in item order
+func reply(statuses ...int) func(w http.ResponseWriter, items []string) {
+	return func(w http.ResponseWriter, items []string) {
+		var results BatchResults
+		for i, id := range items {
+			if i < len(statuses) && statuses[i] != 0 {
+				results.Results = append(results.Results, BatchResult{ID: id, Status: statuses[i], Error: "nope"})
+			}
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(results)
+	}
+}
+
+func testBatch() []Payload {
+	return []Payload{
+		{Event: "order.created", Data: 1},
+		{Event: "order.created", Data: 2},
+		{Event: "order.created", Data: 3},
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	server, got := batchServer(t)
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det))
+
+	resp := client.SendBatch(context.Background(), testBatch())
+	if !resp.Success || resp.Error != nil {
+		t.Fatalf("Expected the batch accepted, got %v", resp.Error)
+	}
+	if len(*got) != 1 || !slices.Equal((*got)[0].items, []string{"msg_0001", "msg_0002", "msg_0003"}) {
+		t.Errorf("Expected one request with every item, got %+v", *got)
+	}
+	for _, item := range resp.Items {
+		if !item.Success || item.StatusCode != http.StatusOK {
+			t.Errorf("Expected item accepted with the batch status, got %+v", item)
+		}
+	}
+}
+
+func TestSendBatch_RetriesOnlyFailedItems(t *testing.T) {
+	server, got := batchServer(t, reply(http.StatusOK, http.StatusServiceUnavailable))
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(3))
+
+	resp := client.SendBatch(context.Background(), testBatch())
+	if !resp.Success {
+		t.Fatalf("Expected every item accepted in the end, got %v", resp.Error)
+	}
+	if len(*got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(*got))
+	}
+	first, second := (*got)[0], (*got)[1]
+	if !slices.Equal(second.items, []string{"msg_0002", "msg_0003"}) {
+		t.Errorf("Expected the failed and missing items resent, got %v", second.items)
+	}
+	if first.msgID == second.msgID || resp.MessageID != second.msgID {
+		t.Errorf("Expected the resend to be a new message, got %s then %s", first.msgID, second.msgID)
+	}
+	if state, _ := client.State(first.msgID); state != StateDelivered {
+		t.Errorf("Expected the first round delivered, got %s", state)
+	}
+	if len(resp.Attempts) != 2 || resp.Attempts[0].Error == nil {
+		t.Errorf("Expected the partial attempt recorded as failed, got %+v", resp.Attempts)
+	}
+}
+
+func TestSendBatch_RejectedItems(t *testing.T) {
+	server, got := batchServer(t, reply(http.StatusOK, http.StatusUnprocessableEntity, http.StatusOK))
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(3))
+
+	resp := client.SendBatch(context.Background(), testBatch())
+	if resp.Success || !errors.Is(resp.Error, ErrPartialBatch) {
+		t.Fatalf("Expected a partial batch error, got %v", resp.Error)
+	}
+	if len(*got) != 1 {
+		t.Errorf("Expected rejected items not retried, got %d requests", len(*got))
+	}
+	item := resp.Items[1]
+	if item.Success || item.StatusCode != http.StatusUnprocessableEntity || !errors.Is(item.Error, ErrClientError) {
+		t.Errorf("Expected the item rejected, got %+v", item)
+	}
+	if !resp.Items[0].Success || !resp.Items[2].Success {
+		t.Errorf("Expected the other items accepted, got %+v", resp.Items)
+	}
+}
+
+func TestSendBatch_OutOfAttempts(t *testing.T) {
+	failing := reply(http.StatusOK, http.StatusInternalServerError, http.StatusOK)
+	server, got := batchServer(t, failing, reply(http.StatusInternalServerError))
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(2))
+
+	resp := client.SendBatch(context.Background(), testBatch())
+	if resp.Success || !errors.Is(resp.Error, ErrPartialBatch) || len(*got) != 2 {
+		t.Fatalf("Expected the batch to fail after 2 requests, got %v after %d", resp.Error, len(*got))
+	}
+	if item := resp.Items[1]; item.Success || !errors.Is(item.Error, ErrServerError) {
+		t.Errorf("Expected the item to keep its last error, got %+v", item)
+	}
+	if resp.State != StateFailed {
+		t.Errorf("Expected the batch failed, got %s", resp.State)
+	}
+}
+
+func TestSendBatch_WholeBatchRetry(t *testing.T) {
+	unavailable := func(w http.ResponseWriter, _ []string) { w.WriteHeader(http.StatusServiceUnavailable) }
+	server, got := batchServer(t, unavailable)
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(3))
+
+	resp := client.SendBatch(context.Background(), testBatch())
+	if !resp.Success || len(*got) != 2 {
+		t.Fatalf("Expected success after a retry, got %v after %d requests", resp.Error, len(*got))
+	}
+	if (*got)[0].msgID != (*got)[1].msgID || len((*got)[1].items) != 3 {
+		t.Errorf("Expected the whole batch retried under its message ID, got %+v", *got)
+	}
+}
+
+func TestSendBatch_Invalid(t *testing.T) {
+	client, _ := NewClient("http://localhost", testSecret)
+
+	if resp := client.SendBatch(context.Background(), nil); !errors.Is(resp.Error, ErrInvalidEvent) {
+		t.Errorf("Expected an empty batch to be refused, got %v", resp.Error)
+	}
+	resp := client.SendBatch(context.Background(), []Payload{{Event: "order.created"}, {Event: ReservedPrefix + "x"}})
+	if resp.Error == nil {
+		t.Error("Expected a reserved event name to be refused")
+	}
+}
+
+func TestSendBatch_SharesPayloadPipeline(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithCanonicalJSON(),
+		WithPayloadMetrics(), WithDedupWindow(time.Minute))
+
+	// The third item repeats the first and is suppressed by the dedup window
+	payloads := []Payload{
+		{Event: "order.created", Data: map[string]any{"z": "<b>", "a": 1}},
+		{Event: "order.created", Data: 2},
+		{Event: "order.created", Data: map[string]any{"a": 1, "z": "<b>"}},
+	}
+	resp := client.SendBatch(context.Background(), payloads)
+	if !resp.Success {
+		t.Fatalf("Expected the batch accepted, got %v", resp.Error)
+	}
+	if !resp.Items[2].Duplicate || resp.Items[2].MessageID != resp.Items[0].MessageID {
+		t.Errorf("Expected the repeated item suppressed as a duplicate of the first, got %+v", resp.Items[2])
+	}
+	if want := `"data":[{"data":{"a":1,"z":"<b>"},"event":"order.created","id":"msg_0001","timestamp":"0001-01-01T00:00:00Z"},`; !strings.Contains(string(body), want) {
+		t.Errorf("Expected canonical item bodies, got %s", body)
+	}
+	if stats := client.PayloadStats()["order.created"]; stats.Count != 3 {
+		t.Errorf("Expected payload metrics for every item, got %+v", stats)
+	}
+
+	// The same event through SendPayload is a duplicate too
+	if single := client.SendPayload(context.Background(), payloads[1]); !single.Duplicate {
+		t.Errorf("Expected SendPayload to share the batch's dedup window, got %+v", single)
+	}
+}
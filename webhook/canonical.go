@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// BodyTransform rewrites the marshaled payload before it is signed, so the
+// signature always covers the exact bytes put on the wire
+type BodyTransform func(body []byte) ([]byte, error)
+
+// WithBodyTransform appends transforms applied, in order, to every payload before signing
+func WithBodyTransform(fns ...BodyTransform) Option {
+	return func(c *Config) {
+		c.Transforms = append(c.Transforms, fns...)
+	}
+}
+
+// WithCanonicalJSON appends a CanonicalJSON step, giving byte-identical bodies
+// for equal payloads regardless of map order or escaping
+func WithCanonicalJSON() Option {
+	return WithBodyTransform(CanonicalJSON)
+}
+
+// CanonicalJSON re-encodes a JSON document with sorted object keys, no
+// insignificant whitespace and no HTML escaping. Numbers keep their original text.
+func CanonicalJSON(body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("canonical json: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("canonical json: trailing data after document")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("canonical json: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
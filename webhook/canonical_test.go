@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "sorts keys", in: `{"b":1,"a":{"d":2,"c":3}}`, want: `{"a":{"c":3,"d":2},"b":1}`},
+		{name: "strips whitespace", in: "{\n  \"a\": [1, 2]\n}\n", want: `{"a":[1,2]}`},
+		{name: "keeps number text", in: `{"n":1.50,"big":12345678901234567890}`, want: `{"big":12345678901234567890,"n":1.50}`},
+		{name: "no html escaping", in: `{"html":"<a href=\"x\">&</a>"}`, want: `{"html":"<a href=\"x\">&</a>"}`},
+		{name: "invalid", in: `{"a":`, wantErr: true},
+		{name: "trailing data", in: `{} {}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON([]byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CanonicalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_BodyTransform(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wrap := func(b []byte) ([]byte, error) {
+		return append(append([]byte(`{"envelope":`), b...), '}'), nil
+	}
+	client, _ := NewClient(server.URL, testSecret, WithBodyTransform(wrap), WithCanonicalJSON())
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{"z": 1, "a": "<b>"})
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	want := `{"envelope":{"data":{"a":"<b>","z":1},"event":"order.created","timestamp":`
+	if len(body) < len(want) || string(body[:len(want)]) != want {
+		t.Errorf("Expected transformed canonical body, got %s", body)
+	}
+
+	// The signature covers the transformed bytes
+	key, _ := signing.DecodeSecret(testSecret)
+	ts, _ := strconv.ParseInt(header.Get("svix-timestamp"), 10, 64)
+	if err := signing.VerifyV1(key, header.Get("svix-id"), time.Unix(ts, 0), body, header.Get("svix-signature")); err != nil {
+		t.Errorf("Expected signature over transformed body, got %v", err)
+	}
+
+	failing, _ := NewClient(server.URL, testSecret, WithBodyTransform(func([]byte) ([]byte, error) {
+		return nil, errors.New("codec unavailable")
+	}))
+	if resp := failing.Send(context.Background(), "order.created", map[string]any{}); resp.Error == nil {
+		t.Error("Expected transform error")
+	}
+}
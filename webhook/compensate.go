@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// Compensation undoes or records the effects of a delivery that failed for
+// good, e.g. marking a notification undeliverable in the application database
+type Compensation func(failed DeliveryEvent)
+
+// Compensator runs compensations registered by message ID when that delivery
+// reaches StageFailed, and drops them once it is sent. Deferred and parked
+// deliveries keep theirs until their outcome is known.
+type Compensator struct {
+	client *Client
+	stop   func()
+
+	mu      sync.Mutex
+	actions map[string]Compensation
+	running sync.WaitGroup
+}
+
+// NewCompensator watches client's deliveries until Close
+func NewCompensator(client *Client) *Compensator {
+	k := &Compensator{client: client, actions: make(map[string]Compensation)}
+	k.stop = client.ObserveDeliveries(k.observe)
+	return k
+}
+
+// Send sends payload with fn registered for its message ID before the first
+// attempt. Sends rejected before delivery starts, such as invalid event names
+// or duplicates, return their error without registering fn.
+func (k *Compensator) Send(ctx context.Context, payload Payload, fn Compensation, opts ...SendOption) Response {
+	opts = append(opts, func(o *sendOptions) {
+		o.assigned = func(msgID string) { k.Register(msgID, fn) }
+	})
+	return k.client.SendPayload(ctx, payload, opts...)
+}
+
+// Register sets fn as the compensation for msgID, replacing any earlier one
+func (k *Compensator) Register(msgID string, fn Compensation) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.actions[msgID] = fn
+}
+
+// Forget drops the compensation for msgID
+func (k *Compensator) Forget(msgID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.actions, msgID)
+}
+
+// Pending returns how many compensations await an outcome
+func (k *Compensator) Pending() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.actions)
+}
+
+// Wait blocks until every started compensation has returned
+func (k *Compensator) Wait() {
+	k.running.Wait()
+}
+
+// Close stops watching deliveries and waits for running compensations
+func (k *Compensator) Close() {
+	k.stop()
+	k.Wait()
+}
+
+// observe runs off the delivery path, as observers must not block
+func (k *Compensator) observe(e DeliveryEvent) {
+	if e.Stage != StageSent && e.Stage != StageFailed {
+		return
+	}
+	k.mu.Lock()
+	fn, ok := k.actions[e.MessageID]
+	delete(k.actions, e.MessageID)
+	k.mu.Unlock()
+
+	if ok && e.Stage == StageFailed {
+		k.running.Add(1)
+		go func() {
+			defer k.running.Done()
+			fn(e)
+		}()
+	}
+}
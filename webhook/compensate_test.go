@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestCompensator(t *testing.T) {
+	server, _ := countingServer(t, http.StatusGone)
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(0))
+	saga := NewCompensator(client)
+	defer saga.Close()
+
+	var mu sync.Mutex
+	var failed []DeliveryEvent
+	compensate := func(e DeliveryEvent) {
+		mu.Lock()
+		failed = append(failed, e)
+		mu.Unlock()
+	}
+
+	resp := saga.Send(context.Background(), Payload{Event: "order.created", Data: map[string]any{}}, compensate)
+	saga.Wait()
+	if resp.Success {
+		t.Fatalf("Expected delivery to fail")
+	}
+	if len(failed) != 1 || failed[0].MessageID != resp.MessageID || failed[0].StatusCode != http.StatusGone {
+		t.Fatalf("Expected one compensation for %s, got %+v", resp.MessageID, failed)
+	}
+	if saga.Pending() != 0 {
+		t.Errorf("Expected no pending compensations, got %d", saga.Pending())
+	}
+
+	// Rejected before delivery: nothing is registered
+	saga.Send(context.Background(), Payload{Event: "hookshot.internal", Data: map[string]any{}}, compensate)
+	if saga.Pending() != 0 {
+		t.Errorf("Expected invalid send not to register, got %d", saga.Pending())
+	}
+}
+
+func TestCompensator_SentAndParked(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret)
+	saga := NewCompensator(client)
+	defer saga.Close()
+
+	ran := false
+	compensate := func(DeliveryEvent) { ran = true }
+
+	if resp := saga.Send(context.Background(), Payload{Event: "order.created", Data: 1}, compensate); !resp.Success {
+		t.Fatalf("Expected success, got %v", resp.Error)
+	}
+
+	client.Pause()
+	if resp := saga.Send(context.Background(), Payload{Event: "order.created", Data: 2}, compensate); !resp.Parked {
+		t.Fatalf("Expected send to be parked")
+	}
+	if saga.Pending() != 1 {
+		t.Errorf("Expected parked delivery to keep its compensation, got %d", saga.Pending())
+	}
+	for range client.Resume() {
+	}
+	saga.Wait()
+	if ran || saga.Pending() != 0 {
+		t.Errorf("Expected delivered sends to drop compensations, ran=%v pending=%d", ran, saga.Pending())
+	}
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// ErrInvalidConfig wraps every problem reported by Config.Validate
+var ErrInvalidConfig = errors.New("webhook: invalid config")
+
+// NewConfig returns the configuration NewClient builds from the same arguments,
+// so it can be checked with Validate before a client is created
+func NewConfig(targetURL, secret string, opts ...Option) Config {
+	cfg := Config{
+		TargetURL:   targetURL,
+		Secret:      secret,
+		MaxRetries:  3,
+		Timeout:     10 * time.Second,
+		MaxInterval: 30 * time.Second,
+		Headers:     signing.SvixHeaders,
+		ResumeRate:  10,
+		MaxHeld:     10000,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.MaxBackoffHint == 0 {
+		cfg.MaxBackoffHint = cfg.MaxInterval
+	}
+	return cfg
+}
+
+// Validate checks the whole configuration and reports every problem at once,
+// each naming the field at fault, where NewClient stops at the first. The
+// result unwraps to one error per problem, each wrapping ErrInvalidConfig.
+func (cfg Config) Validate() error {
+	var errs []error
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidConfig, field, strings.TrimPrefix(err.Error(), "webhook: ")))
+		}
+	}
+
+	if cfg.TargetURL == "" {
+		add("TargetURL", errors.New("is required"))
+	} else {
+		add("TargetURL", checkURL(cfg.TargetURL))
+	}
+	for i, u := range cfg.Targets {
+		add(fmt.Sprintf("Targets[%d]", i), checkURL(u))
+	}
+	if cfg.ShadowURL != "" {
+		add("ShadowURL", checkURL(cfg.ShadowURL))
+	}
+	if cfg.CanaryURL != "" {
+		add("CanaryURL", checkURL(cfg.CanaryURL))
+	}
+	add("CanaryPercent", validPercent(cfg.CanaryPercent))
+	if len(cfg.Targets) > 0 && len(cfg.SignedHeaders) > 0 {
+		add("SignedHeaders", errors.New("signed headers cover a single target URL; drop Targets or SignedHeaders"))
+	}
+
+	if cfg.Headers == (signing.HeaderNames{}) {
+		cfg.Headers = signing.SvixHeaders
+	}
+	if err := cfg.Headers.Validate(); err != nil {
+		add("Headers", err)
+	}
+	add("LegacyHeaders", validLegacyHeaders(cfg))
+
+	if cfg.Secret == "" {
+		add("Secret", errors.New("is required; generate one with signing.EncodeSecret over 32 random bytes"))
+	} else if err := normalizeSecrets(&cfg); err != nil {
+		add("Secret", fmt.Errorf("%v (SecretEncoding is %q)", err, cfg.SecretEncoding))
+	} else if signers, err := newSigners(cfg); err != nil {
+		add("Secret", err)
+	} else {
+		if _, err := enabledVersions(signers, cfg.SignatureVersions, len(cfg.SignedHeaders) > 0); err != nil {
+			add("SignatureVersions", err)
+		}
+		if _, err := newTokenKey(cfg, signers[0]); err != nil {
+			add("QueryTokenTTL", err)
+		}
+		if len(cfg.SignedHeaders) > 0 && signers[0].Version() != signing.SchemeV1 {
+			add("SignedHeaders", errors.New("signed headers require an HMAC (whsec_) secret"))
+		}
+	}
+
+	for _, d := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"Timeout", cfg.Timeout}, {"MaxInterval", cfg.MaxInterval}, {"MaxBackoffHint", cfg.MaxBackoffHint},
+		{"TargetCooldown", cfg.TargetCooldown}, {"DedupWindow", cfg.DedupWindow}, {"HedgeMinDelay", cfg.HedgeMinDelay},
+	} {
+		if d.value < 0 {
+			add(d.field, fmt.Errorf("must not be negative, got %s", d.value))
+		}
+	}
+	if cfg.HedgePercentile < 0 || cfg.HedgePercentile >= 1 {
+		add("HedgePercentile", fmt.Errorf("must be at least 0 and below 1, e.g. 0.95, got %v", cfg.HedgePercentile))
+	}
+	for _, code := range cfg.SuccessStatuses {
+		if code < 200 || code > 599 {
+			add("SuccessStatuses", fmt.Errorf("must be final HTTP statuses (200-599), got %d", code))
+		}
+	}
+	if cfg.ResumeRate < 0 {
+		add("ResumeRate", fmt.Errorf("must not be negative, got %d", cfg.ResumeRate))
+	}
+	if cfg.MaxHeld < 0 {
+		add("MaxHeld", fmt.Errorf("must not be negative, got %d", cfg.MaxHeld))
+	}
+	for i, stage := range cfg.Enrichers {
+		if stage.Name == "" || stage.Enricher == nil {
+			add(fmt.Sprintf("Enrichers[%d]", i), errors.New("needs a name and an enricher"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkURL requires an absolute http or https URL
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must be an absolute http:// or https:// URL", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	if err := NewConfig("https://example.com/hook", testSecret).Validate(); err != nil {
+		t.Errorf("Expected the default config to be valid, got %v", err)
+	}
+
+	cfg := NewConfig("example.com/hook", "whsec_not base64!",
+		WithTrafficSplit("ftp://canary.example.com", 120),
+		WithTimeout(-time.Second),
+		WithSignatureVersions(signing.SchemeV1a),
+	)
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Expected ErrInvalidConfig, got %v", err)
+	}
+	problems := err.(interface{ Unwrap() []error }).Unwrap()
+	var fields []string
+	for _, p := range problems {
+		msg := strings.TrimPrefix(p.Error(), ErrInvalidConfig.Error()+": ")
+		fields = append(fields, msg[:strings.Index(msg, ":")])
+	}
+	want := []string{"TargetURL", "CanaryURL", "CanaryPercent", "Secret", "Timeout"}
+	if strings.Join(fields, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected problems with %v, got %v", want, problems)
+	}
+	if !strings.Contains(err.Error(), "absolute http:// or https:// URL") {
+		t.Errorf("Expected an actionable URL message, got %v", err)
+	}
+}
+
+func TestConfig_Validate_DependentChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		field string
+	}{
+		{"missing secret", NewConfig("https://example.com", ""), "Secret"},
+		{"unknown version", NewConfig("https://example.com", testSecret, WithSignatureVersions(signing.SchemeV1a)), "SignatureVersions"},
+		{"signed headers with targets", NewConfig("https://example.com", testSecret, WithSignedHeaders("Content-Type"), WithTargets(Failover, "https://backup.example.com")), "SignedHeaders"},
+		{"bad encoding", NewConfig("https://example.com", "whsec_abc", WithSecretEncoding(signing.EncodingHex)), "Secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), "invalid config: "+tt.field+":") {
+				t.Errorf("Expected a %s problem, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNewConfig_MatchesNewClient(t *testing.T) {
+	client, _ := NewClient("https://example.com", testSecret, WithMaxRetries(5))
+	cfg := NewConfig("https://example.com", testSecret, WithMaxRetries(5))
+	if cfg.MaxRetries != client.config.MaxRetries || cfg.MaxBackoffHint != client.config.MaxBackoffHint || cfg.Headers != client.config.Headers {
+		t.Errorf("Expected NewConfig to build the client's config, got %+v", cfg)
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import "context"
+
+// Correlation links a payload to the event chain it belongs to
+type Correlation struct {
+	CorrelationID string // Shared by every event in the chain
+	CausationID   string // Message ID of the event that directly caused this one
+}
+
+type correlationContextKey struct{}
+
+// ContextWithCorrelation returns a context whose sends carry corr unless the
+// payload sets its own IDs. Receivers put the next link of the chain in
+// handler contexts, so events sent while handling one are linked to it.
+func ContextWithCorrelation(ctx context.Context, corr Correlation) context.Context {
+	return context.WithValue(ctx, correlationContextKey{}, corr)
+}
+
+// CorrelationFromContext returns the correlation set by ContextWithCorrelation
+func CorrelationFromContext(ctx context.Context) Correlation {
+	corr, _ := ctx.Value(correlationContextKey{}).(Correlation)
+	return corr
+}
+
+// correlate fills the payload's empty correlation fields from ctx
+func correlate(ctx context.Context, payload *Payload) {
+	corr := CorrelationFromContext(ctx)
+	if payload.CorrelationID == "" {
+		payload.CorrelationID = corr.CorrelationID
+	}
+	if payload.CausationID == "" {
+		payload.CausationID = corr.CausationID
+	}
+}
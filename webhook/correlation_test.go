@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Correlation(t *testing.T) {
+	var got Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, testSecret)
+
+	ctx := ContextWithCorrelation(context.Background(), Correlation{CorrelationID: "corr_1", CausationID: "msg_parent"})
+	client.Send(ctx, "order.created", map[string]any{})
+	if got.CorrelationID != "corr_1" || got.CausationID != "msg_parent" {
+		t.Errorf("Expected correlation from context, got %q %q", got.CorrelationID, got.CausationID)
+	}
+
+	event, _ := NewEvent("order.created").WithData(map[string]any{}).WithCorrelationID("corr_2").Build()
+	client.SendEvent(ctx, event)
+	if got.CorrelationID != "corr_2" || got.CausationID != "msg_parent" {
+		t.Errorf("Expected explicit correlation ID to win, got %q %q", got.CorrelationID, got.CausationID)
+	}
+
+	got = Payload{}
+	client.Send(context.Background(), "order.created", map[string]any{})
+	if got.CorrelationID != "" || got.CausationID != "" {
+		t.Errorf("Expected no correlation without context, got %q %q", got.CorrelationID, got.CausationID)
+	}
+}
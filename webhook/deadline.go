@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sabry-awad97/Hookshot/retry"
+)
+
+// Deferred is the unfinished remainder of a delivery whose retry schedule did
+// not fit the caller's context deadline
+type Deferred struct {
+	MessageID string
+	Attempts  int    // Attempts already made
+	Remaining uint64 // Attempts left in the retry schedule
+
+	client *Client
+	d      delivery
+}
+
+// Send resumes the delivery with the remaining attempts, re-signed with a
+// fresh timestamp; attempt numbering continues where it stopped
+func (df *Deferred) Send(ctx context.Context) Response {
+	d := df.d
+	d.attempt, d.remaining = df.Attempts, df.Remaining
+	df.client.seal(&d)
+	return df.client.sendWithRetry(ctx, d)
+}
+
+// WithDeadlineOverflow hands deliveries whose retries were cut short by the
+// context deadline to fn, e.g. to enqueue them for a background worker that
+// calls Deferred.Send. Without it the remaining attempts are dropped.
+func WithDeadlineOverflow(fn func(*Deferred)) Option {
+	return func(c *Config) {
+		c.DeadlineOverflow = fn
+	}
+}
+
+// budgetForDeadline limits the policy to the attempts that fit ctx's deadline,
+// assuming each may take the full HTTP timeout. It reports whether it did.
+func (c *Client) budgetForDeadline(ctx context.Context, policy *retry.Policy, msgID string) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+	remaining := deadline.Sub(c.det.Now())
+	budget := policy.Budget(remaining, c.config.Timeout)
+	if budget >= policy.MaxAttempts {
+		return false
+	}
+	c.logger.Info("webhook: retry budget limited by context deadline",
+		"msgId", msgID, "attempts", budget, "scheduled", policy.MaxAttempts, "remaining", remaining)
+	policy.MaxAttempts = budget
+	return true
+}
+
+// overflow passes the rest of a delivery to the DeadlineOverflow handler when
+// the deadline, not the retry schedule, ended it
+func (c *Client) overflow(ctx context.Context, d delivery, cut bool, made int, left uint64) bool {
+	if c.config.DeadlineOverflow == nil || !(cut || errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+		return false
+	}
+	c.config.DeadlineOverflow(&Deferred{
+		MessageID: d.msgID,
+		Attempts:  d.attempt + made,
+		Remaining: left,
+		client:    c,
+		d:         d,
+	})
+	return true
+}
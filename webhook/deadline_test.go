@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_DeadlineBudget(t *testing.T) {
+	var healthy atomic.Bool
+	var attemptHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptHeaders = append(attemptHeaders, r.Header.Get(AttemptHeader))
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var deferred []*Deferred
+	det, _ := testDeterminism()
+	now := time.Now()
+	det.Now = func() time.Time { return now }
+	client, _ := NewClient(server.URL, testSecret,
+		WithDeterminism(det), WithMaxRetries(5), WithTimeout(time.Second),
+		WithDeadlineOverflow(func(d *Deferred) { deferred = append(deferred, d) }),
+	)
+
+	// 1s attempt + 1s delay + 1s attempt fits; the third attempt would not
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(3200*time.Millisecond))
+	defer cancel()
+	resp := client.Send(ctx, "order.created", nil)
+
+	if resp.Success || len(resp.Attempts) != 2 || !resp.Deferred {
+		t.Fatalf("Expected 2 attempts then deferral, got %d attempts, deferred=%v", len(resp.Attempts), resp.Deferred)
+	}
+	if len(deferred) != 1 || deferred[0].Attempts != 2 || deferred[0].Remaining != 3 {
+		t.Fatalf("Expected one deferral with 3 attempts left, got %+v", deferred)
+	}
+
+	healthy.Store(true)
+	if resumed := deferred[0].Send(context.Background()); !resumed.Success || resumed.MessageID != deferred[0].MessageID {
+		t.Errorf("Expected resumed delivery of %s to succeed, got %+v", deferred[0].MessageID, resumed)
+	}
+	if !slices.Equal(attemptHeaders, []string{"1", "2", "3"}) {
+		t.Errorf("Expected attempt numbering to continue, got %v", attemptHeaders)
+	}
+}
+
+func TestClient_DeadlineBudget_NoOverflow(t *testing.T) {
+	server, hits := countingServer(t, http.StatusServiceUnavailable)
+
+	det, _ := testDeterminism()
+	now := time.Now()
+	det.Now = func() time.Time { return now }
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(5), WithTimeout(time.Second))
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(500*time.Millisecond))
+	defer cancel()
+	resp := client.Send(ctx, "order.created", nil)
+
+	// At least one attempt is always made, and nothing is deferred without a handler
+	if *hits != 1 || resp.Deferred {
+		t.Errorf("Expected a single attempt and no deferral, got %d attempts, deferred=%v", *hits, resp.Deferred)
+	}
+}
+
+func TestClient_DeadlineBudget_UsesInjectedClock(t *testing.T) {
+	server, hits := countingServer(t, http.StatusServiceUnavailable)
+
+	// The real deadline leaves room for every attempt, but the injected clock
+	// is already 1.5s into it, so only one 1s attempt fits
+	now := time.Now()
+	det, _ := testDeterminism()
+	det.Now = func() time.Time { return now.Add(1500 * time.Millisecond) }
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(5), WithTimeout(time.Second))
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(3200*time.Millisecond))
+	defer cancel()
+	client.Send(ctx, "order.created", nil)
+
+	if *hits != 1 {
+		t.Errorf("Expected the budget to follow the injected clock and allow 1 attempt, got %d", *hits)
+	}
+}
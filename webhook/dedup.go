@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// WithDedupWindow suppresses a send whose event name and data match one sent
+// within window, so a producer that emits the same business event twice does
+// not notify the receiver twice. The payload timestamp is not part of the
+// match. A failed send is forgotten, so re-emitting it goes through.
+func WithDedupWindow(window time.Duration) Option {
+	return func(c *Config) {
+		c.DedupWindow = window
+	}
+}
+
+// dedupKey hashes what makes two payloads the same business event
+func dedupKey(payload Payload) ([sha256.Size]byte, error) {
+	data, err := json.Marshal(payload.Data)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.New()
+	h.Write([]byte(payload.Event))
+	h.Write([]byte{0})
+	h.Write(data)
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key, nil
+}
+
+type dedupEntry struct {
+	key   [sha256.Size]byte
+	msgID string
+	at    time.Time
+}
+
+// dedupWindow remembers recently sent payload hashes in arrival order
+type dedupWindow struct {
+	window time.Duration
+	mu     sync.Mutex
+	seen   map[[sha256.Size]byte]dedupEntry
+	order  []dedupEntry // Oldest first, for expiry
+}
+
+func newDedupWindow(window time.Duration) *dedupWindow {
+	if window <= 0 {
+		return nil
+	}
+	return &dedupWindow{window: window, seen: make(map[[sha256.Size]byte]dedupEntry)}
+}
+
+// claim records key for msgID, or returns the message ID already holding it
+func (w *dedupWindow) claim(key [sha256.Size]byte, msgID string, now time.Time) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.order) > 0 && now.Sub(w.order[0].at) >= w.window {
+		if e := w.order[0]; w.seen[e.key] == e {
+			delete(w.seen, e.key)
+		}
+		w.order = w.order[1:]
+	}
+	if e, ok := w.seen[key]; ok {
+		return e.msgID, true
+	}
+	e := dedupEntry{key: key, msgID: msgID, at: now}
+	w.seen[key] = e
+	w.order = append(w.order, e)
+	return "", false
+}
+
+// release forgets key if msgID still holds it
+func (w *dedupWindow) release(key [sha256.Size]byte, msgID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if e, ok := w.seen[key]; ok && e.msgID == msgID {
+		delete(w.seen, key)
+	}
+}
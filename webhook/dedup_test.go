@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_DedupWindow_SuppressesRepeat(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	client, err := NewClient(server.URL, testSecret, WithDedupWindow(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	first := client.Send(context.Background(), "order.paid", map[string]any{"id": 7, "total": 12})
+	if !first.Success {
+		t.Fatalf("Expected first send to succeed, got %v", first.Error)
+	}
+	second := client.Send(context.Background(), "order.paid", map[string]any{"total": 12, "id": 7})
+	if !second.Duplicate {
+		t.Fatalf("Expected second send to be a duplicate")
+	}
+	if second.MessageID != first.MessageID {
+		t.Errorf("Expected original message ID %s, got %s", first.MessageID, second.MessageID)
+	}
+	if *hits != 1 {
+		t.Errorf("Expected 1 delivery, got %d", *hits)
+	}
+
+	other := client.Send(context.Background(), "order.paid", map[string]any{"id": 8, "total": 12})
+	if other.Duplicate || *hits != 2 {
+		t.Errorf("Expected different data to be delivered, got duplicate=%v hits=%d", other.Duplicate, *hits)
+	}
+	renamed := client.Send(context.Background(), "order.refunded", map[string]any{"id": 7, "total": 12})
+	if renamed.Duplicate || *hits != 3 {
+		t.Errorf("Expected different event to be delivered, got duplicate=%v hits=%d", renamed.Duplicate, *hits)
+	}
+}
+
+func TestClient_DedupWindow_Expires(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	det, _ := testDeterminism()
+	now := det.Now()
+	det.Now = func() time.Time { return now }
+	client, err := NewClient(server.URL, testSecret, WithDeterminism(det), WithDedupWindow(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	client.Send(context.Background(), "user.created", map[string]string{"id": "u1"})
+	now = now.Add(30 * time.Second)
+	if resp := client.Send(context.Background(), "user.created", map[string]string{"id": "u1"}); !resp.Duplicate {
+		t.Errorf("Expected duplicate inside the window")
+	}
+	now = now.Add(31 * time.Second)
+	if resp := client.Send(context.Background(), "user.created", map[string]string{"id": "u1"}); resp.Duplicate {
+		t.Errorf("Expected delivery after the window expired")
+	}
+	if *hits != 2 {
+		t.Errorf("Expected 2 deliveries, got %d", *hits)
+	}
+}
+
+func TestClient_DedupWindow_FailedSendNotRemembered(t *testing.T) {
+	server, hits := countingServer(t, http.StatusBadRequest)
+	client, err := NewClient(server.URL, testSecret, WithDedupWindow(time.Minute), WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if resp := client.Send(context.Background(), "user.created", map[string]string{"id": "u1"}); resp.Success {
+		t.Fatalf("Expected first send to fail")
+	}
+	if resp := client.Send(context.Background(), "user.created", map[string]string{"id": "u1"}); resp.Duplicate {
+		t.Errorf("Expected re-emit of a failed send to be delivered")
+	}
+	if *hits != 2 {
+		t.Errorf("Expected 2 attempts, got %d", *hits)
+	}
+}
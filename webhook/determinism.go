@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/retry"
+
+	"github.com/google/uuid"
+)
+
+// Scheduler waits out the delay between retry attempts
+type Scheduler = retry.Scheduler
+
+// Determinism bundles every non-deterministic input of the send path.
+// Nil fields fall back to the real clock, UUID message IDs, a random
+// jitter source and real timers.
+type Determinism struct {
+	Now       func() time.Time // Clock for payload and signing timestamps
+	NewID     func() string    // Message ID generator (must include the msg_ prefix)
+	Jitter    func() float64   // Jitter source returning values in [0, 1)
+	Scheduler Scheduler        // Retry delay scheduler
+}
+
+// WithDeterminism replaces the clock, ID generator, jitter source and retry
+// scheduler, so golden tests of the send path produce byte-identical requests
+func WithDeterminism(d Determinism) Option {
+	return func(c *Config) {
+		c.Determinism = d
+	}
+}
+
+// withDefaults fills unset fields with their production implementations
+func (d Determinism) withDefaults() Determinism {
+	if d.Now == nil {
+		d.Now = time.Now
+	}
+	if d.NewID == nil {
+		d.NewID = func() string {
+			return fmt.Sprintf("msg_%s", uuid.New().String())
+		}
+	}
+	if d.Jitter == nil {
+		d.Jitter = rand.Float64
+	}
+	if d.Scheduler == nil {
+		d.Scheduler = retry.RealScheduler{}
+	}
+	return d
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// fakeScheduler records requested delays and fires immediately
+type fakeScheduler struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (s *fakeScheduler) After(d time.Duration) <-chan time.Time {
+	s.mu.Lock()
+	s.delays = append(s.delays, d)
+	s.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func testDeterminism() (Determinism, *fakeScheduler) {
+	fixed := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	n := 0
+	sched := &fakeScheduler{}
+	return Determinism{
+		Now: func() time.Time { return fixed },
+		NewID: func() string {
+			n++
+			return fmt.Sprintf("msg_%04d", n)
+		},
+		Jitter:    func() float64 { return 0.5 },
+		Scheduler: sched,
+	}, sched
+}
+
+// recordSend runs a full send against a flaky server and returns a dump of every request
+func recordSend(t *testing.T) ([]byte, []time.Duration) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var dump bytes.Buffer
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		fmt.Fprintf(&dump, "%s %s\n", r.Method, r.URL.Path)
+		for _, h := range []string{"Content-Type", "Svix-Id", "Svix-Timestamp", "Svix-Signature"} {
+			fmt.Fprintf(&dump, "%s: %s\n", h, r.Header.Get(h))
+		}
+		fmt.Fprintf(&dump, "\n%s\n\n", body)
+		if attempts < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	det, sched := testDeterminism()
+	client, err := NewClient(server.URL+"/webhook", testSecret, WithDeterminism(det))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{
+		"order_id": "12345",
+		"amount":   99.99,
+	})
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if resp.MessageID != "msg_0001" {
+		t.Errorf("Expected message ID 'msg_0001', got '%s'", resp.MessageID)
+	}
+
+	return dump.Bytes(), sched.delays
+}
+
+func TestDeterminism_Golden(t *testing.T) {
+	got, delays := recordSend(t)
+
+	golden := filepath.Join("testdata", "send_retry.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Request dump does not match golden file\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+
+	wantDelays := []time.Duration{1 * time.Second, 1500 * time.Millisecond}
+	if len(delays) != len(wantDelays) {
+		t.Fatalf("Expected %d scheduled delays, got %v", len(wantDelays), delays)
+	}
+	for i := range wantDelays {
+		if delays[i] != wantDelays[i] {
+			t.Errorf("Delay %d: expected %v, got %v", i, wantDelays[i], delays[i])
+		}
+	}
+}
+
+func TestDeterminism_Repeatable(t *testing.T) {
+	first, _ := recordSend(t)
+	second, _ := recordSend(t)
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("Expected byte-identical requests across runs\n--- first ---\n%s\n--- second ---\n%s", first, second)
+	}
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// diagnosticsEvent names the deliveries sent by DiagnoseReceiver
+const diagnosticsEvent = ReservedPrefix + "diagnostics"
+
+// diagnosticsInstructions travel in every diagnostics delivery for anyone
+// inspecting receiver logs
+const diagnosticsInstructions = "Hookshot receiver diagnostics: verify this delivery exactly like any other " +
+	"and respond 2xx only if the signature verifies. Do not process the event."
+
+// DiagnosticProbe is the outcome of one diagnostics delivery
+type DiagnosticProbe struct {
+	Name     string `json:"name"`
+	Expected bool   `json:"expected"` // Whether a correct receiver accepts it
+	Status   int    `json:"status"`
+	Accepted bool   `json:"accepted"` // 2xx response
+	Error    string `json:"error,omitempty"`
+}
+
+// ReceiverDiagnosis reports the integration mistakes DiagnoseReceiver detected
+type ReceiverDiagnosis struct {
+	TargetURL string            `json:"target_url"`
+	Probes    []DiagnosticProbe `json:"probes"`
+	Findings  []string          `json:"findings"`
+}
+
+// OK reports whether the receiver verified every probe correctly
+func (d *ReceiverDiagnosis) OK() bool {
+	return len(d.Findings) == 0
+}
+
+// String renders the diagnosis for humans
+func (d *ReceiverDiagnosis) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "receiver diagnostics for %s\n", d.TargetURL)
+	for _, p := range d.Probes {
+		want, got := "reject", "rejected"
+		if p.Expected {
+			want = "accept"
+		}
+		if p.Accepted {
+			got = "accepted"
+		}
+		status := "ok  "
+		if p.Accepted != p.Expected || p.Error != "" {
+			status = "FAIL"
+		}
+		detail := fmt.Sprintf("%s (status %d)", got, p.Status)
+		if p.Error != "" {
+			detail = p.Error
+		}
+		fmt.Fprintf(&b, "%s %-14s want %s, %s\n", status, p.Name, want, detail)
+	}
+	if d.OK() {
+		b.WriteString("no integration mistakes detected\n")
+	}
+	for _, f := range d.Findings {
+		b.WriteString("- " + f + "\n")
+	}
+	return b.String()
+}
+
+// diagnosticCase is one probe: the body sent and the bytes and key it is signed over
+type diagnosticCase struct {
+	name     string
+	expected bool
+	sign     func(key []byte, secret string, body []byte) (signKey, signBody []byte)
+	compact  bool // Send compact JSON instead of the pretty-printed body
+}
+
+var diagnosticCases = []diagnosticCase{
+	{name: "valid", expected: true, sign: func(key []byte, _ string, body []byte) ([]byte, []byte) { return key, body }},
+	{name: "compact", expected: true, compact: true, sign: func(key []byte, _ string, body []byte) ([]byte, []byte) { return key, body }},
+	{name: "raw-secret", sign: func(_ []byte, secret string, body []byte) ([]byte, []byte) { return []byte(secret), body }},
+	{name: "undecoded-key", sign: func(_ []byte, secret string, body []byte) ([]byte, []byte) {
+		return []byte(strings.TrimPrefix(secret, signing.SecretPrefix)), body
+	}},
+	{name: "empty-body", sign: func(key []byte, _ string, _ []byte) ([]byte, []byte) { return key, nil }},
+	{name: "bad-signature", sign: func(_ []byte, _ string, body []byte) ([]byte, []byte) { return []byte("hookshot-diagnostics"), body }},
+}
+
+// DiagnoseReceiver sends a series of hookshot.diagnostics deliveries, each
+// signed correctly or in one commonly mistaken way, and infers from which ones
+// the receiver accepts whether it re-serializes the body before verifying, uses
+// the wrong secret encoding, verifies without access to the raw body, or does
+// not verify at all. Probes are sent once each, without retries.
+func (c *Client) DiagnoseReceiver(ctx context.Context) (*ReceiverDiagnosis, error) {
+	if c.signers[0].Version() != signing.SchemeV1 {
+		return nil, fmt.Errorf("webhook: receiver diagnostics require an HMAC (whsec_) secret")
+	}
+	if c.tokenKey != nil {
+		return nil, fmt.Errorf("webhook: receiver diagnostics require signature headers, not query tokens")
+	}
+	key, err := signing.DecodeSecret(c.config.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to create signer: %w", err)
+	}
+
+	diag := &ReceiverDiagnosis{TargetURL: c.config.TargetURL}
+	results := make(map[string]DiagnosticProbe)
+	for _, dc := range diagnosticCases {
+		p := c.sendDiagnostic(ctx, key, dc)
+		diag.Probes = append(diag.Probes, p)
+		results[p.Name] = p
+	}
+	diag.Findings = diagnosticFindings(results)
+	return diag, nil
+}
+
+func (c *Client) sendDiagnostic(ctx context.Context, key []byte, dc diagnosticCase) DiagnosticProbe {
+	p := DiagnosticProbe{Name: dc.name, Expected: dc.expected}
+
+	body := diagnosticBody(c, dc)
+	msgID := c.det.NewID()
+	ts := c.det.Now()
+	signKey, signBody := dc.sign(key, c.config.Secret, body)
+
+	req, err := c.newRequest(ctx, delivery{
+		body:      body,
+		msgID:     msgID,
+		timestamp: ts,
+		signature: signing.SignV1(signKey, msgID, ts, signBody),
+	}, 1)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		p.Error = fmt.Sprintf("%v: %v", ErrNetwork, err)
+		return p
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	p.Status = resp.StatusCode
+	p.Accepted = resp.StatusCode >= 200 && resp.StatusCode < 300
+	return p
+}
+
+// diagnosticBody renders the probe payload. Except for the compact case it is
+// indented, keys are out of alphabetical order and it carries non-ASCII text,
+// so a receiver that re-encodes the JSON cannot reproduce the signed bytes.
+func diagnosticBody(c *Client, dc diagnosticCase) []byte {
+	data := map[string]any{
+		"probe":        dc.name,
+		"expect":       map[bool]string{true: "accept", false: "reject"}[dc.expected],
+		"instructions": diagnosticsInstructions,
+	}
+	if dc.compact {
+		// Sorted keys and ASCII only: what most re-encoders emit for this payload
+		body, _ := json.Marshal(map[string]any{"data": data, "event": diagnosticsEvent, "timestamp": c.det.Now()})
+		return body
+	}
+
+	quote := func(v any) string {
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Appendf(nil, `{
+  "timestamp": %s,
+  "event": %s,
+  "data": {
+    "text": "café",
+    "probe": %s,
+    "expect": %s,
+    "instructions": %s
+  }
+}`, quote(c.det.Now()), quote(diagnosticsEvent), quote(data["probe"]), quote(data["expect"]), quote(diagnosticsInstructions))
+}
+
+func diagnosticFindings(r map[string]DiagnosticProbe) []string {
+	for _, p := range r {
+		if p.Error != "" {
+			return []string{"receiver unreachable: " + p.Error}
+		}
+	}
+
+	if r["bad-signature"].Accepted {
+		return []string{"accepts deliveries with invalid signatures: signatures are not verified"}
+	}
+
+	var findings []string
+	if r["empty-body"].Accepted {
+		findings = append(findings, "verifies an empty body: the raw request body was consumed (e.g. by a JSON body parser) "+
+			"before verification; read and verify the raw bytes first")
+	}
+	if r["raw-secret"].Accepted || r["undecoded-key"].Accepted {
+		findings = append(findings, "uses the secret string as the HMAC key: strip the whsec_ prefix and base64-decode the rest")
+	}
+	if !r["valid"].Accepted && r["compact"].Accepted {
+		findings = append(findings, "re-serializes the body before verification: verify the raw bytes exactly as received")
+	}
+	if len(findings) == 0 && (!r["valid"].Accepted || !r["compact"].Accepted) {
+		findings = append(findings, fmt.Sprintf("rejects correctly signed deliveries (status %d): check the secret, "+
+			"signature header names and timestamp tolerance", r["valid"].Status))
+	}
+	return findings
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+func TestClient_DiagnoseReceiver(t *testing.T) {
+	key, _ := signing.DecodeSecret(testSecret)
+
+	// Each receiver verifies with a different mistake: it derives the key and
+	// the verified bytes from the raw body it read
+	tests := []struct {
+		name    string
+		verify  func(body []byte) (verifyKey, verifyBody []byte)
+		skip    bool // Accept without verifying
+		finding string
+	}{
+		{name: "correct", verify: func(b []byte) ([]byte, []byte) { return key, b }},
+		{name: "re-serialized", verify: func(b []byte) ([]byte, []byte) {
+			var v any
+			json.Unmarshal(b, &v)
+			out, _ := json.Marshal(v)
+			return key, out
+		}, finding: "re-serializes the body"},
+		{name: "raw secret", verify: func(b []byte) ([]byte, []byte) { return []byte(testSecret), b }, finding: "secret string as the HMAC key"},
+		{name: "consumed body", verify: func([]byte) ([]byte, []byte) { return key, nil }, finding: "verifies an empty body"},
+		{name: "no verification", skip: true, finding: "signatures are not verified"},
+		{name: "wrong secret", verify: func(b []byte) ([]byte, []byte) { return []byte("other"), b }, finding: "rejects correctly signed deliveries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var p Payload
+				json.Unmarshal(body, &p)
+				events = append(events, p.Event)
+				if tt.skip {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+
+				ts, _ := strconv.ParseInt(r.Header.Get("svix-timestamp"), 10, 64)
+				k, b := tt.verify(body)
+				if err := signing.VerifyV1(k, r.Header.Get("svix-id"), time.Unix(ts, 0), b, r.Header.Get("svix-signature")); err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, _ := NewClient(server.URL, testSecret)
+			diag, err := client.DiagnoseReceiver(context.Background())
+			if err != nil {
+				t.Fatalf("DiagnoseReceiver() error = %v", err)
+			}
+
+			if len(events) != len(diagnosticCases) || events[0] != diagnosticsEvent {
+				t.Errorf("Expected %d %s deliveries, got %v", len(diagnosticCases), diagnosticsEvent, events)
+			}
+			if tt.finding == "" {
+				if !diag.OK() {
+					t.Errorf("Expected no findings, got %v", diag.Findings)
+				}
+				return
+			}
+			if len(diag.Findings) != 1 || !strings.Contains(diag.Findings[0], tt.finding) {
+				t.Errorf("Expected finding %q, got %v", tt.finding, diag.Findings)
+			}
+			if !strings.Contains(diag.String(), "FAIL") {
+				t.Errorf("Expected report to flag failed probes, got:\n%s", diag)
+			}
+		})
+	}
+}
+
+func TestClient_DiagnoseReceiver_RequiresHMAC(t *testing.T) {
+	secretKey, _, _ := signing.GenerateKeyPair()
+	client, _ := NewClient("http://localhost", secretKey)
+	if _, err := client.DiagnoseReceiver(context.Background()); err == nil {
+		t.Error("Expected error for an Ed25519 secret")
+	}
+}
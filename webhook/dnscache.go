@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LookupFunc resolves host to addresses and reports how long they may be cached.
+// A zero TTL means the resolver does not know and DNSCacheConfig.DefaultTTL applies.
+type LookupFunc func(ctx context.Context, host string) (addrs []string, ttl time.Duration, err error)
+
+// DNSCacheConfig configures a DNSCache
+type DNSCacheConfig struct {
+	Lookup      LookupFunc    // Resolver (default: net.DefaultResolver, which exposes no TTLs)
+	DefaultTTL  time.Duration // TTL when Lookup reports none, i.e. every entry with the default Lookup (default: 30s)
+	NegativeTTL time.Duration // How long failed lookups are cached (default: 5s)
+	MaxStale    time.Duration // How long expired entries may be served when refresh fails (default: 5m)
+}
+
+// DNSCacheStats are cumulative cache counters
+type DNSCacheStats struct {
+	Hits         uint64 // Served from a fresh entry
+	Misses       uint64 // Required a lookup
+	StaleServed  uint64 // Served an expired entry because refresh failed
+	NegativeHits uint64 // Served a cached failure
+}
+
+// HitRate is the fraction of lookups answered from cache
+func (s DNSCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses + s.NegativeHits
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.NegativeHits) / float64(total)
+}
+
+type dnsEntry struct {
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+// DNSCache caches delivery host lookups with negative caching and
+// stale-on-error fallback, so flaky DNS does not surface as network errors.
+// Record TTLs are honored only when DNSCacheConfig.Lookup reports them; the
+// default resolver does not, so its entries all live for DefaultTTL.
+type DNSCache struct {
+	config DNSCacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+
+	hits, misses, stale, negative atomic.Uint64
+}
+
+// NewDNSCache creates a resolver cache
+func NewDNSCache(cfg DNSCacheConfig) *DNSCache {
+	if cfg.Lookup == nil {
+		cfg.Lookup = func(ctx context.Context, host string) ([]string, time.Duration, error) {
+			addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+			return addrs, 0, err
+		}
+	}
+	if cfg.DefaultTTL == 0 {
+		cfg.DefaultTTL = 30 * time.Second
+	}
+	if cfg.NegativeTTL == 0 {
+		cfg.NegativeTTL = 5 * time.Second
+	}
+	if cfg.MaxStale == 0 {
+		cfg.MaxStale = 5 * time.Minute
+	}
+	return &DNSCache{
+		config:  cfg,
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// WithDNSCache routes connection dialing through cache. It only applies to
+// the Client's default HTTP client; custom clients should use DialContext.
+// Entries expire after DefaultTTL unless the cache's LookupFunc supplies TTLs.
+func WithDNSCache(cache *DNSCache) Option {
+	return func(c *Config) {
+		c.DNSCache = cache
+	}
+}
+
+// Lookup returns the addresses for host, consulting the cache first
+func (d *DNSCache) Lookup(ctx context.Context, host string) ([]string, error) {
+	now := d.now()
+
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+
+	if ok && now.Before(entry.expires) {
+		if entry.err != nil {
+			d.negative.Add(1)
+			return nil, entry.err
+		}
+		d.hits.Add(1)
+		return entry.addrs, nil
+	}
+
+	d.misses.Add(1)
+	addrs, ttl, err := d.config.Lookup(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+	}
+
+	if err != nil {
+		// Serve the last good answer while it is within the stale window
+		if ok && entry.err == nil && now.Sub(entry.expires) < d.config.MaxStale {
+			d.stale.Add(1)
+			return entry.addrs, nil
+		}
+		d.store(host, dnsEntry{err: err, expires: now.Add(d.config.NegativeTTL)})
+		return nil, err
+	}
+
+	if ttl <= 0 {
+		ttl = d.config.DefaultTTL
+	}
+	d.store(host, dnsEntry{addrs: addrs, expires: now.Add(ttl)})
+	return addrs, nil
+}
+
+// Stats returns cumulative cache counters
+func (d *DNSCache) Stats() DNSCacheStats {
+	return DNSCacheStats{
+		Hits:         d.hits.Load(),
+		Misses:       d.misses.Load(),
+		StaleServed:  d.stale.Load(),
+		NegativeHits: d.negative.Load(),
+	}
+}
+
+// DialContext returns a dial function resolving through the cache and trying
+// each address in turn
+func (d *DNSCache) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		addrs, err := d.Lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		for _, ip := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+func (d *DNSCache) store(host string, e dnsEntry) {
+	d.mu.Lock()
+	d.entries[host] = e
+	d.mu.Unlock()
+}
+
+// transportWithDNSCache clones the default transport with cached dialing
+func transportWithDNSCache(cache *DNSCache) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = cache.DialContext(guardedDialer())
+	return t
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type fakeResolver struct {
+	calls int
+	addrs []string
+	ttl   time.Duration
+	err   error
+}
+
+func (f *fakeResolver) lookup(ctx context.Context, host string) ([]string, time.Duration, error) {
+	f.calls++
+	return f.addrs, f.ttl, f.err
+}
+
+func newTestCache(r *fakeResolver, now *time.Time) *DNSCache {
+	cache := NewDNSCache(DNSCacheConfig{
+		Lookup:      r.lookup,
+		DefaultTTL:  time.Minute,
+		NegativeTTL: 10 * time.Second,
+		MaxStale:    time.Hour,
+	})
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestDNSCache_TTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := &fakeResolver{addrs: []string{"10.0.0.1"}, ttl: 5 * time.Second}
+	cache := newTestCache(r, &now)
+	ctx := context.Background()
+
+	cache.Lookup(ctx, "example.com")
+	cache.Lookup(ctx, "example.com")
+	if r.calls != 1 {
+		t.Errorf("Expected 1 lookup within TTL, got %d", r.calls)
+	}
+
+	now = now.Add(6 * time.Second)
+	cache.Lookup(ctx, "example.com")
+	if r.calls != 2 {
+		t.Errorf("Expected refresh after resolver TTL, got %d lookups", r.calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Expected 1 hit and 2 misses, got %+v", stats)
+	}
+}
+
+func TestDNSCache_DefaultTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := &fakeResolver{addrs: []string{"10.0.0.1"}}
+	cache := newTestCache(r, &now)
+	ctx := context.Background()
+
+	cache.Lookup(ctx, "example.com")
+	now = now.Add(59 * time.Second)
+	cache.Lookup(ctx, "example.com")
+	if r.calls != 1 {
+		t.Errorf("Expected default TTL to apply when resolver reports none, got %d lookups", r.calls)
+	}
+}
+
+func TestDNSCache_StaleOnError(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := &fakeResolver{addrs: []string{"10.0.0.1"}, ttl: time.Second}
+	cache := newTestCache(r, &now)
+	ctx := context.Background()
+
+	cache.Lookup(ctx, "example.com")
+
+	now = now.Add(2 * time.Second)
+	r.err = errors.New("SERVFAIL")
+	addrs, err := cache.Lookup(ctx, "example.com")
+	if err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.1" {
+		t.Errorf("Expected stale answer on error, got %v, %v", addrs, err)
+	}
+	if cache.Stats().StaleServed != 1 {
+		t.Errorf("Expected 1 stale answer, got %+v", cache.Stats())
+	}
+
+	// Beyond the stale window the failure surfaces and is cached negatively
+	now = now.Add(2 * time.Hour)
+	if _, err := cache.Lookup(ctx, "example.com"); err == nil {
+		t.Error("Expected error beyond stale window")
+	}
+	calls := r.calls
+	if _, err := cache.Lookup(ctx, "example.com"); err == nil {
+		t.Error("Expected cached negative answer")
+	}
+	if r.calls != calls {
+		t.Errorf("Expected negative cache to skip lookup, got %d extra calls", r.calls-calls)
+	}
+	if cache.Stats().NegativeHits != 1 {
+		t.Errorf("Expected 1 negative hit, got %+v", cache.Stats())
+	}
+}
+
+func TestDNSCacheStats_HitRate(t *testing.T) {
+	s := DNSCacheStats{Hits: 3, Misses: 1}
+	if s.HitRate() != 0.75 {
+		t.Errorf("Expected hit rate 0.75, got %v", s.HitRate())
+	}
+	if (DNSCacheStats{}).HitRate() != 0 {
+		t.Error("Expected zero hit rate for empty stats")
+	}
+}
+
+func TestClient_WithDNSCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	now := time.Unix(1000, 0)
+	r := &fakeResolver{addrs: []string{"127.0.0.1"}}
+	cache := newTestCache(r, &now)
+
+	client, _ := NewClient("http://hooks.internal:"+u.Port(), testSecret, WithDNSCache(cache))
+	resp := client.Send(context.Background(), "test.event", nil)
+
+	if !resp.Success {
+		t.Fatalf("Expected success through cached resolver, got error: %v", resp.Error)
+	}
+	if r.calls != 1 {
+		t.Errorf("Expected resolver to be used once, got %d", r.calls)
+	}
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// egressDay is the layout of EgressUsage.Day
+const egressDay = "2006-01-02"
+
+// EgressUsage is the traffic sent to one endpoint on behalf of one tenant
+// during one UTC day
+type EgressUsage struct {
+	Day      string // UTC date, YYYY-MM-DD
+	Tenant   string // Set with ContextWithTenant; empty for untagged sends
+	Endpoint string // Target URL, credentials masked
+	Attempts uint64 // Attempts whose request body reached the wire
+	Bytes    uint64 // Request body bytes written, retries included
+}
+
+// WithEgressAccounting records EgressUsage per day, tenant and endpoint, read
+// with Client.EgressUsage, for chargeback or usage billing
+func WithEgressAccounting() Option {
+	return func(c *Config) {
+		c.EgressAccounting = true
+	}
+}
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a context whose sends are accounted to tenant
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant set by ContextWithTenant
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenant
+}
+
+// egressKey identifies one EgressUsage row
+type egressKey struct {
+	day, tenant, endpoint string
+}
+
+// egressLedger holds EgressUsage keyed by day, tenant and endpoint
+type egressLedger struct {
+	mu    sync.Mutex
+	usage map[egressKey]EgressUsage
+}
+
+// accountEgress records attempt a of d, unless it failed before the body was sent
+func (c *Client) accountEgress(d delivery, a Attempt) {
+	if !c.config.EgressAccounting || (a.Phase != "" && a.Phase != "wait") {
+		return
+	}
+	endpoint := a.Target
+	if endpoint == "" {
+		endpoint = c.config.TargetURL
+	}
+	k := egressKey{day: c.det.Now().UTC().Format(egressDay), tenant: d.tenant, endpoint: endpoint}
+
+	l := &c.egress
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.usage == nil {
+		l.usage = make(map[egressKey]EgressUsage)
+	}
+	u := l.usage[k]
+	u.Day, u.Tenant, u.Endpoint = k.day, k.tenant, k.endpoint
+	u.Attempts++
+	u.Bytes += uint64(len(d.body))
+	l.usage[k] = u
+}
+
+// EgressUsage returns the rows for UTC days from through to, inclusive, sorted
+// by day, tenant and endpoint. Zero bounds are open. It is empty unless
+// WithEgressAccounting is set.
+func (c *Client) EgressUsage(from, to time.Time) []EgressUsage {
+	first, last := "", ""
+	if !from.IsZero() {
+		first = from.UTC().Format(egressDay)
+	}
+	if !to.IsZero() {
+		last = to.UTC().Format(egressDay)
+	}
+
+	c.egress.mu.Lock()
+	out := make([]EgressUsage, 0, len(c.egress.usage))
+	for _, u := range c.egress.usage {
+		if (first == "" || u.Day >= first) && (last == "" || u.Day <= last) {
+			out = append(out, u)
+		}
+	}
+	c.egress.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b EgressUsage) int {
+		return strings.Compare(a.Day+"\x00"+a.Tenant+"\x00"+a.Endpoint, b.Day+"\x00"+b.Tenant+"\x00"+b.Endpoint)
+	})
+	return out
+}
+
+// WriteEgressCSV writes usage as CSV with a day,tenant,endpoint,attempts,bytes header row
+func WriteEgressCSV(w io.Writer, usage []EgressUsage) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"day", "tenant", "endpoint", "attempts", "bytes"})
+	for _, u := range usage {
+		cw.Write([]string{u.Day, u.Tenant, u.Endpoint, strconv.FormatUint(u.Attempts, 10), strconv.FormatUint(u.Bytes, 10)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_EgressUsage(t *testing.T) {
+	flaky, _ := countingServer(t, http.StatusServiceUnavailable)
+	ok, _ := countingServer(t, http.StatusOK)
+
+	det, _ := testDeterminism()
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	det.Now = func() time.Time { return now }
+	client, _ := NewClient(flaky.URL, testSecret, WithDeterminism(det), WithMaxRetries(3), WithEgressAccounting())
+
+	acme := ContextWithTenant(context.Background(), "acme")
+	resp := client.Send(acme, "order.created", map[string]string{"id": "1"})
+	if resp.Success {
+		t.Fatal("Expected the flaky endpoint to fail")
+	}
+
+	now = now.Add(2 * time.Hour) // Next UTC day
+	other, _ := NewClient(ok.URL, testSecret, WithDeterminism(det), WithEgressAccounting())
+	other.Send(acme, "order.created", map[string]string{"id": "2"})
+	other.Send(context.Background(), "order.created", map[string]string{"id": "3"})
+
+	usage := client.EgressUsage(time.Time{}, time.Time{})
+	if len(usage) != 1 {
+		t.Fatalf("Expected one row, got %+v", usage)
+	}
+	if u := usage[0]; u.Day != "2024-03-01" || u.Tenant != "acme" || u.Endpoint != flaky.URL || u.Attempts != 3 || u.Bytes != 3*uint64(resp.BodySize) {
+		t.Errorf("Unexpected usage %+v (body %d bytes)", u, resp.BodySize)
+	}
+
+	rows := other.EgressUsage(now, now)
+	if len(rows) != 2 || rows[0].Tenant != "" || rows[1].Tenant != "acme" || rows[1].Day != "2024-03-02" {
+		t.Errorf("Expected untagged then acme rows for 2024-03-02, got %+v", rows)
+	}
+	if got := other.EgressUsage(time.Time{}, now.AddDate(0, 0, -1)); len(got) != 0 {
+		t.Errorf("Expected no rows before 2024-03-02, got %+v", got)
+	}
+
+	plain, _ := NewClient(ok.URL, testSecret)
+	plain.Send(acme, "order.created", nil)
+	if len(plain.EgressUsage(time.Time{}, time.Time{})) != 0 {
+		t.Error("Expected no usage without WithEgressAccounting")
+	}
+}
+
+func TestClient_EgressUsage_SkipsUnsentAttempts(t *testing.T) {
+	client, _ := NewClient("http://127.0.0.1:1", testSecret, WithMaxRetries(1), WithEgressAccounting())
+	client.Send(context.Background(), "order.created", nil)
+	if usage := client.EgressUsage(time.Time{}, time.Time{}); len(usage) != 0 {
+		t.Errorf("Expected refused connections to cost nothing, got %+v", usage)
+	}
+}
+
+func TestWriteEgressCSV(t *testing.T) {
+	var b strings.Builder
+	err := WriteEgressCSV(&b, []EgressUsage{{Day: "2024-03-01", Tenant: "acme", Endpoint: "https://a.example/hook", Attempts: 2, Bytes: 300}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "day,tenant,endpoint,attempts,bytes\n2024-03-01,acme,https://a.example/hook,2,300\n"
+	if b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+}
@@ -0,0 +1,269 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEndpoint is returned when an endpoint fails pre-flight validation
+var ErrInvalidEndpoint = errors.New("webhook: invalid endpoint")
+
+// EndpointPolicy configures ValidateEndpoint
+type EndpointPolicy struct {
+	AllowPrivate bool          // Permit loopback, private, link-local and other non-public addresses
+	RequireHTTPS bool          // Reject plain http:// endpoints
+	Probe        bool          // Send an unsigned HEAD request once the other checks pass
+	Timeout      time.Duration // Budget for each network check (default: 5s)
+	Lookup       LookupFunc    // Resolver (default: net.DefaultResolver)
+	TLSConfig    *tls.Config   // TLS settings for the handshake check (default: system roots)
+}
+
+// EndpointCheck is the outcome of one validation step
+type EndpointCheck struct {
+	Name   string // syntax, dns, policy, tls or probe
+	OK     bool
+	Detail string
+}
+
+// EndpointReport collects the diagnostics for one endpoint URL
+type EndpointReport struct {
+	URL    string
+	Checks []EndpointCheck
+}
+
+// OK reports whether every check passed
+func (r *EndpointReport) OK() bool {
+	for _, c := range r.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns an ErrInvalidEndpoint error naming the failed checks, or nil
+func (r *EndpointReport) Err() error {
+	var failed []string
+	for _, c := range r.Checks {
+		if !c.OK {
+			failed = append(failed, c.Name+": "+c.Detail)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidEndpoint, strings.Join(failed, "; "))
+}
+
+func (r *EndpointReport) add(name string, ok bool, format string, args ...any) bool {
+	r.Checks = append(r.Checks, EndpointCheck{Name: name, OK: ok, Detail: fmt.Sprintf(format, args...)})
+	return ok
+}
+
+// ValidateEndpoint checks an endpoint URL before it is used as a delivery
+// target: URL syntax, DNS resolution, SSRF policy on every resolved address,
+// the TLS handshake for https URLs and, optionally, a HEAD probe. The
+// handshake and probe connect to the addresses the policy check vetted, never
+// to a fresh lookup. Checks stop at the first failure that makes later ones
+// meaningless.
+func ValidateEndpoint(ctx context.Context, rawURL string, policy EndpointPolicy) *EndpointReport {
+	if policy.Timeout == 0 {
+		policy.Timeout = 5 * time.Second
+	}
+	if policy.Lookup == nil {
+		policy.Lookup = func(ctx context.Context, host string) ([]string, time.Duration, error) {
+			addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+			return addrs, 0, err
+		}
+	}
+
+	report := &EndpointReport{URL: rawURL}
+
+	u, err := url.Parse(rawURL)
+	switch {
+	case err != nil:
+		report.add("syntax", false, "%v", err)
+		return report
+	case u.Scheme != "http" && u.Scheme != "https":
+		report.add("syntax", false, "scheme must be http or https, got %q", u.Scheme)
+		return report
+	case u.Hostname() == "":
+		report.add("syntax", false, "missing host")
+		return report
+	case u.User != nil:
+		report.add("syntax", false, "credentials in the URL are not allowed")
+		return report
+	case policy.RequireHTTPS && u.Scheme != "https":
+		report.add("syntax", false, "https is required")
+		return report
+	}
+	report.add("syntax", true, "%s", u.Redacted())
+
+	host := u.Hostname()
+	addrs := []string{host}
+	if net.ParseIP(host) == nil {
+		lctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+		addrs, _, err = policy.Lookup(lctx, host)
+		cancel()
+		if err == nil && len(addrs) == 0 {
+			err = errors.New("no addresses")
+		}
+		if !report.add("dns", err == nil, "%s", dnsDetail(host, addrs, err)) {
+			return report
+		}
+	}
+
+	if !policy.AllowPrivate {
+		for _, a := range addrs {
+			if ip := net.ParseIP(a); ip == nil || !isPublicIP(ip) {
+				report.add("policy", false, "%s: non-public address %s is not permitted", host, a)
+				return report
+			}
+		}
+	}
+	report.add("policy", true, "%d address(es) permitted", len(addrs))
+
+	if u.Scheme == "https" {
+		ok, detail := tlsHandshake(ctx, u, addrs, policy)
+		if !report.add("tls", ok, "%s", detail) {
+			return report
+		}
+	}
+
+	if policy.Probe {
+		ok, detail := probe(ctx, u, addrs, policy)
+		report.add("probe", ok, "%s", detail)
+	}
+	return report
+}
+
+func dnsDetail(host string, addrs []string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s: %v", host, err)
+	}
+	return fmt.Sprintf("%s -> %s", host, strings.Join(addrs, ", "))
+}
+
+// nonPublicNets are special-purpose ranges the net.IP predicates do not cover
+var nonPublicNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),       // "This network"
+	mustCIDR("100.64.0.0/10"),   // Carrier-grade NAT
+	mustCIDR("192.0.0.0/24"),    // IETF protocol assignments
+	mustCIDR("192.0.2.0/24"),    // Documentation (TEST-NET-1)
+	mustCIDR("198.18.0.0/15"),   // Benchmarking
+	mustCIDR("198.51.100.0/24"), // Documentation (TEST-NET-2)
+	mustCIDR("203.0.113.0/24"),  // Documentation (TEST-NET-3)
+	mustCIDR("240.0.0.0/4"),     // Reserved, and the limited broadcast address
+	mustCIDR("2001:db8::/32"),   // IPv6 documentation
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// isPublicIP rejects addresses an SSRF-safe sender must not reach
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// pinnedDial dials the vetted addresses in turn on the requested port, so a
+// check's connection goes where the policy check looked rather than wherever
+// a second lookup of the host points
+func pinnedDial(addrs []string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		var dialer net.Dialer
+		var errs []error
+		for _, ip := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// tlsHandshake connects to the vetted addrs with SNI set to the URL's host
+func tlsHandshake(ctx context.Context, u *url.URL, addrs []string, policy EndpointPolicy) (bool, string) {
+	cfg := &tls.Config{}
+	if policy.TLSConfig != nil {
+		cfg = policy.TLSConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = u.Hostname()
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	hctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+	raw, err := pinnedDial(addrs)(hctx, "tcp", addr)
+	if err != nil {
+		return false, err.Error()
+	}
+	conn := tls.Client(raw, cfg)
+	defer conn.Close()
+	if err := conn.HandshakeContext(hctx); err != nil {
+		return false, err.Error()
+	}
+
+	state := conn.ConnectionState()
+	cert := state.PeerCertificates[0]
+	return true, fmt.Sprintf("%s, certificate for %s expires %s", tls.VersionName(state.Version),
+		cert.Subject.CommonName, cert.NotAfter.Format(time.DateOnly))
+}
+
+// probe sends the HEAD request over a connection to the vetted addrs
+func probe(ctx context.Context, u *url.URL, addrs []string, policy EndpointPolicy) (bool, string) {
+	pctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(pctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: policy.TLSConfig, DisableKeepAlives: true, DialContext: pinnedDial(addrs)},
+		// A redirect could point at an address the policy check never saw
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err.Error()
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return false, fmt.Sprintf("HEAD returned %d", resp.StatusCode)
+	}
+	// Receivers commonly answer HEAD with 4xx (e.g. 405); reachability is what matters
+	return true, fmt.Sprintf("HEAD returned %d", resp.StatusCode)
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrEnrichment wraps the error of an enricher whose policy is EnrichFail
+var ErrEnrichment = errors.New("webhook: enrichment failed")
+
+// Enricher augments a payload before it is marshaled and signed, e.g. by
+// looking up a customer profile by ID and embedding selected fields. It should
+// return a new Data value rather than mutate the caller's.
+type Enricher interface {
+	Enrich(ctx context.Context, payload Payload) (Payload, error)
+}
+
+// EnricherFunc adapts a function to Enricher
+type EnricherFunc func(ctx context.Context, payload Payload) (Payload, error)
+
+// Enrich calls f
+func (f EnricherFunc) Enrich(ctx context.Context, payload Payload) (Payload, error) {
+	return f(ctx, payload)
+}
+
+// EnrichPolicy decides what a failed or timed-out enricher does to the send
+type EnrichPolicy int
+
+const (
+	EnrichSkip EnrichPolicy = iota // Log and send the payload as it was before the enricher
+	EnrichFail                     // Fail the send with ErrEnrichment
+)
+
+// EnrichStage is one enricher with its timeout and failure policy
+type EnrichStage struct {
+	Name      string
+	Enricher  Enricher
+	Timeout   time.Duration // Zero: bounded only by the send's context
+	OnFailure EnrichPolicy
+}
+
+// WithEnricher appends an enrichment stage. Stages run in order before every
+// send, each seeing the previous one's output. The event name cannot be
+// changed by an enricher.
+func WithEnricher(name string, e Enricher, timeout time.Duration, onFailure EnrichPolicy) Option {
+	return func(c *Config) {
+		c.Enrichers = append(c.Enrichers, EnrichStage{Name: name, Enricher: e, Timeout: timeout, OnFailure: onFailure})
+	}
+}
+
+// enrich runs every stage against payload
+func (c *Client) enrich(ctx context.Context, payload Payload) (Payload, error) {
+	for _, stage := range c.config.Enrichers {
+		enriched, err := stage.run(ctx, payload)
+		if err != nil {
+			if stage.OnFailure == EnrichFail {
+				return payload, fmt.Errorf("%w: %s: %w", ErrEnrichment, stage.Name, err)
+			}
+			c.logger.Warn("webhook: enricher failed, sending without it", "enricher", stage.Name, "event", payload.Event, "error", err)
+			continue
+		}
+		enriched.Event = payload.Event
+		payload = enriched
+	}
+	return payload, nil
+}
+
+// run calls the enricher on its own goroutine so a stage that ignores its
+// context still cannot hold the send past Timeout
+func (s EnrichStage) run(ctx context.Context, payload Payload) (Payload, error) {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		payload Payload
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		p, err := s.Enricher.Enrich(ctx, payload)
+		done <- result{p, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.payload, r.err
+	case <-ctx.Done():
+		return payload, ctx.Err()
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Enricher(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+	}))
+	defer server.Close()
+
+	profile := EnricherFunc(func(ctx context.Context, p Payload) (Payload, error) {
+		data := p.Data.(map[string]any)
+		p.Data = map[string]any{"customer_id": data["customer_id"], "customer": map[string]any{"tier": "gold"}}
+		p.Event = "renamed.event"
+		return p, nil
+	})
+	client, err := NewClient(server.URL, testSecret, WithEnricher("profile", profile, time.Second, EnrichFail))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{"customer_id": "c1"})
+	if !resp.Success {
+		t.Fatalf("Expected success, got %v", resp.Error)
+	}
+	data := body["data"].(map[string]any)
+	if data["customer"].(map[string]any)["tier"] != "gold" {
+		t.Errorf("Expected enriched customer tier, got %v", data)
+	}
+	if body["event"] != "order.created" {
+		t.Errorf("Expected event name to be kept, got %v", body["event"])
+	}
+}
+
+func TestClient_Enricher_FailurePolicies(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	failing := EnricherFunc(func(ctx context.Context, p Payload) (Payload, error) {
+		return p, errors.New("profile service down")
+	})
+	slow := EnricherFunc(func(ctx context.Context, p Payload) (Payload, error) {
+		time.Sleep(time.Second)
+		p.Data = "late"
+		return p, nil
+	})
+
+	skip, _ := NewClient(server.URL, testSecret,
+		WithEnricher("profile", failing, 0, EnrichSkip),
+		WithEnricher("slow", slow, 20*time.Millisecond, EnrichSkip))
+	started := time.Now()
+	if resp := skip.Send(context.Background(), "order.created", "original"); !resp.Success {
+		t.Fatalf("Expected skipped enrichers to still send, got %v", resp.Error)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected timeout to bound a stuck enricher, took %v", elapsed)
+	}
+
+	fail, _ := NewClient(server.URL, testSecret, WithEnricher("profile", failing, 0, EnrichFail))
+	resp := fail.Send(context.Background(), "order.created", "original")
+	if !errors.Is(resp.Error, ErrEnrichment) {
+		t.Errorf("Expected ErrEnrichment, got %v", resp.Error)
+	}
+	if *hits != 1 {
+		t.Errorf("Expected 1 delivery, got %d", *hits)
+	}
+}
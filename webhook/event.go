@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrInvalidEvent is returned when an event fails validation
+var ErrInvalidEvent = errors.New("webhook: invalid event")
+
+// eventNamePattern accepts dot-separated lowercase segments, e.g. order.created
+var eventNamePattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)+$`)
+
+// maxKeyLength bounds idempotency and ordering keys
+const maxKeyLength = 256
+
+// Event is a validated webhook event produced by EventBuilder
+type Event struct {
+	Name           string
+	Data           any
+	Timestamp      time.Time // Zero means the send time
+	IdempotencyKey string
+	OrderingKey    string
+	CorrelationID  string // Empty: taken from the send context
+	CausationID    string // Empty: taken from the send context
+	Sequence       uint64 // Position among events with the same OrderingKey, from 1; zero for none
+}
+
+// EventBuilder assembles an Event fluently and validates it on Build
+type EventBuilder struct {
+	event Event
+}
+
+// NewEvent starts building an event with the given name
+func NewEvent(name string) *EventBuilder {
+	return &EventBuilder{event: Event{Name: name}}
+}
+
+// WithData sets the event data
+func (b *EventBuilder) WithData(data any) *EventBuilder {
+	b.event.Data = data
+	return b
+}
+
+// WithTimestamp overrides the payload timestamp
+func (b *EventBuilder) WithTimestamp(t time.Time) *EventBuilder {
+	b.event.Timestamp = t
+	return b
+}
+
+// WithIdemKey sets the idempotency key
+func (b *EventBuilder) WithIdemKey(key string) *EventBuilder {
+	b.event.IdempotencyKey = key
+	return b
+}
+
+// WithOrderingKey sets the ordering key
+func (b *EventBuilder) WithOrderingKey(key string) *EventBuilder {
+	b.event.OrderingKey = key
+	return b
+}
+
+// WithCorrelationID sets the ID shared by every event in the chain
+func (b *EventBuilder) WithCorrelationID(id string) *EventBuilder {
+	b.event.CorrelationID = id
+	return b
+}
+
+// WithCausationID sets the message ID of the event that caused this one
+func (b *EventBuilder) WithCausationID(id string) *EventBuilder {
+	b.event.CausationID = id
+	return b
+}
+
+// WithSequence sets the event's position among those sharing its ordering key,
+// so receivers can restore the order with receiver.NewOrderingBuffer
+func (b *EventBuilder) WithSequence(seq uint64) *EventBuilder {
+	b.event.Sequence = seq
+	return b
+}
+
+// Build validates the event and returns it
+func (b *EventBuilder) Build() (Event, error) {
+	if err := b.event.Validate(); err != nil {
+		return Event{}, err
+	}
+	return b.event, nil
+}
+
+// Validate checks the event name format and required fields
+func (e Event) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEvent)
+	}
+	if !eventNamePattern.MatchString(e.Name) {
+		return fmt.Errorf("%w: name %q must be lowercase dot-separated segments (e.g. order.created)", ErrInvalidEvent, e.Name)
+	}
+	if e.Data == nil {
+		return fmt.Errorf("%w: data is required", ErrInvalidEvent)
+	}
+	if len(e.IdempotencyKey) > maxKeyLength {
+		return fmt.Errorf("%w: idempotency key exceeds %d bytes", ErrInvalidEvent, maxKeyLength)
+	}
+	if len(e.OrderingKey) > maxKeyLength {
+		return fmt.Errorf("%w: ordering key exceeds %d bytes", ErrInvalidEvent, maxKeyLength)
+	}
+	if e.Sequence > 0 && e.OrderingKey == "" {
+		return fmt.Errorf("%w: sequence requires an ordering key", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// SendEvent validates and dispatches a built event
+func (c *Client) SendEvent(ctx context.Context, e Event) Response {
+	if err := e.Validate(); err != nil {
+		return Response{Error: err}
+	}
+
+	ts := e.Timestamp
+	if ts.IsZero() {
+		ts = c.det.Now()
+	}
+
+	var opts []SendOption
+	if e.IdempotencyKey != "" {
+		opts = append(opts, WithIdempotencyKey(e.IdempotencyKey))
+	}
+	if e.OrderingKey != "" {
+		opts = append(opts, WithOrderingKey(e.OrderingKey))
+	}
+
+	payload := Payload{Event: e.Name, Timestamp: ts, Data: e.Data, CorrelationID: e.CorrelationID, CausationID: e.CausationID, Sequence: e.Sequence}
+	return c.SendPayload(ctx, payload, opts...)
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEventBuilder_Validation(t *testing.T) {
+	data := map[string]any{"id": "1"}
+
+	tests := []struct {
+		name    string
+		builder *EventBuilder
+		wantErr bool
+	}{
+		{name: "valid", builder: NewEvent("order.created").WithData(data), wantErr: false},
+		{name: "nested namespace", builder: NewEvent("billing.invoice.paid").WithData(data), wantErr: false},
+		{name: "empty name", builder: NewEvent("").WithData(data), wantErr: true},
+		{name: "single segment", builder: NewEvent("order").WithData(data), wantErr: true},
+		{name: "uppercase", builder: NewEvent("Order.Created").WithData(data), wantErr: true},
+		{name: "empty segment", builder: NewEvent("order..created").WithData(data), wantErr: true},
+		{name: "missing data", builder: NewEvent("order.created"), wantErr: true},
+		{name: "oversized key", builder: NewEvent("order.created").WithData(data).WithIdemKey(strings.Repeat("k", 300)), wantErr: true},
+		{name: "sequence", builder: NewEvent("order.created").WithData(data).WithOrderingKey("cust-1").WithSequence(3), wantErr: false},
+		{name: "sequence without ordering key", builder: NewEvent("order.created").WithData(data).WithSequence(3), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Expected error to wrap ErrInvalidEvent, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_SendEvent(t *testing.T) {
+	var header http.Header
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret)
+
+	event, err := NewEvent("order.created").
+		WithData(map[string]any{"order_id": "1"}).
+		WithIdemKey("order-1").
+		WithOrderingKey("cust-1").
+		WithSequence(2).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	resp := client.SendEvent(context.Background(), event)
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if header.Get("Idempotency-Key") != "order-1" || header.Get("Webhook-Ordering-Key") != "cust-1" {
+		t.Errorf("Expected idempotency and ordering headers, got %v", header)
+	}
+	if !strings.Contains(string(body), `"sequence":2`) {
+		t.Errorf("Expected the sequence in the payload, got %s", body)
+	}
+
+	// Unvalidated events are rejected before any network call
+	resp = client.SendEvent(context.Background(), Event{Name: "Bad"})
+	if !errors.Is(resp.Error, ErrInvalidEvent) {
+		t.Errorf("Expected ErrInvalidEvent, got: %v", resp.Error)
+	}
+}
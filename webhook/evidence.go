@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Evidence is a sealed snapshot of one request of a delivery attempt: the
+// exact header fields and body bytes written, the receiver's TLS certificates
+// and its response. Each redirect hop and hedged copy is a request of its own.
+// Records chain through PrevHash, so removing or altering one breaks every
+// later Hash.
+type Evidence struct {
+	MessageID   string            `json:"message_id"`
+	Event       string            `json:"event"`
+	Attempt     int               `json:"attempt"`
+	Hop         int               `json:"hop"`             // 0 for the target, n for the nth redirect followed
+	Hedge       bool              `json:"hedge,omitempty"` // The hedged copy of the attempt
+	Time        time.Time         `json:"time"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Header      []HeaderField     `json:"header"` // In the order written, as the transport named them
+	Body        []byte            `json:"body"`   // As read from the request body by the transport
+	TLSVersion  string            `json:"tls_version,omitempty"`
+	CipherSuite string            `json:"cipher_suite,omitempty"`
+	Peer        []PeerCertificate `json:"peer_certificates,omitempty"` // Leaf first
+	Response    *EvidenceResponse `json:"response,omitempty"`          // Nil when no response arrived
+	Error       string            `json:"error,omitempty"`             // Transport error when no response arrived
+	PrevHash    string            `json:"prev_hash"`
+	Hash        string            `json:"hash"` // Hex SHA-256 of the record with Hash empty
+}
+
+// HeaderField is one request header line
+type HeaderField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PeerCertificate identifies one certificate presented by the receiver
+type PeerCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	SHA256    string    `json:"sha256"` // Fingerprint of the DER encoding
+}
+
+// EvidenceResponse is the receiver's answer to one request
+type EvidenceResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"` // As far as the client read it
+}
+
+// WithEvidence records Evidence for every request of every delivery attempt
+// and passes it to fn, in chain order, for durable storage. fn must not block.
+// The records hold signatures and full bodies, so store them like the
+// payloads themselves.
+func WithEvidence(fn func(Evidence)) Option {
+	return func(c *Config) {
+		c.OnEvidence = fn
+	}
+}
+
+// Verify reports whether the record still matches its Hash; chain integrity
+// also needs each PrevHash to equal the previous record's Hash
+func (e Evidence) Verify() bool {
+	return e.Hash != "" && e.seal() == e.Hash
+}
+
+func (e Evidence) seal() string {
+	e.Hash = ""
+	b, _ := json.Marshal(e)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// evidenceChain links records in the order they are produced
+type evidenceChain struct {
+	mu   sync.Mutex
+	last string
+}
+
+// evidenceKey carries the evidenceScope of an attempt's requests
+type evidenceKey struct{}
+
+// evidenceScope identifies the delivery attempt a request belongs to
+type evidenceScope struct {
+	d       delivery
+	attempt int
+}
+
+// withEvidence marks ctx so requests sent under it are recorded as evidence
+func (c *Client) withEvidence(ctx context.Context, d delivery, attempt int) context.Context {
+	if c.config.OnEvidence == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, evidenceKey{}, evidenceScope{d: d, attempt: attempt})
+}
+
+// evidenceTransport records one Evidence per request it carries, so every
+// redirect hop and hedged copy of an attempt gets its own record, holding the
+// header fields and body bytes that request actually wrote
+type evidenceTransport struct {
+	c    *Client
+	base http.RoundTripper
+}
+
+func (t *evidenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	scope, ok := req.Context().Value(evidenceKey{}).(evidenceScope)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	w := &wireCapture{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), w.clientTrace()))
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = teeBody{io.TeeReader(req.Body, w), req.Body}
+	}
+	hop := 0
+	for r := req.Response; r != nil && r.Request != nil; r = r.Request.Response {
+		hop++
+	}
+	e := Evidence{
+		MessageID: scope.d.msgID,
+		Event:     scope.d.event,
+		Attempt:   scope.attempt,
+		Hop:       hop,
+		Hedge:     req.Header.Get(HedgeHeader) != "",
+		Time:      t.c.det.Now(),
+		Method:    req.Method,
+		URL:       req.URL.String(),
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		e.Error = err.Error()
+		t.c.recordEvidence(e, w)
+		return nil, err
+	}
+	resp.Body = &evidenceBody{ReadCloser: resp.Body, c: t.c, e: e, w: w, resp: resp}
+	return resp, nil
+}
+
+// wireCapture collects what one request wrote
+type wireCapture struct {
+	mu     sync.Mutex
+	header []HeaderField
+	body   bytes.Buffer
+}
+
+// Write records request body bytes as the transport reads them
+func (w *wireCapture) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.body.Write(p)
+}
+
+func (w *wireCapture) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		WroteHeaderField: func(key string, values []string) {
+			w.mu.Lock()
+			for _, v := range values {
+				w.header = append(w.header, HeaderField{Name: key, Value: v})
+			}
+			w.mu.Unlock()
+		},
+	}
+}
+
+// teeBody passes request body reads through a wireCapture
+type teeBody struct {
+	io.Reader
+	io.Closer
+}
+
+// evidenceBody records the request's evidence once its response is closed,
+// with the response body as far as the client read it
+type evidenceBody struct {
+	io.ReadCloser
+	c    *Client
+	e    Evidence
+	w    *wireCapture
+	resp *http.Response
+	read bytes.Buffer
+	once sync.Once
+}
+
+func (b *evidenceBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read.Write(p[:n])
+	return n, err
+}
+
+func (b *evidenceBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.e.Response = &EvidenceResponse{StatusCode: b.resp.StatusCode, Header: b.resp.Header, Body: b.read.Bytes()}
+		if b.resp.TLS != nil {
+			b.e.TLSVersion = tls.VersionName(b.resp.TLS.Version)
+			b.e.CipherSuite = tls.CipherSuiteName(b.resp.TLS.CipherSuite)
+			for _, cert := range b.resp.TLS.PeerCertificates {
+				b.e.Peer = append(b.e.Peer, peerCertificate(cert))
+			}
+		}
+		b.c.recordEvidence(b.e, b.w)
+	})
+	return err
+}
+
+// recordEvidence seals the snapshot of one request and hands it to OnEvidence
+func (c *Client) recordEvidence(e Evidence, w *wireCapture) {
+	w.mu.Lock()
+	e.Header = w.header
+	e.Body = bytes.Clone(w.body.Bytes())
+	w.mu.Unlock()
+
+	chain := &c.evidence
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	e.PrevHash = chain.last
+	e.Hash = e.seal()
+	chain.last = e.Hash
+	c.config.OnEvidence(e)
+}
+
+func peerCertificate(cert *x509.Certificate) PeerCertificate {
+	sum := sha256.Sum256(cert.Raw)
+	return PeerCertificate{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		Serial:    cert.SerialNumber.String(),
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+}
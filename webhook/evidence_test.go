@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Evidence(t *testing.T) {
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Receipt", "r-1")
+		w.Write([]byte("received"))
+	}))
+	defer server.Close()
+
+	var records []Evidence
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(2),
+		WithHTTPClient(server.Client()), WithEvidence(func(e Evidence) { records = append(records, e) }))
+
+	resp := client.Send(context.Background(), "order.created", map[string]string{"id": "1"})
+	if !resp.Success || len(records) != 2 {
+		t.Fatalf("Expected two evidence records for a delivered message, got %d", len(records))
+	}
+
+	first, last := records[0], records[1]
+	if first.PrevHash != "" || last.PrevHash != first.Hash || !first.Verify() || !last.Verify() {
+		t.Errorf("Expected a verifiable hash chain, got %+v", records)
+	}
+	if first.Response.StatusCode != http.StatusServiceUnavailable || last.Attempt != 2 || last.MessageID != resp.MessageID {
+		t.Errorf("Unexpected records %+v", records)
+	}
+	if last.Response.Header.Get("X-Receipt") != "r-1" || string(last.Response.Body) != "received" {
+		t.Errorf("Expected the response captured, got %+v", last.Response)
+	}
+	if !slices.ContainsFunc(last.Header, func(f HeaderField) bool { return f.Name == "Svix-Signature" && f.Value != "" }) {
+		t.Errorf("Expected the signature among the written headers, got %+v", last.Header)
+	}
+	if last.Method != http.MethodPost || last.URL != server.URL || len(last.Body) == 0 {
+		t.Errorf("Expected the request captured, got %s %s", last.Method, last.URL)
+	}
+	if last.TLSVersion == "" || len(last.Peer) == 0 || last.Peer[0].SHA256 == "" {
+		t.Errorf("Expected the peer certificate captured, got %q %+v", last.TLSVersion, last.Peer)
+	}
+
+	last.Response.Body = []byte("tampered")
+	if last.Verify() {
+		t.Error("Expected an altered record to fail verification")
+	}
+}
+
+func TestClient_Evidence_NetworkError(t *testing.T) {
+	var records []Evidence
+	det, _ := testDeterminism()
+	client, _ := NewClient("http://127.0.0.1:1", testSecret, WithDeterminism(det), WithMaxRetries(1),
+		WithEvidence(func(e Evidence) { records = append(records, e) }))
+
+	client.Send(context.Background(), "order.created", nil)
+	if len(records) != 1 || records[0].Response != nil || records[0].Error == "" || !records[0].Verify() {
+		t.Errorf("Expected a sealed record of the failure, got %+v", records)
+	}
+}
+
+func TestClient_Evidence_PerRequest(t *testing.T) {
+	var headers []http.Header
+	other := redirectServer(t, nil, &headers)
+	server := redirectServer(t, func(string) string { return other.URL + "/new" }, &headers)
+
+	var records []Evidence
+	policy := RedirectFollow(3)
+	policy.AllowPrivate = true
+	client, _ := NewClient(server.URL+"/old", testSecret, WithRedirectPolicy(policy),
+		WithEvidence(func(e Evidence) { records = append(records, e) }))
+
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Success {
+		t.Fatalf("Expected success, got %v", resp.Error)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected one record per hop, got %d", len(records))
+	}
+	hasSignature := func(e Evidence) bool {
+		return slices.ContainsFunc(e.Header, func(f HeaderField) bool { return f.Name == "Svix-Signature" })
+	}
+	first, hop := records[0], records[1]
+	if first.Hop != 0 || first.Response.StatusCode != http.StatusTemporaryRedirect || !hasSignature(first) {
+		t.Errorf("Expected the signed first request and its redirect, got %+v", first)
+	}
+	if hop.Hop != 1 || hop.URL != other.URL+"/new" || hop.Response.StatusCode != http.StatusOK || hasSignature(hop) {
+		t.Errorf("Expected the cross-origin hop with only its own, unsigned headers, got %+v", hop)
+	}
+	if len(hop.Body) == 0 || hop.PrevHash != first.Hash {
+		t.Errorf("Expected the hop's wire body in a chained record, got %+v", hop)
+	}
+}
+
+func TestClient_Evidence_Hedged(t *testing.T) {
+	var mu sync.Mutex
+	n := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		mu.Lock()
+		n++
+		first := n == 1
+		mu.Unlock()
+		if first {
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	var mu2 sync.Mutex
+	var records []Evidence
+	client, _ := NewClient(server.URL, testSecret, WithHedging(0.95, 20*time.Millisecond),
+		WithEvidence(func(e Evidence) { mu2.Lock(); records = append(records, e); mu2.Unlock() }))
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Success {
+		t.Fatalf("Expected the hedge to succeed, got %v", resp.Error)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu2.Lock()
+		got := slices.Clone(records)
+		mu2.Unlock()
+		if len(got) == 2 || time.Now().After(deadline) {
+			records = got
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	i := slices.IndexFunc(records, func(e Evidence) bool { return e.Hedge })
+	if len(records) != 2 || i < 0 {
+		t.Fatalf("Expected a record for each copy, got %+v", records)
+	}
+	hedge := records[i]
+	if hedge.Response == nil || hedge.Response.StatusCode != http.StatusOK ||
+		!slices.ContainsFunc(hedge.Header, func(f HeaderField) bool { return f.Name == HedgeHeader }) {
+		t.Errorf("Expected the winning copy's own headers and response, got %+v", hedge)
+	}
+	if loser := records[1-i]; loser.Response != nil || loser.Error == "" {
+		t.Errorf("Expected the cancelled copy recorded with its error, got %+v", loser)
+	}
+}
@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HedgeHeader marks the hedged copy of an attempt; like AttemptHeader it is not signed
+const HedgeHeader = "Webhook-Hedge"
+
+const (
+	hedgeSamples    = 128 // Recent successful latencies kept for the percentile
+	hedgeMinSamples = 16  // Below this, HedgeMinDelay alone is the threshold
+)
+
+// WithHedging sends a second copy of an attempt to the same endpoint when the
+// first has not answered within the given percentile (e.g. 0.95) of recent
+// successful latencies, and takes whichever succeeds first. Both copies carry
+// the same message ID, so receivers deduplicating by svix-id process it once.
+// minDelay is the threshold until enough latencies are known, and its floor.
+func WithHedging(percentile float64, minDelay time.Duration) Option {
+	return func(c *Config) {
+		c.HedgePercentile = percentile
+		c.HedgeMinDelay = minDelay
+	}
+}
+
+// latencyWindow keeps the latest successful response latencies
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (w *latencyWindow) observe(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.samples) < hedgeSamples {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % hedgeSamples
+}
+
+// percentile returns the p-th latency, false until enough are known
+func (w *latencyWindow) percentile(p float64) (time.Duration, bool) {
+	w.mu.Lock()
+	sorted := slices.Clone(w.samples)
+	w.mu.Unlock()
+	if len(sorted) < hedgeMinSamples {
+		return 0, false
+	}
+	slices.Sort(sorted)
+	i := min(int(p*float64(len(sorted))), len(sorted)-1)
+	return sorted[i], true
+}
+
+// hedgeDelay is how long an attempt runs alone before its copy is sent
+func (c *Client) hedgeDelay() time.Duration {
+	if d, ok := c.latency.percentile(c.config.HedgePercentile); ok {
+		return max(d, c.config.HedgeMinDelay)
+	}
+	return c.config.HedgeMinDelay
+}
+
+// hedgeOutcome describes the hedged copy of an attempt
+type hedgeOutcome struct {
+	trace *attemptTrace // The copy's own trace; nil when no copy was sent
+	used  bool          // The response or error returned is the copy's
+}
+
+// hedgeResult is the outcome of one copy of an attempt
+type hedgeResult struct {
+	copy int // 0 for the original, 1 for the hedge
+	resp *http.Response
+	err  error
+}
+
+func (r hedgeResult) ok(c *Client) bool {
+	return r.err == nil && c.delivered(r.resp.StatusCode)
+}
+
+// cancelBody releases a copy's context once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// do sends req, hedging it when enabled. The copy is sent with its own trace,
+// so its timings and evidence are recorded whether or not it wins. The first
+// 2xx wins and the other copy is cancelled; when neither succeeds the first
+// outcome is returned.
+func (c *Client) do(ctx context.Context, req *http.Request, d delivery, attempt int) (*http.Response, hedgeOutcome, error) {
+	var hedge hedgeOutcome
+	if c.config.HedgePercentile <= 0 {
+		resp, err := c.http.Do(req)
+		return resp, hedge, err
+	}
+
+	results := make(chan hedgeResult, 2)
+	var cancels [2]context.CancelFunc
+	launch := func(copy int, req *http.Request, cancel context.CancelFunc) {
+		cancels[copy] = cancel
+		go func() {
+			start := time.Now()
+			resp, err := c.http.Do(req)
+			r := hedgeResult{copy: copy, resp: resp, err: err}
+			if r.ok(c) {
+				c.latency.observe(time.Since(start))
+			}
+			results <- r
+		}()
+	}
+
+	primaryCtx, cancel := context.WithCancel(req.Context())
+	launch(0, req.WithContext(primaryCtx), cancel)
+	inflight := 1
+
+	timer := time.NewTimer(c.hedgeDelay())
+	defer timer.Stop()
+	wait := timer.C
+
+	var first *hedgeResult // First failure, returned if no copy succeeds
+	for {
+		select {
+		case <-wait:
+			wait = nil
+			hedgeCtx, cancel := context.WithCancel(ctx)
+			tr := &attemptTrace{start: time.Now()}
+			hreq, err := c.newRequest(httptrace.WithClientTrace(hedgeCtx, tr.clientTrace()), d, attempt)
+			if err != nil {
+				cancel()
+				continue
+			}
+			hreq.Header.Set(HedgeHeader, "1")
+			launch(1, hreq, cancel)
+			inflight++
+			hedge.trace = tr
+
+		case r := <-results:
+			inflight--
+			if r.ok(c) {
+				if first != nil {
+					discard(*first, cancels)
+				}
+				if inflight > 0 {
+					cancels[1-r.copy]()
+					go func() { discard(<-results, cancels) }()
+				}
+				r.resp.Body = cancelBody{r.resp.Body, cancels[r.copy]}
+				hedge.used = r.copy == 1
+				return r.resp, hedge, nil
+			}
+			if first == nil {
+				first = &r
+			} else {
+				discard(r, cancels)
+			}
+			// A failure before the threshold is an ordinary failed attempt
+			if inflight == 0 {
+				hedge.used = first.copy == 1
+				if first.err != nil {
+					cancels[first.copy]()
+					return nil, hedge, first.err
+				}
+				first.resp.Body = cancelBody{first.resp.Body, cancels[first.copy]}
+				return first.resp, hedge, nil
+			}
+		}
+	}
+}
+
+// discard closes a losing copy's response and releases its context
+func discard(r hedgeResult, cancels [2]context.CancelFunc) {
+	if r.resp != nil {
+		io.Copy(io.Discard, io.LimitReader(r.resp.Body, 4<<10))
+		r.resp.Body.Close()
+	}
+	cancels[r.copy]()
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Hedging(t *testing.T) {
+	var mu sync.Mutex
+	var ids, hedges []string
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body) // The server notices a cancelled request only once the body is read
+		mu.Lock()
+		first := len(ids) == 0
+		ids = append(ids, r.Header.Get("svix-id"))
+		hedges = append(hedges, r.Header.Get(HedgeHeader))
+		mu.Unlock()
+		if first {
+			<-r.Context().Done() // Stalls until the hedge wins
+			close(cancelled)
+			return
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithHedging(0.95, 20*time.Millisecond))
+	start := time.Now()
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success {
+		t.Fatalf("Expected the hedge to succeed, got %v", resp.Error)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the hedge to cut latency, took %v", elapsed)
+	}
+	if len(resp.Attempts) != 1 || !resp.Attempts[0].Hedged {
+		t.Fatalf("Expected one hedged attempt, got %+v", resp.Attempts)
+	}
+	if a := resp.Attempts[0]; !a.HedgeWon || a.HedgeTimings.TTFB == 0 || a.HedgeTimings.Total == 0 {
+		t.Errorf("Expected the winning copy's own timings, got %+v", a)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the slow copy to be cancelled")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ids) != 2 || ids[0] != ids[1] || ids[0] != resp.MessageID {
+		t.Errorf("Expected both copies to carry message ID %s, got %v", resp.MessageID, ids)
+	}
+	if hedges[0] != "" || hedges[1] != "1" {
+		t.Errorf("Expected only the copy to carry %s, got %q", HedgeHeader, hedges)
+	}
+}
+
+func TestClient_Hedging_FastAndFailedAttempts(t *testing.T) {
+	ok, okHits := countingServer(t, http.StatusOK)
+	client, _ := NewClient(ok.URL, testSecret, WithHedging(0.95, 200*time.Millisecond))
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success || resp.Attempts[0].Hedged || *okHits != 1 {
+		t.Errorf("Expected a fast response not to be hedged, got %+v with %d requests", resp.Attempts, *okHits)
+	}
+
+	failing, failHits := countingServer(t, http.StatusServiceUnavailable)
+	client, _ = NewClient(failing.URL, testSecret, WithMaxRetries(1), WithHedging(0.95, 200*time.Millisecond))
+	resp = client.Send(context.Background(), "order.created", nil)
+	if resp.Success || resp.StatusCode != http.StatusServiceUnavailable || *failHits != 1 {
+		t.Errorf("Expected a quick failure to stay an ordinary attempt, got status %d with %d requests", resp.StatusCode, *failHits)
+	}
+}
+
+func TestClient_Hedging_BothCopiesFail(t *testing.T) {
+	var mu sync.Mutex
+	n := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n++
+		first := n == 1
+		mu.Unlock()
+		if first {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(1), WithHedging(0.95, 20*time.Millisecond))
+	resp := client.Send(context.Background(), "order.created", nil)
+	if resp.Success || resp.StatusCode != http.StatusServiceUnavailable || !resp.Attempts[0].Hedged || !resp.Attempts[0].HedgeWon {
+		t.Errorf("Expected the first failure (the hedge's 503) to be reported, got %d %+v", resp.StatusCode, resp.Attempts)
+	}
+}
+
+func TestLatencyWindow_Percentile(t *testing.T) {
+	var w latencyWindow
+	if _, ok := w.percentile(0.95); ok {
+		t.Error("Expected no percentile without samples")
+	}
+	for i := 1; i <= 200; i++ {
+		w.observe(time.Duration(i) * time.Millisecond)
+	}
+	// Only the latest 128 (73ms-200ms) are kept
+	if p, ok := w.percentile(0.5); !ok || p != 137*time.Millisecond {
+		t.Errorf("Expected a 137ms median, got %v", p)
+	}
+	if p, _ := w.percentile(0.99); p != 199*time.Millisecond {
+		t.Errorf("Expected a 199ms p99, got %v", p)
+	}
+}
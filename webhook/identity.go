@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"net/http"
+	"strings"
+)
+
+// SenderHeader identifies who operates the sender and whom to contact
+const SenderHeader = "Webhook-Sender"
+
+// SenderIdentity describes the sender to receivers that firewall, debug or
+// escalate based on request metadata
+type SenderIdentity struct {
+	Name    string // Organization or product name, e.g. "Acme Payments"
+	URL     string // Portal or documentation URL
+	Contact string // Contact address, e.g. "mailto:webhooks@acme.example"
+}
+
+// String renders the Webhook-Sender value: "{name}; url={url}; contact={contact}",
+// omitting empty parts
+func (s SenderIdentity) String() string {
+	parts := []string{}
+	if s.Name != "" {
+		parts = append(parts, s.Name)
+	}
+	if s.URL != "" {
+		parts = append(parts, "url="+s.URL)
+	}
+	if s.Contact != "" {
+		parts = append(parts, "contact="+s.Contact)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// WithUserAgent sets the User-Agent of every request the client sends
+func WithUserAgent(ua string) Option {
+	return func(c *Config) {
+		c.UserAgent = ua
+	}
+}
+
+// WithSenderIdentity adds a Webhook-Sender header naming the sender and how to reach it
+func WithSenderIdentity(id SenderIdentity) Option {
+	return func(c *Config) {
+		c.Sender = id
+	}
+}
+
+// setIdentity applies the configured User-Agent and Webhook-Sender headers
+func (c *Client) setIdentity(h http.Header) {
+	if c.config.UserAgent != "" {
+		h.Set("User-Agent", c.config.UserAgent)
+	}
+	if id := c.config.Sender.String(); id != "" {
+		h.Set(SenderHeader, id)
+	}
+}
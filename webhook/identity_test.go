@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSenderIdentity_String(t *testing.T) {
+	tests := []struct {
+		id   SenderIdentity
+		want string
+	}{
+		{SenderIdentity{}, ""},
+		{SenderIdentity{Name: "Acme Payments"}, "Acme Payments"},
+		{SenderIdentity{Name: "Acme Payments", URL: "https://portal.acme.example", Contact: "mailto:hooks@acme.example"},
+			"Acme Payments; url=https://portal.acme.example; contact=mailto:hooks@acme.example"},
+		{SenderIdentity{Contact: "mailto:hooks@acme.example"}, "contact=mailto:hooks@acme.example"},
+	}
+	for _, tt := range tests {
+		if got := tt.id.String(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestClient_Identity(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret,
+		WithUserAgent("AcmeWebhooks/2.1"),
+		WithSenderIdentity(SenderIdentity{Name: "Acme", URL: "https://portal.acme.example"}),
+	)
+	client.WarmUp(context.Background())
+	client.Send(context.Background(), "order.created", nil)
+
+	if len(headers) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(headers))
+	}
+	for _, h := range headers {
+		if got := h.Get("User-Agent"); got != "AcmeWebhooks/2.1" {
+			t.Errorf("Expected custom User-Agent, got %q", got)
+		}
+		if got := h.Get(SenderHeader); got != "Acme; url=https://portal.acme.example" {
+			t.Errorf("Expected sender identity, got %q", got)
+		}
+	}
+
+	// Without configuration neither header is overridden
+	plain, _ := NewClient(server.URL, testSecret)
+	plain.Send(context.Background(), "order.created", nil)
+	if h := headers[2]; h.Get(SenderHeader) != "" || h.Get("User-Agent") != "Go-http-client/1.1" {
+		t.Errorf("Expected default headers, got %v", h)
+	}
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrDeliveryDisabled marks attempts stopped by DisableDelivery
+	ErrDeliveryDisabled = errors.New("webhook: delivery disabled")
+	// ErrHoldFull is returned for deliveries stopped by Pause or DisableDelivery
+	// once MaxHeld are already waiting; callers should keep the event and retry
+	// later
+	ErrHoldFull = errors.New("webhook: too many deliveries held")
+)
+
+// killSwitch holds deliveries stopped by DisableDelivery
+type killSwitch struct {
+	mu      sync.Mutex
+	all     bool
+	targets map[string]bool
+	held    []delivery
+}
+
+// WithMaxHeld caps how many deliveries Pause parks and, separately, how many
+// DisableDelivery holds; 0 removes the cap
+func WithMaxHeld(n int) Option {
+	return func(c *Config) {
+		c.MaxHeld = n
+	}
+}
+
+// DisableDelivery is the emergency stop for incident response. With no
+// targets it halts delivery everywhere, otherwise only to the given URLs.
+// Unlike Pause it also stops deliveries already retrying: their next attempt
+// is held instead of sent. Sends keep being accepted and held with Parked set
+// on their Response until EnableDelivery, up to MaxHeld; beyond that they fail
+// with ErrHoldFull. Held deliveries live in memory only and are lost if the
+// process exits, so callers that must not lose events should keep their own
+// copy until a send is delivered. Attempts already on the wire complete.
+func (c *Client) DisableDelivery(targets ...string) {
+	k := &c.kill
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(targets) == 0 {
+		k.all = true
+	}
+	for _, t := range targets {
+		if k.targets == nil {
+			k.targets = make(map[string]bool)
+		}
+		k.targets[t] = true
+	}
+	c.logger.Warn("webhook: delivery disabled", "all", k.all, "targets", slices.Sorted(maps.Keys(k.targets)))
+}
+
+// EnableDelivery lifts the global stop, or with targets the stop on those
+// URLs, and flushes the held deliveries like Resume. Deliveries still bound
+// for a disabled target are held again.
+func (c *Client) EnableDelivery(targets ...string) <-chan Response {
+	k := &c.kill
+	k.mu.Lock()
+	if len(targets) == 0 {
+		k.all = false
+	}
+	for _, t := range targets {
+		delete(k.targets, t)
+	}
+	backlog := k.held
+	k.held = nil
+	c.logger.Info("webhook: delivery enabled", "all", !k.all, "disabled_targets", slices.Sorted(maps.Keys(k.targets)))
+	k.mu.Unlock()
+
+	results := make(chan Response, len(backlog))
+	go c.flush(backlog, results)
+	return results
+}
+
+// DeliveryDisabled reports whether the global stop is on and which targets are
+// disabled individually
+func (c *Client) DeliveryDisabled() (all bool, targets []string) {
+	c.kill.mu.Lock()
+	defer c.kill.mu.Unlock()
+	return c.kill.all, slices.Sorted(maps.Keys(c.kill.targets))
+}
+
+// Held returns the number of deliveries waiting for EnableDelivery
+func (c *Client) Held() int {
+	c.kill.mu.Lock()
+	defer c.kill.mu.Unlock()
+	return len(c.kill.held)
+}
+
+// halted reports whether d must be held before its first attempt: under the
+// global stop, or when it is pinned to a disabled target
+func (c *Client) halted(d delivery) bool {
+	c.kill.mu.Lock()
+	defer c.kill.mu.Unlock()
+	return c.kill.all || (d.pinned && c.kill.targets[d.target])
+}
+
+// disabled reports whether an attempt to target must be held
+func (c *Client) disabled(target string) bool {
+	if target == "" {
+		target = c.config.TargetURL
+	}
+	c.kill.mu.Lock()
+	defer c.kill.mu.Unlock()
+	return c.kill.all || c.kill.targets[target]
+}
+
+// hold keeps d for EnableDelivery and reports it as parked, or fails it with
+// ErrHoldFull when MaxHeld deliveries are already held
+func (c *Client) hold(d delivery, attempts []Attempt) Response {
+	c.kill.mu.Lock()
+	full := c.holdFull(len(c.kill.held))
+	if !full {
+		c.kill.held = append(c.kill.held, d)
+	}
+	c.kill.mu.Unlock()
+
+	if full {
+		return c.refuseHold(d, attempts)
+	}
+	c.emitOutcome(StageParked, d, attempts)
+	return Response{Parked: true, State: StateQueued, MessageID: d.msgID, Attempts: attempts, BodySize: len(d.body)}
+}
+
+// holdFull reports whether n waiting deliveries reach MaxHeld
+func (c *Client) holdFull(n int) bool {
+	return c.config.MaxHeld > 0 && n >= c.config.MaxHeld
+}
+
+// refuseHold fails d with ErrHoldFull instead of parking or holding it
+func (c *Client) refuseHold(d delivery, attempts []Attempt) Response {
+	err := fmt.Errorf("%w: limit %d", ErrHoldFull, c.config.MaxHeld)
+	c.logger.Error("webhook: delivery dropped, hold is full", "msgId", d.msgID, "held", c.config.MaxHeld)
+	c.emitOutcome(StageFailed, d, attempts)
+	return Response{Error: err, State: StateFailed, MessageID: d.msgID, Attempts: attempts, BodySize: len(d.body)}
+}
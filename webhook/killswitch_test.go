@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_DisableDelivery(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret)
+
+	client.DisableDelivery()
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Parked || resp.State != StateQueued || resp.MessageID == "" {
+		t.Fatalf("Expected the send to be held, got %+v", resp)
+	}
+	if *hits != 0 || client.Held() != 1 {
+		t.Fatalf("Expected nothing sent and one held, got %d sent and %d held", *hits, client.Held())
+	}
+	if all, _ := client.DeliveryDisabled(); !all {
+		t.Error("Expected the global stop to be reported")
+	}
+
+	var results []Response
+	for r := range client.EnableDelivery() {
+		results = append(results, r)
+	}
+	if len(results) != 1 || !results[0].Success || results[0].MessageID != resp.MessageID || *hits != 1 {
+		t.Errorf("Expected the held send delivered on enable, got %+v with %d hits", results, *hits)
+	}
+	if client.Held() != 0 {
+		t.Errorf("Expected nothing held, got %d", client.Held())
+	}
+}
+
+func TestClient_DisableDelivery_HaltsRetries(t *testing.T) {
+	var calls atomic.Int32
+	var attempts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts = append(attempts, r.Header.Get(AttemptHeader))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	det, _ := testDeterminism()
+	var client *Client
+	client, _ = NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(3),
+		WithAttemptObserver(func(Attempt) { client.DisableDelivery() }))
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Parked || len(resp.Attempts) != 1 || calls.Load() != 1 {
+		t.Fatalf("Expected the retry to be held after one attempt, got %+v with %d calls", resp, calls.Load())
+	}
+	if state, _ := client.State(resp.MessageID); state != StateQueued {
+		t.Errorf("Expected the message queued, got %s", state)
+	}
+
+	client.config.OnAttempt = nil
+	result := <-client.EnableDelivery()
+	if !result.Success || !slices.Equal(attempts, []string{"1", "2"}) {
+		t.Errorf("Expected the held retry to resume as attempt 2, got %+v with attempts %v", result, attempts)
+	}
+}
+
+func TestClient_DisableDelivery_PerTarget(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret)
+
+	client.DisableDelivery("https://other.example.com/hook")
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Success {
+		t.Fatalf("Expected other targets to keep receiving, got %v", resp.Error)
+	}
+
+	client.DisableDelivery(server.URL)
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Parked {
+		t.Errorf("Expected the disabled target's send to be held, got %+v", resp)
+	}
+	if all, targets := client.DeliveryDisabled(); all || !slices.Equal(targets, []string{server.URL, "https://other.example.com/hook"}) {
+		t.Errorf("Unexpected disabled state %v %v", all, targets)
+	}
+
+	<-client.EnableDelivery(server.URL)
+	if *hits != 2 || client.Held() != 0 {
+		t.Errorf("Expected the held send delivered once its target is enabled, got %d hits", *hits)
+	}
+}
+
+func TestClient_DisableDelivery_MaxHeld(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret, WithMaxHeld(2))
+
+	client.DisableDelivery()
+	for range 2 {
+		if resp := client.Send(context.Background(), "order.created", nil); !resp.Parked {
+			t.Fatalf("Expected the send to be held, got %+v", resp)
+		}
+	}
+	resp := client.Send(context.Background(), "order.created", nil)
+	if resp.Parked || !errors.Is(resp.Error, ErrHoldFull) || resp.State != StateFailed {
+		t.Fatalf("Expected ErrHoldFull once the hold is full, got %+v", resp)
+	}
+	if state, ok := client.State(resp.MessageID); !ok || state != StateFailed {
+		t.Errorf("Expected the refused message failed, got %s, %v", state, ok)
+	}
+	if client.Held() != 2 {
+		t.Errorf("Expected two held, got %d", client.Held())
+	}
+
+	var delivered int
+	for r := range client.EnableDelivery() {
+		if r.Success {
+			delivered++
+		}
+	}
+	if delivered != 2 || *hits != 2 {
+		t.Errorf("Expected the two held sends delivered, got %d with %d hits", delivered, *hits)
+	}
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// DeliveryStage names a point in a delivery's lifecycle
+type DeliveryStage string
+
+const (
+	StageSent     DeliveryStage = "sent"     // Accepted with a 2xx
+	StageRetried  DeliveryStage = "retried"  // An attempt failed and another is scheduled
+	StageFailed   DeliveryStage = "failed"   // Rejected or out of attempts; terminal
+	StageDeferred DeliveryStage = "deferred" // Remaining attempts handed to the deadline overflow handler
+	StageParked   DeliveryStage = "parked"   // Held by Pause
+)
+
+// DeliveryEvent reports one lifecycle transition of a delivery
+type DeliveryEvent struct {
+	Stage      DeliveryStage
+	MessageID  string
+	Event      string        // Payload event name
+	Target     string        // URL of the latest attempt
+	Attempt    int           // Attempts made so far
+	StatusCode int           // Status of the latest attempt, zero without a response
+	Error      error         // Error of the latest attempt
+	Delay      time.Duration // For StageRetried, the wait before the next attempt
+	Time       time.Time
+	BodySize   int // Signed body bytes
+}
+
+// deliveryObservers holds the callbacks registered with ObserveDeliveries
+type deliveryObservers struct {
+	mu   sync.RWMutex
+	next int
+	fns  map[int]func(DeliveryEvent)
+}
+
+// ObserveDeliveries calls fn synchronously for every lifecycle transition of
+// every delivery until stop is called. fn must not block.
+func (c *Client) ObserveDeliveries(fn func(DeliveryEvent)) (stop func()) {
+	o := &c.observers
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.fns == nil {
+		o.fns = make(map[int]func(DeliveryEvent))
+	}
+	id := o.next
+	o.next++
+	o.fns[id] = fn
+
+	return func() {
+		o.mu.Lock()
+		delete(o.fns, id)
+		o.mu.Unlock()
+	}
+}
+
+// emit reports e to every observer
+func (c *Client) emit(e DeliveryEvent) {
+	o := &c.observers
+	o.mu.RLock()
+	fns := make([]func(DeliveryEvent), 0, len(o.fns))
+	for _, fn := range o.fns {
+		fns = append(fns, fn)
+	}
+	o.mu.RUnlock()
+
+	e.Time = c.det.Now()
+	c.states.advance(Transition{MessageID: e.MessageID, Event: e.Event, To: stageStates[e.Stage], Attempt: e.Attempt, Time: e.Time})
+	c.waiters.settle(e)
+	for _, fn := range fns {
+		fn(e)
+	}
+}
+
+// emitOutcome reports a stage of a delivery, described by its latest attempt
+func (c *Client) emitOutcome(stage DeliveryStage, d delivery, attempts []Attempt) {
+	c.emit(lifecycleEvent(stage, d, attempts))
+}
+
+// emitRetry reports a failed attempt that will be retried after delay
+func (c *Client) emitRetry(d delivery, attempts []Attempt, delay time.Duration) {
+	e := lifecycleEvent(StageRetried, d, attempts)
+	e.Delay = delay
+	c.emit(e)
+}
+
+func lifecycleEvent(stage DeliveryStage, d delivery, attempts []Attempt) DeliveryEvent {
+	e := DeliveryEvent{Stage: stage, MessageID: d.msgID, Event: d.event, Target: d.target, BodySize: len(d.body)}
+	if n := len(attempts); n > 0 {
+		last := attempts[n-1]
+		e.Attempt, e.Target, e.StatusCode, e.Error = last.Number, last.Target, last.StatusCode, last.Error
+	}
+	return e
+}
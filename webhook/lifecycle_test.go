@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestClient_ObserveDeliveries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det))
+
+	var events []DeliveryEvent
+	stop := client.ObserveDeliveries(func(e DeliveryEvent) { events = append(events, e) })
+	resp := client.Send(context.Background(), "order.created", nil)
+
+	var stages []DeliveryStage
+	for _, e := range events {
+		stages = append(stages, e.Stage)
+	}
+	if !slices.Equal(stages, []DeliveryStage{StageRetried, StageSent}) {
+		t.Fatalf("Expected retried then sent, got %v", stages)
+	}
+	if e := events[0]; e.MessageID != resp.MessageID || e.Event != "order.created" || e.StatusCode != 503 || e.Attempt != 1 || e.Delay <= 0 {
+		t.Errorf("Unexpected retry event: %+v", e)
+	}
+	if e := events[1]; e.StatusCode != 200 || e.Attempt != 2 || e.Target != server.URL {
+		t.Errorf("Unexpected sent event: %+v", e)
+	}
+
+	stop()
+	client.Send(context.Background(), "order.created", nil)
+	if len(events) != 2 {
+		t.Errorf("Expected no events after stop, got %d", len(events))
+	}
+}
+
+func TestClient_ObserveDeliveries_Failed(t *testing.T) {
+	server, _ := countingServer(t, http.StatusBadRequest)
+	client, _ := NewClient(server.URL, testSecret)
+
+	var stages []DeliveryStage
+	client.ObserveDeliveries(func(e DeliveryEvent) { stages = append(stages, e.Stage) })
+	client.Send(context.Background(), "order.created", nil)
+	if !slices.Equal(stages, []DeliveryStage{StageFailed}) {
+		t.Errorf("Expected a single failed event, got %v", stages)
+	}
+
+	client.Pause()
+	client.Send(context.Background(), "order.created", nil)
+	if stages[len(stages)-1] != StageParked {
+		t.Errorf("Expected a parked event, got %v", stages)
+	}
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// migrationProbeEvent names the deliveries sent by ProbeHeaderMigration
+const migrationProbeEvent = ReservedPrefix + "header_probe"
+
+// WithLegacyHeaders also emits the message ID, timestamp and signature under
+// legacy names (e.g. signing.SvixHeaders after switching to Standard Webhooks)
+// until the given time, or indefinitely when it is zero. Both sets carry the
+// same signatures, so receivers keep verifying while they migrate.
+func WithLegacyHeaders(legacy signing.HeaderNames, until time.Time) Option {
+	return func(c *Config) {
+		c.LegacyHeaders = legacy
+		c.LegacyUntil = until
+	}
+}
+
+// HeaderCounts counts probe deliveries accepted and rejected under one header set
+type HeaderCounts struct {
+	Accepted uint64
+	Rejected uint64
+}
+
+// MigrationStats reports which header set the receiver validates, from the
+// outcomes of ProbeHeaderMigration
+type MigrationStats struct {
+	Current HeaderCounts // Config.Headers alone
+	Legacy  HeaderCounts // Config.LegacyHeaders alone
+}
+
+// LegacyRequired reports whether the receiver has only ever accepted the
+// legacy set, so dual emission cannot be dropped yet
+func (s MigrationStats) LegacyRequired() bool {
+	return s.Current.Accepted == 0 && s.Legacy.Accepted > 0
+}
+
+type migrationCounters struct {
+	current, legacy [2]atomic.Uint64 // accepted, rejected
+}
+
+func (m *migrationCounters) record(legacy, accepted bool) {
+	set := &m.current
+	if legacy {
+		set = &m.legacy
+	}
+	if accepted {
+		set[0].Add(1)
+	} else {
+		set[1].Add(1)
+	}
+}
+
+// validLegacyHeaders checks that the legacy set is complete and distinct from the current one
+func validLegacyHeaders(cfg Config) error {
+	if cfg.LegacyHeaders == (signing.HeaderNames{}) {
+		return nil
+	}
+	if cfg.QueryTokenTTL > 0 {
+		return fmt.Errorf("webhook: legacy headers cannot be combined with query tokens")
+	}
+	if err := cfg.LegacyHeaders.Validate(); err != nil {
+		return fmt.Errorf("webhook: legacy headers: %w", err)
+	}
+	for _, pair := range [][2]string{
+		{cfg.Headers.ID, cfg.LegacyHeaders.ID},
+		{cfg.Headers.Timestamp, cfg.LegacyHeaders.Timestamp},
+		{cfg.Headers.Signature, cfg.LegacyHeaders.Signature},
+	} {
+		if strings.EqualFold(pair[0], pair[1]) {
+			return fmt.Errorf("webhook: legacy header %q is also a current header", pair[1])
+		}
+	}
+	return nil
+}
+
+// dualHeaders reports whether the legacy set is still being emitted
+func (c *Client) dualHeaders() bool {
+	if c.config.LegacyHeaders == (signing.HeaderNames{}) {
+		return false
+	}
+	return c.config.LegacyUntil.IsZero() || c.det.Now().Before(c.config.LegacyUntil)
+}
+
+// setSignatureHeaders writes the delivery's ID, timestamp and signature under names
+func setSignatureHeaders(h http.Header, names signing.HeaderNames, d delivery) {
+	h.Set(names.ID, d.msgID)
+	h.Set(names.Timestamp, fmt.Sprintf("%d", d.timestamp.Unix()))
+	h.Set(names.Signature, d.signature)
+}
+
+// MigrationStats returns the cumulative header probe outcomes
+func (c *Client) MigrationStats() MigrationStats {
+	m := &c.migration
+	return MigrationStats{
+		Current: HeaderCounts{Accepted: m.current[0].Load(), Rejected: m.current[1].Load()},
+		Legacy:  HeaderCounts{Accepted: m.legacy[0].Load(), Rejected: m.legacy[1].Load()},
+	}
+}
+
+// ProbeHeaderMigration sends one hookshot.header_probe delivery under the
+// current header set alone and one under the legacy set alone, records whether
+// the receiver accepted each with a 2xx, and returns the cumulative stats.
+// Probing periodically during the migration window shows when the legacy set
+// is no longer validated and can be dropped.
+func (c *Client) ProbeHeaderMigration(ctx context.Context) (MigrationStats, error) {
+	if c.config.LegacyHeaders == (signing.HeaderNames{}) {
+		return MigrationStats{}, fmt.Errorf("webhook: no legacy headers configured")
+	}
+	for _, legacy := range []bool{false, true} {
+		names := c.config.Headers
+		if legacy {
+			names = c.config.LegacyHeaders
+		}
+		status, err := c.probeHeaders(ctx, names)
+		if err != nil {
+			return c.MigrationStats(), fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+		}
+		c.migration.record(legacy, status >= 200 && status < 300)
+	}
+
+	stats := c.MigrationStats()
+	c.logger.Info("webhook: header migration probe", "target", c.config.TargetURL,
+		"current_accepted", stats.Current.Accepted, "legacy_accepted", stats.Legacy.Accepted)
+	return stats, nil
+}
+
+func (c *Client) probeHeaders(ctx context.Context, names signing.HeaderNames) (int, error) {
+	body, err := json.Marshal(Payload{Event: migrationProbeEvent, Timestamp: c.det.Now(), Data: map[string]string{"signature_header": names.Signature}})
+	if err != nil {
+		return 0, err
+	}
+	d := delivery{body: body, msgID: c.det.NewID(), header: http.Header{}}
+	c.seal(&d)
+	req, err := c.newRequest(ctx, d, 1)
+	if err != nil {
+		return 0, err
+	}
+	for _, set := range []signing.HeaderNames{c.config.Headers, c.config.LegacyHeaders} {
+		req.Header.Del(set.ID)
+		req.Header.Del(set.Timestamp)
+		req.Header.Del(set.Signature)
+	}
+	setSignatureHeaders(req.Header, names, d)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
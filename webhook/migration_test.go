@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// headerSetServer accepts deliveries only when names.Signature is present
+func headerSetServer(t *testing.T, names signing.HeaderNames, headers *[]http.Header) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*headers = append(*headers, r.Header.Clone())
+		if r.Header.Get(names.Signature) == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_LegacyHeaders(t *testing.T) {
+	var headers []http.Header
+	server := headerSetServer(t, signing.SvixHeaders, &headers)
+
+	client, err := NewClient(server.URL, testSecret, WithStandardWebhooks(), WithLegacyHeaders(signing.SvixHeaders, time.Time{}))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	h := headers[0]
+	if h.Get("webhook-signature") == "" || h.Get("webhook-signature") != h.Get("svix-signature") {
+		t.Errorf("Expected identical signatures under both sets, got %q and %q", h.Get("webhook-signature"), h.Get("svix-signature"))
+	}
+	if h.Get("webhook-id") != h.Get("svix-id") || h.Get("webhook-timestamp") != h.Get("svix-timestamp") {
+		t.Error("Expected matching ID and timestamp under both sets")
+	}
+}
+
+func TestClient_LegacyHeaders_Expire(t *testing.T) {
+	det, _ := testDeterminism()
+	var headers []http.Header
+	server := headerSetServer(t, signing.StandardHeaders, &headers)
+
+	client, _ := NewClient(server.URL, testSecret, WithStandardWebhooks(), WithDeterminism(det),
+		WithLegacyHeaders(signing.SvixHeaders, det.Now()))
+	client.Send(context.Background(), "order.created", nil)
+	if headers[0].Get("svix-signature") != "" {
+		t.Error("Expected no legacy headers after the migration window")
+	}
+}
+
+func TestClient_LegacyHeaders_Invalid(t *testing.T) {
+	if _, err := NewClient("http://a", testSecret, WithLegacyHeaders(signing.SvixHeaders, time.Time{})); err == nil {
+		t.Error("Expected error for legacy headers equal to the current set")
+	}
+	if _, err := NewClient("http://a", testSecret, WithLegacyHeaders(signing.HeaderNames{ID: "x-id"}, time.Time{})); err == nil {
+		t.Error("Expected error for an incomplete legacy set")
+	}
+}
+
+func TestClient_ProbeHeaderMigration(t *testing.T) {
+	var headers []http.Header
+	server := headerSetServer(t, signing.SvixHeaders, &headers)
+
+	client, _ := NewClient(server.URL, testSecret, WithStandardWebhooks(), WithLegacyHeaders(signing.SvixHeaders, time.Time{}))
+	stats, err := client.ProbeHeaderMigration(context.Background())
+	if err != nil {
+		t.Fatalf("ProbeHeaderMigration() error = %v", err)
+	}
+	want := MigrationStats{Current: HeaderCounts{Rejected: 1}, Legacy: HeaderCounts{Accepted: 1}}
+	if stats != want {
+		t.Errorf("Expected %+v, got %+v", want, stats)
+	}
+	if !stats.LegacyRequired() {
+		t.Error("Expected the legacy set to be required")
+	}
+	if headers[0].Get("svix-signature") != "" || headers[1].Get("webhook-signature") != "" {
+		t.Error("Expected each probe to carry a single header set")
+	}
+
+	plain, _ := NewClient(server.URL, testSecret)
+	if _, err := plain.ProbeHeaderMigration(context.Background()); err == nil {
+		t.Error("Expected error without legacy headers")
+	}
+}
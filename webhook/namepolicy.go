@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ReservedPrefix is reserved for events emitted by Hookshot itself
+const ReservedPrefix = "hookshot."
+
+// NamePolicy decides whether an event name may be sent; it returns a
+// descriptive error for rejected names
+type NamePolicy func(name string) error
+
+// WithNamePolicies adds event-name policies; every policy must accept a name
+// before it is sent. The ReservedPrefix check always applies.
+func WithNamePolicies(policies ...NamePolicy) Option {
+	return func(c *Config) {
+		c.NamePolicies = append(c.NamePolicies, policies...)
+	}
+}
+
+// MatchPattern accepts names matching re
+func MatchPattern(re *regexp.Regexp) NamePolicy {
+	return func(name string) error {
+		if !re.MatchString(name) {
+			return fmt.Errorf("name %q does not match %s", name, re)
+		}
+		return nil
+	}
+}
+
+// AllowNamespaces accepts names whose first segment is one of namespaces
+func AllowNamespaces(namespaces ...string) NamePolicy {
+	allowed := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return func(name string) error {
+		ns, _, _ := strings.Cut(name, ".")
+		if !allowed[ns] {
+			return fmt.Errorf("namespace %q of %q is not registered", ns, name)
+		}
+		return nil
+	}
+}
+
+// AllowNames accepts only the listed event names
+func AllowNames(names ...string) NamePolicy {
+	allowed := make(map[string]bool, len(names))
+	for _, n := range names {
+		allowed[n] = true
+	}
+	return func(name string) error {
+		if !allowed[name] {
+			return fmt.Errorf("event %q is not registered", name)
+		}
+		return nil
+	}
+}
+
+// RejectPrefixes rejects names starting with any of prefixes
+func RejectPrefixes(prefixes ...string) NamePolicy {
+	return func(name string) error {
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p) {
+				return fmt.Errorf("prefix %q is reserved", p)
+			}
+		}
+		return nil
+	}
+}
+
+// checkName runs the reserved-prefix check and every configured policy
+func (c *Client) checkName(name string) error {
+	if err := RejectPrefixes(ReservedPrefix)(name); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	for _, p := range c.config.NamePolicies {
+		if err := p(name); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+	}
+	return nil
+}
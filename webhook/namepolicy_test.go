@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNamePolicies(t *testing.T) {
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithNamePolicies(
+		AllowNamespaces("order", "billing"),
+		RejectPrefixes("billing.internal."),
+		MatchPattern(regexp.MustCompile(`^[a-z.]+$`)),
+	))
+
+	tests := []struct {
+		name    string
+		event   string
+		wantErr bool
+	}{
+		{name: "registered namespace", event: "order.created", wantErr: false},
+		{name: "second namespace", event: "billing.invoice.paid", wantErr: false},
+		{name: "unregistered namespace", event: "user.signup", wantErr: true},
+		{name: "reserved system prefix", event: "hookshot.endpoint.disabled", wantErr: true},
+		{name: "custom reserved prefix", event: "billing.internal.sync", wantErr: true},
+		{name: "pattern mismatch", event: "order.created_v2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := client.Send(context.Background(), tt.event, nil)
+			if (resp.Error != nil) != tt.wantErr {
+				t.Errorf("Send(%q) error = %v, wantErr %v", tt.event, resp.Error, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(resp.Error, ErrInvalidEvent) {
+				t.Errorf("Expected error to wrap ErrInvalidEvent, got: %v", resp.Error)
+			}
+		})
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("Expected only accepted names to be delivered (2), got %d", got)
+	}
+}
+
+func TestNamePolicies_ReservedByDefault(t *testing.T) {
+	client, _ := NewClient("http://localhost:4000/webhook", testSecret)
+
+	resp := client.Send(context.Background(), "hookshot.secret.rotated", nil)
+	if !errors.Is(resp.Error, ErrInvalidEvent) {
+		t.Errorf("Expected reserved prefix to be rejected, got: %v", resp.Error)
+	}
+}
+
+func TestAllowNames(t *testing.T) {
+	policy := AllowNames("order.created")
+	if err := policy("order.created"); err != nil {
+		t.Errorf("Expected registered name to pass, got %v", err)
+	}
+	if err := policy("order.deleted"); err == nil {
+		t.Error("Expected unregistered name to be rejected")
+	}
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// EncodingRisk names a feature of a marshaled body that middleware commonly
+// re-encodes, changing the bytes after they were signed
+type EncodingRisk string
+
+const (
+	// RiskHTMLEscape: <, > or & written as \u003c, \u003e or \u0026, which
+	// re-encoding JSON stacks usually write raw
+	RiskHTMLEscape EncodingRisk = "html-escape"
+	// RiskNonNFC: strings that are not in Unicode Normalization Form C, which
+	// normalizing proxies and frameworks recompose
+	RiskNonNFC EncodingRisk = "non-nfc"
+)
+
+// htmlEscapes are the escapes encoding/json emits for HTML-sensitive runes
+var htmlEscapes = map[string]byte{"003c": '<', "003e": '>', "0026": '&'}
+
+// EncodingRisks reports which re-encoding risks a JSON body carries
+func EncodingRisks(body []byte) []EncodingRisk {
+	var html, nfc bool
+	walkStrings(body, func(lit []byte) []byte {
+		if !bytes.Equal(unescapeHTMLLiteral(lit), lit) {
+			html = true
+		}
+		var s string
+		if json.Unmarshal(lit, &s) == nil && !norm.NFC.IsNormalString(s) {
+			nfc = true
+		}
+		return lit
+	})
+
+	var risks []EncodingRisk
+	if html {
+		risks = append(risks, RiskHTMLEscape)
+	}
+	if nfc {
+		risks = append(risks, RiskNonNFC)
+	}
+	return risks
+}
+
+// UnescapeHTML rewrites the \u003c, \u003e and \u0026 escapes encoding/json
+// adds by default back to raw characters, leaving everything else untouched
+func UnescapeHTML(body []byte) ([]byte, error) {
+	return transformStrings(body, unescapeHTMLLiteral)
+}
+
+func unescapeHTMLLiteral(lit []byte) []byte {
+	out := make([]byte, 0, len(lit))
+	for i := 0; i < len(lit); i++ {
+		if lit[i] == '\\' && i+1 < len(lit) {
+			if lit[i+1] == 'u' && i+6 <= len(lit) {
+				if r, ok := htmlEscapes[strings.ToLower(string(lit[i+2:i+6]))]; ok {
+					out = append(out, r)
+					i += 5
+					continue
+				}
+			}
+			out = append(out, lit[i], lit[i+1])
+			i++
+			continue
+		}
+		out = append(out, lit[i])
+	}
+	return out
+}
+
+// NormalizeNFC rewrites every string, keys included, to Unicode Normalization
+// Form C. Strings already in NFC keep their original bytes.
+func NormalizeNFC(body []byte) ([]byte, error) {
+	var err error
+	out, terr := transformStrings(body, func(lit []byte) []byte {
+		var s string
+		if uerr := json.Unmarshal(lit, &s); uerr != nil {
+			err = uerr
+			return lit
+		}
+		if norm.NFC.IsNormalString(s) {
+			return lit
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.Encode(norm.NFC.String(s))
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	})
+	if terr != nil {
+		return nil, terr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("normalize nfc: %w", err)
+	}
+	return out, nil
+}
+
+// WithPortableJSON appends UnescapeHTML and NormalizeNFC, so signatures
+// survive proxies that re-encode escapes or normalize Unicode
+func WithPortableJSON() Option {
+	return WithBodyTransform(UnescapeHTML, NormalizeNFC)
+}
+
+// WithEncodingGuard logs a warning for every delivery whose final body still
+// carries an EncodingRisk, to find payloads that need WithPortableJSON
+func WithEncodingGuard() Option {
+	return func(c *Config) {
+		c.EncodingGuard = true
+	}
+}
+
+// transformStrings rewrites each string literal (quotes included) of a valid
+// JSON document with fn
+func transformStrings(body []byte, fn func(lit []byte) []byte) ([]byte, error) {
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("webhook: body is not valid JSON")
+	}
+	return walkStrings(body, fn), nil
+}
+
+// walkStrings passes every string literal in body to fn and splices in the result
+func walkStrings(body []byte, fn func(lit []byte) []byte) []byte {
+	out := make([]byte, 0, len(body))
+	for i := 0; i < len(body); i++ {
+		if body[i] != '"' {
+			out = append(out, body[i])
+			continue
+		}
+		j := i + 1
+		for j < len(body) && body[j] != '"' {
+			if body[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(body) {
+			return append(out, body[i:]...) // Unterminated; leave as is
+		}
+		out = append(out, fn(body[i:j+1])...)
+		i = j
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestUnescapeHTML(t *testing.T) {
+	body, _ := json.Marshal(map[string]string{"html": "<b>a & b</b>"})
+	got, err := UnescapeHTML(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"html":"<b>a & b</b>"}`; string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	// An escaped backslash followed by u003c is not an escape
+	literal := []byte(`{"a":"\\u003c"}`)
+	if got, _ := UnescapeHTML(literal); string(got) != string(literal) {
+		t.Errorf("Expected %s unchanged, got %s", literal, got)
+	}
+	if _, err := UnescapeHTML([]byte(`{"a":`)); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestNormalizeNFC(t *testing.T) {
+	// "é" as e + combining acute accent, in a key and a value
+	body := []byte(`{"caf` + "e\u0301" + `":"cafe` + "\u0301" + `","n":1}`)
+	got, err := NormalizeNFC(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"café":"café","n":1}`; string(got) != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	normal := []byte(`{"a":"\u003cplain"}`)
+	if got, _ := NormalizeNFC(normal); string(got) != string(normal) {
+		t.Errorf("Expected NFC strings to keep their bytes, got %s", got)
+	}
+}
+
+func TestEncodingRisks(t *testing.T) {
+	escaped, _ := json.Marshal(map[string]string{"a": "<", "b": "e\u0301"})
+	if got := EncodingRisks(escaped); !slices.Equal(got, []EncodingRisk{RiskHTMLEscape, RiskNonNFC}) {
+		t.Errorf("Expected both risks, got %v", got)
+	}
+	if got := EncodingRisks([]byte(`{"a":"plain"}`)); len(got) != 0 {
+		t.Errorf("Expected no risks, got %v", got)
+	}
+}
+
+func TestClient_PortableJSON(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithPortableJSON(), WithEncodingGuard())
+	client.Send(context.Background(), "order.created", map[string]string{"note": "a & b"})
+	if risks := EncodingRisks(body); len(risks) != 0 {
+		t.Errorf("Expected a portable body, got %v in %s", risks, body)
+	}
+}
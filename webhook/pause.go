@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pauseState holds deliveries parked while the Client is paused
+type pauseState struct {
+	mu     sync.Mutex
+	paused bool
+	parked []delivery
+}
+
+// WithResumeRate sets how many parked deliveries are flushed per second after Resume
+func WithResumeRate(perSecond int) Option {
+	return func(c *Config) {
+		c.ResumeRate = perSecond
+	}
+}
+
+// Pause parks new sends in memory instead of attempting delivery, e.g. during a
+// known receiver maintenance window. Parked sends return a Response with Parked
+// set and are signed afresh when flushed, so they do not age past the
+// receiver's timestamp tolerance. Beyond MaxHeld parked sends, further ones
+// fail with ErrHoldFull.
+func (c *Client) Pause() {
+	c.pause.mu.Lock()
+	c.pause.paused = true
+	c.pause.mu.Unlock()
+}
+
+// Paused reports whether the Client is paused
+func (c *Client) Paused() bool {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	return c.pause.paused
+}
+
+// Parked returns the number of deliveries waiting for Resume
+func (c *Client) Parked() int {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	return len(c.pause.parked)
+}
+
+// Resume stops parking new sends and flushes the parked ones in order in the
+// background, at most ResumeRate per second. The returned channel receives one
+// Response per flushed delivery and is closed once the backlog is drained; it
+// is buffered, so callers may ignore it. Pausing again mid-flush parks the
+// remainder.
+func (c *Client) Resume() <-chan Response {
+	c.pause.mu.Lock()
+	c.pause.paused = false
+	backlog := c.pause.parked
+	c.pause.parked = nil
+	c.pause.mu.Unlock()
+
+	results := make(chan Response, len(backlog))
+	go c.flush(backlog, results)
+	return results
+}
+
+// park queues d if the Client is paused, or fails it with ErrHoldFull when
+// MaxHeld deliveries are already parked. It reports whether d was taken.
+func (c *Client) park(d delivery) (Response, bool) {
+	c.pause.mu.Lock()
+	paused := c.pause.paused
+	full := paused && c.holdFull(len(c.pause.parked))
+	if paused && !full {
+		c.pause.parked = append(c.pause.parked, d)
+	}
+	c.pause.mu.Unlock()
+
+	switch {
+	case !paused:
+		return Response{}, false
+	case full:
+		return c.refuseHold(d, nil), true
+	}
+	c.emit(DeliveryEvent{Stage: StageParked, MessageID: d.msgID, Event: d.event, Target: d.target, BodySize: len(d.body)})
+	return Response{Parked: true, State: StateQueued, MessageID: d.msgID, BodySize: len(d.body)}, true
+}
+
+func (c *Client) flush(backlog []delivery, results chan<- Response) {
+	defer close(results)
+
+	var interval time.Duration
+	if c.config.ResumeRate > 0 {
+		interval = time.Second / time.Duration(c.config.ResumeRate)
+	}
+
+	for i, d := range backlog {
+		if i > 0 && interval > 0 {
+			<-c.det.Scheduler.After(interval)
+		}
+
+		c.pause.mu.Lock()
+		if c.pause.paused {
+			// Keep the remainder ahead of anything parked since
+			c.pause.parked = append(backlog[i:len(backlog):len(backlog)], c.pause.parked...)
+			c.pause.mu.Unlock()
+			return
+		}
+		c.pause.mu.Unlock()
+
+		c.seal(&d)
+		c.mirror(d)
+		resp := c.sendWithRetry(context.Background(), d)
+		if resp.Error != nil {
+			c.logger.Warn("webhook: parked delivery failed", "msgId", d.msgID, "error", resp.Error)
+		}
+		results <- resp
+	}
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_PauseResume(t *testing.T) {
+	var mu sync.Mutex
+	var events, timestamps []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		events = append(events, p.Event)
+		timestamps = append(timestamps, r.Header.Get("svix-timestamp"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	det, sched := testDeterminism()
+	now := time.Unix(1700000000, 0)
+	det.Now = func() time.Time { return now }
+
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithResumeRate(5))
+	client.Pause()
+	if !client.Paused() {
+		t.Fatal("Expected client to be paused")
+	}
+
+	for _, event := range []string{"order.created", "order.paid", "order.shipped"} {
+		resp := client.Send(context.Background(), event, nil)
+		if !resp.Parked || resp.Success || resp.Error != nil || resp.MessageID == "" {
+			t.Fatalf("Expected parked response, got %+v", resp)
+		}
+	}
+	if client.Parked() != 3 || len(events) != 0 {
+		t.Fatalf("Expected 3 parked and none delivered, got %d parked, %d delivered", client.Parked(), len(events))
+	}
+
+	// Flushed deliveries are re-signed with the resume-time timestamp
+	now = now.Add(time.Hour)
+	var flushed int
+	for resp := range client.Resume() {
+		if !resp.Success {
+			t.Errorf("Expected flushed delivery to succeed, got %v", resp.Error)
+		}
+		flushed++
+	}
+
+	if flushed != 3 || client.Parked() != 0 {
+		t.Errorf("Expected 3 flushed and none parked, got %d flushed, %d parked", flushed, client.Parked())
+	}
+	if want := []string{"order.created", "order.paid", "order.shipped"}; !slices.Equal(events, want) {
+		t.Errorf("Expected delivery order %v, got %v", want, events)
+	}
+	for _, ts := range timestamps {
+		if ts != strconv.FormatInt(now.Unix(), 10) {
+			t.Errorf("Expected timestamp %d, got %s", now.Unix(), ts)
+		}
+	}
+	if want := []time.Duration{200 * time.Millisecond, 200 * time.Millisecond}; !slices.Equal(sched.delays, want) {
+		t.Errorf("Expected catch-up delays %v, got %v", want, sched.delays)
+	}
+
+	if resp := client.Send(context.Background(), "order.delivered", nil); !resp.Success || resp.Parked {
+		t.Errorf("Expected direct delivery after resume, got %+v", resp)
+	}
+}
+
+// blockingScheduler hands each wait to the test
+type blockingScheduler struct{ waits chan chan time.Time }
+
+func (s blockingScheduler) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	s.waits <- ch
+	return ch
+}
+
+func TestClient_PauseDuringFlush(t *testing.T) {
+	var delivered int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sched := blockingScheduler{waits: make(chan chan time.Time)}
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(Determinism{Scheduler: sched}))
+	client.Pause()
+	for i := 0; i < 3; i++ {
+		client.Send(context.Background(), "order.created", nil)
+	}
+
+	results := client.Resume()
+	wait := <-sched.waits // first delivery sent, waiting before the second
+	client.Pause()
+	wait <- time.Time{}
+
+	var flushed int
+	for range results {
+		flushed++
+	}
+	if flushed != 1 || delivered != 1 {
+		t.Errorf("Expected 1 flushed before pausing, got %d flushed, %d delivered", flushed, delivered)
+	}
+	if client.Parked() != 2 {
+		t.Errorf("Expected the remainder to be parked again, got %d", client.Parked())
+	}
+}
+
+func TestClient_Pause_MaxHeld(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret, WithMaxHeld(1))
+
+	client.Pause()
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Parked {
+		t.Fatalf("Expected the send parked, got %+v", resp)
+	}
+	resp := client.Send(context.Background(), "order.created", nil)
+	if resp.Parked || !errors.Is(resp.Error, ErrHoldFull) || client.Parked() != 1 {
+		t.Fatalf("Expected ErrHoldFull with one parked, got %+v and %d parked", resp, client.Parked())
+	}
+	if state, _ := client.State(resp.MessageID); state != StateFailed {
+		t.Errorf("Expected the refused message failed, got %s", state)
+	}
+
+	for range client.Resume() {
+	}
+	if *hits != 1 {
+		t.Errorf("Expected only the parked send delivered, got %d hits", *hits)
+	}
+}
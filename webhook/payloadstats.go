@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"compress/gzip"
+	"maps"
+	"sync"
+	"time"
+)
+
+// PayloadStats are cumulative size and serialization counters for one event type
+type PayloadStats struct {
+	Count           uint64
+	Bytes           uint64        // Signed body bytes, after transforms
+	MaxBytes        int           // Largest single body
+	CompressedBytes uint64        // Bytes the bodies take gzipped at BestSpeed
+	Serialization   time.Duration // Time spent marshaling and transforming
+}
+
+// AverageBytes returns the mean body size
+func (s PayloadStats) AverageBytes() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Bytes) / float64(s.Count)
+}
+
+// CompressionRatio returns raw bytes per compressed byte; high ratios mark
+// verbose payloads that gain most from trimming or compression
+func (s PayloadStats) CompressionRatio() float64 {
+	if s.CompressedBytes == 0 {
+		return 0
+	}
+	return float64(s.Bytes) / float64(s.CompressedBytes)
+}
+
+// WithPayloadMetrics records PayloadStats per event type, read with
+// Client.PayloadStats. Measuring the compression ratio gzips every body, so
+// it is off by default.
+func WithPayloadMetrics() Option {
+	return func(c *Config) {
+		c.PayloadMetrics = true
+	}
+}
+
+// payloadMetrics holds PayloadStats keyed by event name
+type payloadMetrics struct {
+	mu      sync.Mutex
+	byEvent map[string]PayloadStats
+}
+
+func (m *payloadMetrics) record(event string, body []byte, serialization time.Duration) {
+	compressed := gzipSize(body)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.byEvent == nil {
+		m.byEvent = make(map[string]PayloadStats)
+	}
+	s := m.byEvent[event]
+	s.Count++
+	s.Bytes += uint64(len(body))
+	s.MaxBytes = max(s.MaxBytes, len(body))
+	s.CompressedBytes += uint64(compressed)
+	s.Serialization += serialization
+	m.byEvent[event] = s
+}
+
+// PayloadStats returns the payload counters per event type, empty unless
+// WithPayloadMetrics is set
+func (c *Client) PayloadStats() map[string]PayloadStats {
+	c.payloads.mu.Lock()
+	defer c.payloads.mu.Unlock()
+	return maps.Clone(c.payloads.byEvent)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct{ n int }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
+
+func gzipSize(body []byte) int {
+	var cw countingWriter
+	zw, _ := gzip.NewWriterLevel(&cw, gzip.BestSpeed)
+	zw.Write(body)
+	zw.Close()
+	return cw.n
+}
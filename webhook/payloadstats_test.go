@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_PayloadStats(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret, WithPayloadMetrics())
+
+	resp := client.Send(context.Background(), "report.generated", map[string]string{"csv": strings.Repeat("a,b,c\n", 500)})
+	client.Send(context.Background(), "report.generated", map[string]string{"csv": "a"})
+	client.Send(context.Background(), "order.created", map[string]string{"id": "1"})
+
+	stats := client.PayloadStats()
+	report := stats["report.generated"]
+	if report.Count != 2 || report.MaxBytes != resp.BodySize || report.Bytes <= uint64(resp.BodySize) {
+		t.Errorf("Unexpected report.generated stats %+v (first body %d bytes)", report, resp.BodySize)
+	}
+	if report.CompressionRatio() < 10 {
+		t.Errorf("Expected a high compression ratio for a repetitive payload, got %.1f", report.CompressionRatio())
+	}
+	if report.Serialization <= 0 {
+		t.Error("Expected serialization time to be recorded")
+	}
+	if stats["order.created"].Count != 1 {
+		t.Errorf("Expected one order.created, got %+v", stats["order.created"])
+	}
+
+	plain, _ := NewClient(server.URL, testSecret)
+	plain.Send(context.Background(), "order.created", nil)
+	if len(plain.PayloadStats()) != 0 {
+		t.Error("Expected no stats without WithPayloadMetrics")
+	}
+}
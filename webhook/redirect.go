@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// ErrRedirect is returned for a 3xx response the redirect policy did not follow
+var ErrRedirect = errors.New("webhook: redirect not followed")
+
+type redirectMode int
+
+const (
+	redirectNone redirectMode = iota
+	redirectSameHost
+	redirectFollow
+)
+
+// maxRedirects caps RedirectSameHost chains
+const maxRedirects = 10
+
+// RedirectPolicy controls whether deliveries follow 3xx responses. Every hop
+// must be http or https and, unless AllowPrivate is set, connect only to
+// public addresses. The address is checked when it is dialed, so a host cannot
+// pass the check and then rebind to a private address. Signature headers are
+// never sent to another origin.
+type RedirectPolicy struct {
+	mode         redirectMode
+	hops         int
+	AllowPrivate bool // Permit hops to loopback, private and link-local addresses
+	Retry        bool // Retry a 3xx left unfollowed with backoff instead of failing at once
+}
+
+var (
+	// RedirectNone treats every 3xx as a failed delivery (the default), unless
+	// its status is in SuccessStatuses
+	RedirectNone = RedirectPolicy{mode: redirectNone}
+	// RedirectSameHost follows up to 10 redirects that keep the scheme, host and port
+	RedirectSameHost = RedirectPolicy{mode: redirectSameHost, hops: maxRedirects}
+)
+
+// RedirectFollow follows up to n redirects to any permitted host; hops to
+// another origin arrive without signature headers and fail verification there
+func RedirectFollow(n int) RedirectPolicy {
+	return RedirectPolicy{mode: redirectFollow, hops: n}
+}
+
+// WithRedirectPolicy sets how 3xx responses are handled, also on a client
+// passed to WithHTTPClient (which is copied, not modified)
+func WithRedirectPolicy(p RedirectPolicy) Option {
+	return func(c *Config) {
+		c.RedirectPolicy = &p
+	}
+}
+
+// sameOrigin reports whether a and b share scheme, host and port
+func sameOrigin(a, b *http.Request) bool {
+	return strings.EqualFold(a.URL.Scheme, b.URL.Scheme) && strings.EqualFold(a.URL.Host, b.URL.Host)
+}
+
+// checkRedirect implements the policy as an http.Client CheckRedirect hook
+func (c *Client) checkRedirect(p RedirectPolicy) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		first := via[0]
+		if p.mode == redirectNone || len(via) > p.hops || (p.mode == redirectSameHost && !sameOrigin(req, first)) {
+			return http.ErrUseLastResponse
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("%w: scheme %q", ErrRedirect, req.URL.Scheme)
+		}
+		if !p.AllowPrivate {
+			if !c.dialGuard {
+				if err := checkPublicHost(req.Context(), req.URL.Hostname()); err != nil {
+					return fmt.Errorf("%w: %v", ErrRedirect, err)
+				}
+			}
+			// The hop's own dial enforces the policy on the address it connects to
+			*req = *req.WithContext(requirePublic(req.Context(), ErrRedirect))
+		}
+		if !sameOrigin(req, first) {
+			c.stripSignatures(req.Header)
+		}
+		return nil
+	}
+}
+
+// stripSignatures removes every header that authenticates the delivery
+func (c *Client) stripSignatures(h http.Header) {
+	for _, names := range []signing.HeaderNames{c.config.Headers, c.config.LegacyHeaders} {
+		if names != (signing.HeaderNames{}) {
+			h.Del(names.ID)
+			h.Del(names.Timestamp)
+			h.Del(names.Signature)
+		}
+	}
+	h.Del(signing.SignedHeadersHeader)
+}
+
+// publicOnlyKey marks a request context whose connections may only be dialed
+// to public addresses; its value is the sentinel error a refusal wraps
+type publicOnlyKey struct{}
+
+// requirePublic marks ctx so guarded dialers refuse non-public addresses
+func requirePublic(ctx context.Context, sentinel error) context.Context {
+	return context.WithValue(ctx, publicOnlyKey{}, sentinel)
+}
+
+// checkDialAddress is a net.Dialer ControlContext hook enforcing requirePublic.
+// It sees the resolved address actually being dialed, so it cannot be
+// bypassed by DNS answers that change between a check and the connection.
+func checkDialAddress(ctx context.Context, _, address string, _ syscall.RawConn) error {
+	sentinel, ok := ctx.Value(publicOnlyKey{}).(error)
+	if !ok {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: non-public address %s is not permitted", sentinel, host)
+	}
+	return nil
+}
+
+// guardedDialer returns the dialer of the default transport, with the SSRF hook
+func guardedDialer() *net.Dialer {
+	return &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, ControlContext: checkDialAddress}
+}
+
+// guardTransport returns a copy of rt whose dials honor requirePublic, and
+// whether that was possible. Transports with their own dial function are
+// checked against the address of the established connection instead; other
+// RoundTrippers cannot be guarded.
+func guardTransport(rt http.RoundTripper) (http.RoundTripper, bool) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok || t.Dial != nil || t.DialTLS != nil {
+		return rt, false
+	}
+	t = t.Clone()
+	if t.DialContext == nil {
+		t.DialContext = guardedDialer().DialContext
+	} else {
+		t.DialContext = checkConn(t.DialContext)
+	}
+	if t.DialTLSContext != nil {
+		t.DialTLSContext = checkConn(t.DialTLSContext)
+	}
+	return t, true
+}
+
+// checkConn wraps a dial function to apply checkDialAddress to the remote
+// address of the connection it returns
+func checkConn(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDialAddress(ctx, network, conn.RemoteAddr().String(), nil); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// checkPublicHost resolves a redirect hop up front, for HTTP clients whose
+// transport cannot be guarded at dial time
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs := []string{host}
+	if net.ParseIP(host) == nil {
+		var err error
+		if addrs, err = net.DefaultResolver.LookupHost(ctx, host); err != nil {
+			return err
+		}
+	}
+	for _, a := range addrs {
+		if ip := net.ParseIP(a); ip == nil || !isPublicIP(ip) {
+			return fmt.Errorf("%s: non-public address %s is not permitted", host, a)
+		}
+	}
+	return nil
+}
+
+// redirectError describes a 3xx response left unfollowed
+func redirectError(resp *http.Response) error {
+	return fmt.Errorf("%w: status %d to %q", ErrRedirect, resp.StatusCode, resp.Header.Get("Location"))
+}
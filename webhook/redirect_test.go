@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// redirectServer answers /old with a 307 to location and records /new requests
+func redirectServer(t *testing.T, location func(self string) string, headers *[]http.Header) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, location(server.URL), http.StatusTemporaryRedirect)
+			return
+		}
+		*headers = append(*headers, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_RedirectNone(t *testing.T) {
+	var headers []http.Header
+	server := redirectServer(t, func(self string) string { return self + "/new" }, &headers)
+
+	client, _ := NewClient(server.URL+"/old", testSecret)
+	resp := client.Send(context.Background(), "order.created", nil)
+	if resp.Success || !errors.Is(resp.Error, ErrRedirect) || resp.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("Expected an unfollowed redirect, got %v (status %d)", resp.Error, resp.StatusCode)
+	}
+	if len(resp.Attempts) != 1 || len(headers) != 0 {
+		t.Errorf("Expected one attempt and no follow, got %d attempts and %d follows", len(resp.Attempts), len(headers))
+	}
+}
+
+func TestClient_RedirectSameHost(t *testing.T) {
+	var headers []http.Header
+	server := redirectServer(t, func(self string) string { return self + "/new" }, &headers)
+	policy := RedirectSameHost
+	policy.AllowPrivate = true
+
+	client, _ := NewClient(server.URL+"/old", testSecret, WithRedirectPolicy(policy))
+	if resp := client.Send(context.Background(), "order.created", nil); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if headers[0].Get("svix-signature") == "" {
+		t.Error("Expected signature headers on a same-origin hop")
+	}
+
+	var otherHeaders []http.Header
+	other := redirectServer(t, nil, &otherHeaders)
+	cross := redirectServer(t, func(string) string { return other.URL + "/new" }, &headers)
+	client, _ = NewClient(cross.URL+"/old", testSecret, WithRedirectPolicy(policy))
+	if resp := client.Send(context.Background(), "order.created", nil); !errors.Is(resp.Error, ErrRedirect) || len(otherHeaders) != 0 {
+		t.Errorf("Expected the cross-host redirect left unfollowed, got %v", resp.Error)
+	}
+}
+
+func TestClient_RedirectFollow(t *testing.T) {
+	var otherHeaders, headers []http.Header
+	other := redirectServer(t, nil, &otherHeaders)
+	server := redirectServer(t, func(string) string { return other.URL + "/new" }, &headers)
+
+	policy := RedirectFollow(3)
+	policy.AllowPrivate = true
+	client, _ := NewClient(server.URL+"/old", testSecret, WithRedirectPolicy(policy), WithSignedHeaders())
+	client.Send(context.Background(), "order.created", nil)
+	if len(otherHeaders) != 1 {
+		t.Fatalf("Expected the redirect followed once, got %d", len(otherHeaders))
+	}
+	for _, name := range []string{"svix-id", "svix-timestamp", "svix-signature", "Webhook-Signed-Headers"} {
+		if otherHeaders[0].Get(name) != "" {
+			t.Errorf("Expected %s stripped on a cross-origin hop", name)
+		}
+	}
+
+	// Without AllowPrivate the loopback hop fails the SSRF check
+	client, _ = NewClient(server.URL+"/old", testSecret, WithRedirectPolicy(RedirectFollow(3)))
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !errors.Is(resp.Error, ErrRedirect) || len(resp.Attempts) != 1 || len(otherHeaders) != 1 {
+		t.Errorf("Expected a permanent SSRF rejection, got %v after %d attempts", resp.Error, len(resp.Attempts))
+	}
+}
+
+func TestWithRedirectPolicy_CopiesHTTPClient(t *testing.T) {
+	custom := &http.Client{}
+	client, _ := NewClient("http://a", testSecret, WithHTTPClient(custom), WithRedirectPolicy(RedirectSameHost))
+	if custom.CheckRedirect != nil || client.http == custom || client.http.CheckRedirect == nil {
+		t.Error("Expected the policy applied to a copy of the custom client")
+	}
+}
+
+func TestClient_RedirectChecksDialedAddress(t *testing.T) {
+	var headers []http.Header
+	target := redirectServer(t, nil, &headers)
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(target.URL, "http://"))
+
+	// rebind.test is only known to the client's resolver, which answers with
+	// loopback; the hop must be refused on the address actually dialed
+	cache := NewDNSCache(DNSCacheConfig{Lookup: func(context.Context, string) ([]string, time.Duration, error) {
+		return []string{"127.0.0.1"}, 0, nil
+	}})
+	server := redirectServer(t, func(string) string { return "http://rebind.test:" + port + "/new" }, &headers)
+
+	client, _ := NewClient(server.URL+"/old", testSecret, WithDNSCache(cache), WithRedirectPolicy(RedirectFollow(3)))
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !errors.Is(resp.Error, ErrRedirect) || len(headers) != 0 {
+		t.Errorf("Expected the rebound hop refused at dial time, got %v with %d follows", resp.Error, len(headers))
+	}
+
+	// A custom client's transport is guarded the same way
+	loopback := redirectServer(t, func(string) string { return target.URL + "/new" }, &headers)
+	custom, _ := NewClient(loopback.URL+"/old", testSecret, WithHTTPClient(&http.Client{}), WithRedirectPolicy(RedirectFollow(3)))
+	if resp := custom.Send(context.Background(), "order.created", nil); !errors.Is(resp.Error, ErrRedirect) || len(headers) != 0 {
+		t.Errorf("Expected the custom client's hop refused, got %v", resp.Error)
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	marked := requirePublic(context.Background(), ErrRedirect)
+	for _, addr := range []string{"127.0.0.1:80", "169.254.169.254:80", "10.0.0.5:443", "[::1]:443"} {
+		if err := checkDialAddress(marked, "tcp", addr, nil); !errors.Is(err, ErrRedirect) {
+			t.Errorf("Expected %s refused, got %v", addr, err)
+		}
+	}
+	if err := checkDialAddress(marked, "tcp", "93.184.215.14:443", nil); err != nil {
+		t.Errorf("Expected a public address permitted, got %v", err)
+	}
+	if err := checkDialAddress(context.Background(), "tcp", "127.0.0.1:80", nil); err != nil {
+		t.Errorf("Expected unmarked dials unrestricted, got %v", err)
+	}
+}
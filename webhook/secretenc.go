@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// WithSecretEncoding declares how the HMAC secrets passed to NewClient and
+// WithAdditionalSecret encode their key bytes, e.g. signing.EncodingHex for a
+// provider that issues hex secrets. Ed25519 keys are unaffected.
+func WithSecretEncoding(enc signing.SecretEncoding) Option {
+	return func(c *Config) {
+		c.SecretEncoding = enc
+	}
+}
+
+// NewClientWithKey creates a client signing with raw HMAC key bytes
+func NewClientWithKey(targetURL string, key []byte, opts ...Option) (*Client, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("webhook: key is required")
+	}
+	return NewClient(targetURL, signing.EncodeSecret(key), opts...)
+}
+
+// normalizeSecrets rewrites every configured secret to its whsec_ form
+func normalizeSecrets(cfg *Config) error {
+	if cfg.SecretEncoding == "" || cfg.SecretEncoding == signing.EncodingBase64 {
+		return nil
+	}
+	var err error
+	if cfg.Secret, err = signing.NormalizeSecret(cfg.Secret, cfg.SecretEncoding); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	secrets := make([]string, len(cfg.AdditionalSecrets))
+	for i, s := range cfg.AdditionalSecrets {
+		if secrets[i], err = signing.NormalizeSecret(s, cfg.SecretEncoding); err != nil {
+			return fmt.Errorf("webhook: additional secret %d: %w", i+1, err)
+		}
+	}
+	cfg.AdditionalSecrets = secrets
+	return nil
+}
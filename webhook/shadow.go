@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+)
+
+// ShadowHeader marks mirrored deliveries so the shadow receiver can tell them apart
+const ShadowHeader = "Webhook-Shadow"
+
+// ShadowResult is the outcome of one mirrored delivery
+type ShadowResult struct {
+	MessageID  string
+	URL        string
+	StatusCode int // Zero when no response was received
+	Duration   time.Duration
+	Error      error
+}
+
+// WithShadowTarget mirrors every delivery to url, e.g. a new receiver
+// implementation being validated against production traffic before cutover.
+// Shadow deliveries are sent once in the background, without retries, and
+// never affect the Response of the real send. Outcomes are logged under
+// "webhook: shadow delivery" and passed to fn when it is non-nil.
+func WithShadowTarget(url string, fn func(ShadowResult)) Option {
+	return func(c *Config) {
+		c.ShadowURL = url
+		c.OnShadow = fn
+	}
+}
+
+// mirror sends a copy of d to the shadow target, if any
+func (c *Client) mirror(d delivery) {
+	if c.config.ShadowURL == "" {
+		return
+	}
+
+	d.header = d.header.Clone()
+	d.header.Set(ShadowHeader, "true")
+	d.target = c.config.ShadowURL
+	c.seal(&d) // v1h signatures cover the shadow URL
+
+	go func() {
+		res := c.sendShadow(d)
+		attrs := []any{"msgId", res.MessageID, "url", res.URL, "status", res.StatusCode, "duration", res.Duration}
+		if res.Error != nil {
+			c.logger.Warn("webhook: shadow delivery failed", append(attrs, "error", res.Error)...)
+		} else {
+			c.logger.Info("webhook: shadow delivery", attrs...)
+		}
+		if c.config.OnShadow != nil {
+			c.config.OnShadow(res)
+		}
+	}()
+}
+
+func (c *Client) sendShadow(d delivery) (res ShadowResult) {
+	res = ShadowResult{MessageID: d.msgID, URL: d.target}
+	start := time.Now()
+	defer func() { res.Duration = time.Since(start) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+	req, err := c.newRequest(ctx, d, 1)
+	if err != nil {
+		res.Error = fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+		return res
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		res.Error = fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+		return res
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	res.StatusCode = resp.StatusCode
+	switch {
+	case resp.StatusCode >= 500:
+		res.Error = fmt.Errorf("%w: status %d: %s", ErrServerError, resp.StatusCode, redact.String(string(body)))
+	case resp.StatusCode >= 400:
+		res.Error = fmt.Errorf("%w: status %d: %s", ErrClientError, resp.StatusCode, redact.String(string(body)))
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ShadowTarget(t *testing.T) {
+	primary, primaryHits := countingServer(t, http.StatusOK)
+
+	shadowHeaders := make(chan http.Header, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowHeaders <- r.Header.Clone()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+
+	results := make(chan ShadowResult, 1)
+	client, _ := NewClient(primary.URL, testSecret, WithMaxRetries(3),
+		WithShadowTarget(shadow.URL, func(r ShadowResult) { results <- r }))
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success || *primaryHits != 1 {
+		t.Fatalf("Expected the primary delivery to be unaffected, got %+v", resp)
+	}
+
+	var res ShadowResult
+	select {
+	case res = <-results:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the shadow delivery")
+	}
+	h := <-shadowHeaders
+
+	if res.MessageID != resp.MessageID || res.URL != shadow.URL || res.StatusCode != 500 || !errors.Is(res.Error, ErrServerError) {
+		t.Errorf("Unexpected shadow result: %+v", res)
+	}
+	if h.Get(ShadowHeader) != "true" || h.Get("svix-id") != resp.MessageID || h.Get("svix-signature") == "" {
+		t.Errorf("Expected a signed, marked copy, got %v", h)
+	}
+	// A failing shadow is never retried
+	select {
+	case <-shadowHeaders:
+		t.Error("Expected a single shadow attempt")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
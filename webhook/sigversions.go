@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// ErrQueryTokenProbe is returned by ProbeSignatureVersions for clients using
+// WithQueryToken, whose deliveries carry a token instead of signatures
+var ErrQueryTokenProbe = errors.New("webhook: query-token deliveries carry no signature versions to negotiate")
+
+// probeEvent names the deliveries sent by ProbeSignatureVersions
+const probeEvent = ReservedPrefix + "signature_probe"
+
+// WithAdditionalSecret also signs every message with secret, e.g. a whsk_ key
+// alongside a whsec_ secret, so receivers can verify whichever version they support
+func WithAdditionalSecret(secret string) Option {
+	return func(c *Config) {
+		c.AdditionalSecrets = append(c.AdditionalSecrets, secret)
+	}
+}
+
+// WithSignatureVersions emits only the listed versions (e.g. "v1a") instead of
+// one signature per configured secret. List v1h too to keep the signature
+// added by WithSignedHeaders.
+func WithSignatureVersions(versions ...string) Option {
+	return func(c *Config) {
+		c.SignatureVersions = versions
+	}
+}
+
+func newSigners(cfg Config) ([]signing.Signer, error) {
+	var signers []signing.Signer
+	for _, secret := range append([]string{cfg.Secret}, cfg.AdditionalSecrets...) {
+		s, err := signing.NewSigner(secret)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: failed to create signer: %w", err)
+		}
+		if slices.ContainsFunc(signers, func(o signing.Signer) bool { return o.Version() == s.Version() }) {
+			return nil, fmt.Errorf("webhook: more than one %s secret configured", s.Version())
+		}
+		signers = append(signers, s)
+	}
+	return signers, nil
+}
+
+// availableVersions lists the versions the configured secrets can sign with,
+// plus v1h when signed headers are configured
+func availableVersions(signers []signing.Signer, headerSig bool) []string {
+	var available []string
+	for _, s := range signers {
+		available = append(available, s.Version())
+	}
+	if headerSig {
+		available = append(available, signing.SchemeV1H)
+	}
+	return available
+}
+
+// enabledVersions resolves the requested versions against the configured signers
+func enabledVersions(signers []signing.Signer, requested []string, headerSig bool) ([]string, error) {
+	available := availableVersions(signers, headerSig)
+	if len(requested) == 0 {
+		return available, nil
+	}
+	for _, v := range requested {
+		if !slices.Contains(available, v) {
+			return nil, fmt.Errorf("webhook: signature version %q has no configured secret (have %s)", v, strings.Join(available, ", "))
+		}
+	}
+	return slices.Clone(requested), nil
+}
+
+// SignatureVersions returns the signature versions currently emitted
+func (c *Client) SignatureVersions() []string {
+	return slices.Clone(*c.versions.Load())
+}
+
+// Sign returns the signature header value for body under the emitted
+// versions, for transports other than HTTP delivery (e.g. a WebSocket push)
+func (c *Client) Sign(msgID string, timestamp time.Time, body []byte) string {
+	return c.sign(msgID, timestamp, body, *c.versions.Load())
+}
+
+// signatureVersion names how a sealed delivery is authenticated: the versions
+// of its signatures, space-separated, or QueryTokenVersion
+func (c *Client) signatureVersion(d delivery) string {
+	if c.tokenKey != nil {
+		return QueryTokenVersion
+	}
+	sigs, _ := signing.ParseSignatures(d.signature)
+	versions := make([]string, 0, len(sigs))
+	for _, s := range sigs {
+		versions = append(versions, s.Version)
+	}
+	return strings.Join(versions, " ")
+}
+
+// sign returns the space-separated signatures for the given versions
+func (c *Client) sign(msgID string, timestamp time.Time, body []byte, versions []string) string {
+	var sigs []string
+	for _, s := range c.signers {
+		if slices.Contains(versions, s.Version()) {
+			sigs = append(sigs, s.Sign(msgID, timestamp, body))
+		}
+	}
+	return strings.Join(sigs, " ")
+}
+
+// ProbeSignatureVersions sends one hookshot.signature_probe delivery per
+// configured version, v1h included, each signed with that version alone, and
+// restricts later sends to the versions the receiver accepted with a 2xx. If
+// none is accepted the emitted versions are left unchanged and an error is
+// returned. Query-token clients send no signature headers, so there is
+// nothing to negotiate and ErrQueryTokenProbe is returned.
+func (c *Client) ProbeSignatureVersions(ctx context.Context) ([]string, error) {
+	if c.tokenKey != nil {
+		return nil, ErrQueryTokenProbe
+	}
+
+	var accepted []string
+	var errs []string
+	for _, version := range availableVersions(c.signers, c.headerKey != nil) {
+		status, err := c.probe(ctx, version)
+		if err == nil && status >= 200 && status < 300 {
+			accepted = append(accepted, version)
+			continue
+		}
+		if err == nil {
+			err = fmt.Errorf("status %d", status)
+		}
+		errs = append(errs, version+": "+err.Error())
+	}
+
+	if len(accepted) == 0 {
+		return nil, fmt.Errorf("%w: no signature version accepted (%s)", ErrClientError, strings.Join(errs, "; "))
+	}
+	c.versions.Store(&accepted)
+	c.logger.Info("webhook: negotiated signature versions", "target", c.config.TargetURL, "versions", accepted)
+	return slices.Clone(accepted), nil
+}
+
+func (c *Client) probe(ctx context.Context, version string) (int, error) {
+	body, err := json.Marshal(Payload{Event: probeEvent, Timestamp: c.det.Now(), Data: map[string]string{"version": version}})
+	if err != nil {
+		return 0, err
+	}
+	d := delivery{body: body, msgID: c.det.NewID(), header: make(http.Header)}
+	c.sealWith(&d, []string{version})
+	req, err := c.newRequest(ctx, d, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// versionServer accepts deliveries whose signature verifies with verifier
+func versionServer(t *testing.T, verifier signing.Verifier, headers *[]http.Header) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*headers = append(*headers, r.Header.Clone())
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get("svix-timestamp"), 10, 64)
+		if err := verifier.Verify(r.Header.Get("svix-id"), time.Unix(ts, 0), body, r.Header.Get("svix-signature")); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signatureVersions(h http.Header) []string {
+	sigs, _ := signing.ParseSignatures(h.Get("svix-signature"))
+	var out []string
+	for _, s := range sigs {
+		out = append(out, s.Version)
+	}
+	return out
+}
+
+func TestClient_SignatureVersions(t *testing.T) {
+	secretKey, publicKey, _ := signing.GenerateKeyPair()
+	verifier, _ := signing.NewVerifier(publicKey)
+
+	var headers []http.Header
+	server := versionServer(t, verifier, &headers)
+
+	client, err := NewClient(server.URL, testSecret, WithAdditionalSecret(secretKey), WithMaxRetries(1))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if resp := client.Send(context.Background(), "order.created", map[string]any{}); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if got := signatureVersions(headers[0]); !slices.Equal(got, []string{"v1", "v1a"}) {
+		t.Errorf("Expected v1 and v1a signatures, got %v", got)
+	}
+
+	only, _ := NewClient(server.URL, testSecret, WithAdditionalSecret(secretKey), WithSignatureVersions("v1a"), WithMaxRetries(1))
+	only.Send(context.Background(), "order.created", map[string]any{})
+	if got := signatureVersions(headers[1]); !slices.Equal(got, []string{"v1a"}) {
+		t.Errorf("Expected only v1a signature, got %v", got)
+	}
+}
+
+func TestNewClient_SignatureVersions(t *testing.T) {
+	secretKey, _, _ := signing.GenerateKeyPair()
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "unconfigured version", opts: []Option{WithSignatureVersions("v1a")}},
+		{name: "duplicate version", opts: []Option{WithAdditionalSecret(testSecret)}},
+		{name: "invalid additional secret", opts: []Option{WithAdditionalSecret("whsk_bad")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient("http://localhost:4000/webhook", testSecret, tt.opts...); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+	if _, err := NewClient("http://localhost:4000/webhook", testSecret, WithAdditionalSecret(secretKey), WithSignatureVersions("v1a")); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestClient_ProbeSignatureVersions(t *testing.T) {
+	secretKey, publicKey, _ := signing.GenerateKeyPair()
+	verifier, _ := signing.NewVerifier(publicKey)
+
+	var headers []http.Header
+	server := versionServer(t, verifier, &headers)
+	client, _ := NewClient(server.URL, testSecret, WithAdditionalSecret(secretKey))
+
+	versions, err := client.ProbeSignatureVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ProbeSignatureVersions() error = %v", err)
+	}
+	if !slices.Equal(versions, []string{"v1a"}) || !slices.Equal(client.SignatureVersions(), []string{"v1a"}) {
+		t.Errorf("Expected negotiated v1a, got %v / %v", versions, client.SignatureVersions())
+	}
+	if len(headers) != 2 {
+		t.Fatalf("Expected one probe per version, got %d", len(headers))
+	}
+	for i, want := range []string{"v1", "v1a"} {
+		if got := signatureVersions(headers[i]); !slices.Equal(got, []string{want}) {
+			t.Errorf("Expected probe %d signed with %s only, got %v", i, want, got)
+		}
+	}
+
+	// A receiver accepting nothing leaves the versions unchanged
+	hmacOnly, _ := NewClient(server.URL, testSecret)
+	if _, err := hmacOnly.ProbeSignatureVersions(context.Background()); err == nil {
+		t.Error("Expected error when no version is accepted")
+	}
+	if !slices.Equal(hmacOnly.SignatureVersions(), []string{"v1"}) {
+		t.Errorf("Expected versions unchanged, got %v", hmacOnly.SignatureVersions())
+	}
+}
+
+func TestClient_ProbeSignatureVersions_V1H(t *testing.T) {
+	var versions [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := signatureVersions(r.Header)
+		versions = append(versions, got)
+		if !slices.Equal(got, []string{signing.SchemeV1H}) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithSignedHeaders(), WithMaxRetries(1))
+	if got := client.SignatureVersions(); !slices.Equal(got, []string{"v1", "v1h"}) {
+		t.Fatalf("Expected v1 and v1h emitted, got %v", got)
+	}
+
+	negotiated, err := client.ProbeSignatureVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ProbeSignatureVersions() error = %v", err)
+	}
+	if !slices.Equal(negotiated, []string{"v1h"}) {
+		t.Errorf("Expected negotiated v1h, got %v", negotiated)
+	}
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success {
+		t.Fatalf("Expected success with v1h alone, got %v", resp.Error)
+	}
+	if got := resp.Attempts[0].SignatureVersion; got != "v1h" {
+		t.Errorf("Expected attempt to record v1h, got %q", got)
+	}
+}
+
+func TestClient_AttemptSignatureVersion(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(1))
+	if got := client.Send(context.Background(), "order.created", nil).Attempts[0].SignatureVersion; got != "v1" {
+		t.Errorf("Expected v1, got %q", got)
+	}
+
+	tokens, _ := NewClient(server.URL, testSecret, WithQueryToken(time.Minute), WithMaxRetries(1))
+	if got := tokens.Send(context.Background(), "order.created", nil).Attempts[0].SignatureVersion; got != QueryTokenVersion {
+		t.Errorf("Expected %q, got %q", QueryTokenVersion, got)
+	}
+	if _, err := tokens.ProbeSignatureVersions(context.Background()); !errors.Is(err, ErrQueryTokenProbe) {
+		t.Errorf("Expected ErrQueryTokenProbe, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+)
+
+// ErrNoCanary is returned when changing the split of a client without a canary URL
+var ErrNoCanary = errors.New("webhook: no canary target configured")
+
+// WithTrafficSplit routes percent (0-100) of deliveries to canaryURL, e.g. a
+// new receiver version, and the rest to the primary target. Assignment is
+// deterministic by ordering key, or by message ID for sends without one, so
+// related events and every retry of a message land on the same version.
+// Adjust the split at runtime with SetCanaryPercent.
+func WithTrafficSplit(canaryURL string, percent int) Option {
+	return func(c *Config) {
+		c.CanaryURL = canaryURL
+		c.CanaryPercent = percent
+	}
+}
+
+func validPercent(p int) error {
+	if p < 0 || p > 100 {
+		return fmt.Errorf("webhook: canary percent must be between 0 and 100, got %d", p)
+	}
+	return nil
+}
+
+// CanaryURL returns the canary target, empty when no split is configured
+func (c *Client) CanaryURL() string {
+	return c.config.CanaryURL
+}
+
+// CanaryPercent returns the share of deliveries currently routed to the canary
+func (c *Client) CanaryPercent() int {
+	return int(c.canaryPercent.Load())
+}
+
+// SetCanaryPercent changes the split: 100 promotes the canary, 0 rolls it back
+func (c *Client) SetCanaryPercent(p int) error {
+	if c.config.CanaryURL == "" {
+		return ErrNoCanary
+	}
+	if err := validPercent(p); err != nil {
+		return err
+	}
+	c.canaryPercent.Store(int32(p))
+	c.logger.Info("webhook: traffic split changed", "canary", c.config.CanaryURL, "percent", p)
+	return nil
+}
+
+// splitTarget returns the canary URL when key's bucket falls in the canary share
+func (c *Client) splitTarget(key string) (string, bool) {
+	if c.config.CanaryURL == "" {
+		return "", false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	if int(h.Sum32()%100) < c.CanaryPercent() {
+		return c.config.CanaryURL, true
+	}
+	return "", false
+}
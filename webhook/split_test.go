@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestClient_TrafficSplit(t *testing.T) {
+	primary, primaryHits := countingServer(t, http.StatusOK)
+	canary, canaryHits := countingServer(t, http.StatusOK)
+
+	client, err := NewClient(primary.URL, testSecret, WithTrafficSplit(canary.URL, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("customer-%d", i%200)
+		resp := client.Send(context.Background(), "order.created", nil, WithOrderingKey(key))
+		target := resp.Attempts[0].Target
+		if prev, ok := routes[key]; ok && prev != target {
+			t.Fatalf("Expected %s to stick to %s, got %s", key, prev, target)
+		}
+		routes[key] = target
+	}
+	if share := float64(*canaryHits) / 1000; share < 0.05 || share > 0.15 {
+		t.Errorf("Expected about 10%% canary traffic, got %d of 1000", *canaryHits)
+	}
+
+	// Promotion and rollback
+	client.SetCanaryPercent(100)
+	before := *primaryHits
+	client.Send(context.Background(), "order.created", nil)
+	if *primaryHits != before {
+		t.Error("Expected all traffic on the canary after promotion")
+	}
+	client.SetCanaryPercent(0)
+	before = *canaryHits
+	client.Send(context.Background(), "order.created", nil)
+	if *canaryHits != before {
+		t.Error("Expected no canary traffic after rollback")
+	}
+}
+
+func TestClient_TrafficSplit_Invalid(t *testing.T) {
+	if _, err := NewClient("http://a", testSecret, WithTrafficSplit("http://b", 101)); err == nil {
+		t.Error("Expected error for a percent over 100")
+	}
+
+	client, _ := NewClient("http://a", testSecret)
+	if err := client.SetCanaryPercent(50); !errors.Is(err, ErrNoCanary) {
+		t.Errorf("Expected ErrNoCanary, got %v", err)
+	}
+
+	split, _ := NewClient("http://a", testSecret, WithTrafficSplit("http://b", 10))
+	if err := split.SetCanaryPercent(-1); err == nil || split.CanaryPercent() != 10 {
+		t.Errorf("Expected a rejected update leaving 10%%, got %v, %d%%", err, split.CanaryPercent())
+	}
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// MessageState is where a message is in its delivery lifecycle
+type MessageState string
+
+const (
+	StateAccepted       MessageState = "accepted"        // Validated and given a message ID
+	StateQueued         MessageState = "queued"          // Parked by Pause or DisableDelivery until resumed
+	StateAttempting     MessageState = "attempting"      // An attempt is in flight
+	StateRetryScheduled MessageState = "retry_scheduled" // An attempt failed and another is scheduled
+	StateDelivered      MessageState = "delivered"       // Accepted by the receiver with a 2xx; terminal
+	StateFailed         MessageState = "failed"          // Rejected or out of attempts; terminal, as there is no dead-letter queue
+	StateExpired        MessageState = "expired"         // The context deadline cut the retries short; Deferred.Send resumes it
+)
+
+// transitions lists the states each state may move to; "" is a message not yet seen
+var transitions = map[MessageState][]MessageState{
+	"":                  {StateAccepted, StateAttempting},
+	StateAccepted:       {StateQueued, StateAttempting, StateFailed},
+	StateQueued:         {StateAttempting},
+	StateAttempting:     {StateDelivered, StateRetryScheduled, StateFailed, StateExpired},
+	StateRetryScheduled: {StateAttempting, StateQueued, StateFailed, StateExpired},
+	StateExpired:        {StateAttempting},
+}
+
+// CanTransition reports whether a message in s may move to next
+func (s MessageState) CanTransition(next MessageState) bool {
+	for _, to := range transitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminal reports whether no further transition is possible
+func (s MessageState) Terminal() bool {
+	return s != "" && len(transitions[s]) == 0
+}
+
+// Transition is one state change of a message
+type Transition struct {
+	MessageID string
+	Event     string // Payload event name
+	From, To  MessageState
+	Attempt   int // Attempt number for StateAttempting and the states after it
+	Time      time.Time
+}
+
+// stageStates maps lifecycle stages to the state they enter
+var stageStates = map[DeliveryStage]MessageState{
+	StageParked:   StateQueued,
+	StageRetried:  StateRetryScheduled,
+	StageSent:     StateDelivered,
+	StageFailed:   StateFailed,
+	StageDeferred: StateExpired,
+}
+
+// stateMachine tracks every message until it ends and remembers the last
+// settledHistory ended ones
+type stateMachine struct {
+	mu     sync.Mutex
+	states map[string]MessageState
+	ended  []string // Oldest first
+	next   int
+	hooks  map[int]func(Transition)
+}
+
+// advance moves msgID to state and calls the hooks; disallowed transitions are ignored
+func (m *stateMachine) advance(t Transition) {
+	m.mu.Lock()
+	if m.states == nil {
+		m.states = make(map[string]MessageState)
+	}
+	t.From = m.states[t.MessageID]
+	if !t.From.CanTransition(t.To) {
+		m.mu.Unlock()
+		return
+	}
+	m.states[t.MessageID] = t.To
+	if t.To.Terminal() || t.To == StateExpired {
+		m.ended = append(m.ended, t.MessageID)
+		if len(m.ended) > settledHistory {
+			if old := m.ended[0]; m.states[old].Terminal() || m.states[old] == StateExpired {
+				delete(m.states, old)
+			}
+			m.ended = m.ended[1:]
+		}
+	}
+	hooks := make([]func(Transition), 0, len(m.hooks))
+	for _, fn := range m.hooks {
+		hooks = append(hooks, fn)
+	}
+	m.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(t)
+	}
+}
+
+// transition records a state change of d at the client's clock
+func (c *Client) transition(d delivery, to MessageState, attempt int) {
+	c.states.advance(Transition{MessageID: d.msgID, Event: d.event, To: to, Attempt: attempt, Time: c.det.Now()})
+}
+
+// State returns the lifecycle state of messageID. Messages are tracked from
+// acceptance until they end; the last 1024 ended ones are remembered.
+func (c *Client) State(messageID string) (MessageState, bool) {
+	c.states.mu.Lock()
+	defer c.states.mu.Unlock()
+	s, ok := c.states.states[messageID]
+	return s, ok
+}
+
+// OnTransition calls fn synchronously for every state change of every message
+// until stop is called. fn must not block.
+func (c *Client) OnTransition(fn func(Transition)) (stop func()) {
+	m := &c.states
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hooks == nil {
+		m.hooks = make(map[int]func(Transition))
+	}
+	id := m.next
+	m.next++
+	m.hooks[id] = fn
+
+	return func() {
+		m.mu.Lock()
+		delete(m.hooks, id)
+		m.mu.Unlock()
+	}
+}
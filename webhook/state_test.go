@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestClient_StateMachine(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	det, _ := testDeterminism()
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det))
+
+	var got []MessageState
+	stop := client.OnTransition(func(tr Transition) {
+		if !tr.From.CanTransition(tr.To) {
+			t.Errorf("Unexpected transition %s -> %s", tr.From, tr.To)
+		}
+		got = append(got, tr.To)
+	})
+	defer stop()
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+	want := []MessageState{StateAccepted, StateAttempting, StateRetryScheduled, StateAttempting, StateDelivered}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected transitions %v, got %v", want, got)
+	}
+	if resp.State != StateDelivered {
+		t.Errorf("Expected response state %s, got %s", StateDelivered, resp.State)
+	}
+	if s, ok := client.State(resp.MessageID); !ok || s != StateDelivered {
+		t.Errorf("Expected State %s, got %s %v", StateDelivered, s, ok)
+	}
+	if _, ok := client.State("msg_unknown"); ok {
+		t.Error("Expected unknown message to have no state")
+	}
+}
+
+func TestClient_StateMachine_QueuedAndFailed(t *testing.T) {
+	server, _ := countingServer(t, http.StatusBadRequest)
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(0))
+
+	client.Pause()
+	parked := client.Send(context.Background(), "order.created", map[string]any{})
+	if s, _ := client.State(parked.MessageID); s != StateQueued || parked.State != StateQueued {
+		t.Errorf("Expected parked message to be %s, got %s and %s", StateQueued, s, parked.State)
+	}
+	for resp := range client.Resume() {
+		if resp.State != StateFailed {
+			t.Errorf("Expected flushed rejection to be %s, got %s", StateFailed, resp.State)
+		}
+	}
+	if s, _ := client.State(parked.MessageID); s != StateFailed || !s.Terminal() {
+		t.Errorf("Expected terminal %s, got %s", StateFailed, s)
+	}
+	if resp := client.Send(context.Background(), "hookshot.internal", map[string]any{}); resp.State != "" {
+		t.Errorf("Expected unaccepted send to have no state, got %s", resp.State)
+	}
+}
+
+func TestMessageState_Transitions(t *testing.T) {
+	if StateDelivered.CanTransition(StateAttempting) || StateQueued.CanTransition(StateDelivered) {
+		t.Error("Expected disallowed transitions to be refused")
+	}
+	if !StateExpired.CanTransition(StateAttempting) || StateExpired.Terminal() {
+		t.Error("Expected expired messages to be resumable")
+	}
+}
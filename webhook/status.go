@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrUnexpectedStatus is returned for a 2xx response outside SuccessStatuses
+var ErrUnexpectedStatus = errors.New("webhook: status not in the success set")
+
+// WithSuccessStatuses sets the exact statuses that count as a delivered
+// message, e.g. 200 and 202 only, or 409 for receivers that answer duplicates
+// with a conflict. A 2xx outside the set fails with ErrUnexpectedStatus and is
+// not retried; 3xx, 4xx and 5xx outside it keep their usual handling.
+func WithSuccessStatuses(codes ...int) Option {
+	return func(c *Config) {
+		c.SuccessStatuses = codes
+	}
+}
+
+// delivered reports whether status counts as a successful delivery
+func (c *Client) delivered(status int) bool {
+	if len(c.config.SuccessStatuses) > 0 {
+		return slices.Contains(c.config.SuccessStatuses, status)
+	}
+	return status >= 200 && status < 300
+}
+
+// retryRedirects reports whether unfollowed 3xx responses are retried
+func (c *Client) retryRedirects() bool {
+	return c.config.RedirectPolicy != nil && c.config.RedirectPolicy.Retry
+}
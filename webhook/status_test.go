@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SuccessStatuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		opts    []Option
+		success bool
+		err     error
+	}{
+		{"default 2xx", http.StatusNoContent, nil, true, nil},
+		{"default 3xx", http.StatusFound, nil, false, ErrRedirect},
+		{"2xx outside the set", http.StatusNoContent, []Option{WithSuccessStatuses(http.StatusOK)}, false, ErrUnexpectedStatus},
+		{"4xx in the set", http.StatusConflict, []Option{WithSuccessStatuses(http.StatusOK, http.StatusConflict)}, true, nil},
+		{"3xx in the set", http.StatusFound, []Option{WithSuccessStatuses(http.StatusFound)}, true, nil},
+		{"5xx outside the set", http.StatusBadGateway, []Option{WithSuccessStatuses(http.StatusOK)}, false, ErrServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := countingServer(t, tt.status)
+			det, _ := testDeterminism()
+			client, _ := NewClient(server.URL, testSecret, append(tt.opts, WithDeterminism(det), WithMaxRetries(2))...)
+
+			resp := client.Send(context.Background(), "order.created", nil)
+			if resp.Success != tt.success || !errors.Is(resp.Error, tt.err) {
+				t.Fatalf("Expected success %v and error %v, got %v and %v", tt.success, tt.err, resp.Success, resp.Error)
+			}
+			want := 1
+			if tt.err == ErrServerError {
+				want = 2
+			}
+			if *hits != want {
+				t.Errorf("Expected %d requests, got %d", want, *hits)
+			}
+		})
+	}
+}
+
+func TestClient_RedirectRetry(t *testing.T) {
+	server, hits := countingServer(t, http.StatusTemporaryRedirect)
+	det, _ := testDeterminism()
+	policy := RedirectNone
+	policy.Retry = true
+	client, _ := NewClient(server.URL, testSecret, WithDeterminism(det), WithMaxRetries(3), WithRedirectPolicy(policy))
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !errors.Is(resp.Error, ErrRedirect) || *hits != 3 {
+		t.Errorf("Expected the redirect retried 3 times, got %v after %d requests", resp.Error, *hits)
+	}
+}
+
+func TestConfig_Validate_SuccessStatuses(t *testing.T) {
+	cfg := NewConfig("https://example.com/hook", testSecret, WithSuccessStatuses(http.StatusOK, http.StatusContinue))
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected an informational status to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// TargetStrategy selects among a Client's target URLs
+type TargetStrategy int
+
+const (
+	// Failover sends to the first healthy target in order, so later targets
+	// only receive traffic while earlier ones are failing
+	Failover TargetStrategy = iota
+	// RoundRobin rotates through the healthy targets
+	RoundRobin
+)
+
+// WithTargets pools additional target URLs with the primary one passed to
+// NewClient. Each attempt, including retries, picks a target by strategy;
+// targets that fail with a network error or 5xx are excluded for the
+// cooldown (default: 30s) while others remain healthy.
+func WithTargets(strategy TargetStrategy, urls ...string) Option {
+	return func(c *Config) {
+		c.Targets = urls
+		c.TargetStrategy = strategy
+	}
+}
+
+// WithTargetCooldown sets how long a failing target is excluded
+func WithTargetCooldown(d time.Duration) Option {
+	return func(c *Config) {
+		c.TargetCooldown = d
+	}
+}
+
+// targetPool tracks target health for health-aware selection
+type targetPool struct {
+	urls     []string
+	strategy TargetStrategy
+	cooldown time.Duration
+
+	mu   sync.Mutex
+	next int
+	down []time.Time // Excluded until, per target
+}
+
+func newTargetPool(cfg Config) (*targetPool, error) {
+	urls := append([]string{cfg.TargetURL}, cfg.Targets...)
+	for _, u := range cfg.Targets {
+		if u == "" {
+			return nil, errors.New("webhook: target URLs must not be empty")
+		}
+	}
+	if len(urls) > 1 && len(cfg.SignedHeaders) > 0 {
+		return nil, errors.New("webhook: signed headers cover a single target URL and cannot be used with multiple targets")
+	}
+	if cfg.TargetCooldown == 0 {
+		cfg.TargetCooldown = 30 * time.Second
+	}
+	return &targetPool{
+		urls:     urls,
+		strategy: cfg.TargetStrategy,
+		cooldown: cfg.TargetCooldown,
+		down:     make([]time.Time, len(urls)),
+	}, nil
+}
+
+// pick returns the next target URL. When every target is excluded, the one
+// whose exclusion ends first is used rather than failing outright.
+func (p *targetPool) pick(now time.Time) string {
+	if len(p.urls) == 1 {
+		return p.urls[0]
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := 0
+	if p.strategy == RoundRobin {
+		start = p.next
+		p.next = (p.next + 1) % len(p.urls)
+	}
+
+	best := start
+	for i := range p.urls {
+		idx := (start + i) % len(p.urls)
+		if !now.Before(p.down[idx]) {
+			return p.urls[idx]
+		}
+		if p.down[idx].Before(p.down[best]) {
+			best = idx
+		}
+	}
+	return p.urls[best]
+}
+
+// report records the outcome of an attempt against target
+func (p *targetPool) report(target string, healthy bool, now time.Time) {
+	if len(p.urls) == 1 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, u := range p.urls {
+		if u != target {
+			continue
+		}
+		if healthy {
+			p.down[i] = time.Time{}
+		} else {
+			p.down[i] = now.Add(p.cooldown)
+		}
+	}
+}
+
+// Targets returns the pooled target URLs, primary first
+func (c *Client) Targets() []string {
+	return append([]string(nil), c.targets.urls...)
+}
@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// countingServer answers every request with status and counts them
+func countingServer(t *testing.T, status int) (*httptest.Server, *int) {
+	t.Helper()
+	n := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &n
+}
+
+func TestClient_Targets_Failover(t *testing.T) {
+	primary, primaryHits := countingServer(t, http.StatusServiceUnavailable)
+	backup, backupHits := countingServer(t, http.StatusOK)
+
+	det, _ := testDeterminism()
+	now := time.Unix(1700000000, 0)
+	det.Now = func() time.Time { return now }
+	client, err := NewClient(primary.URL, testSecret, WithDeterminism(det),
+		WithTargets(Failover, backup.URL), WithTargetCooldown(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success {
+		t.Fatalf("Expected failover to succeed, got %v", resp.Error)
+	}
+	if got := []string{resp.Attempts[0].Target, resp.Attempts[1].Target}; !slices.Equal(got, []string{primary.URL, backup.URL}) {
+		t.Errorf("Expected retry on the backup, got %v", got)
+	}
+
+	// The failing primary stays excluded for the cooldown
+	client.Send(context.Background(), "order.created", nil)
+	if *primaryHits != 1 || *backupHits != 2 {
+		t.Errorf("Expected primary excluded, got primary=%d backup=%d", *primaryHits, *backupHits)
+	}
+
+	now = now.Add(time.Minute)
+	client.Send(context.Background(), "order.created", nil)
+	if *primaryHits != 2 {
+		t.Errorf("Expected primary retried after the cooldown, got %d hits", *primaryHits)
+	}
+}
+
+func TestClient_Targets_RoundRobin(t *testing.T) {
+	a, aHits := countingServer(t, http.StatusOK)
+	b, bHits := countingServer(t, http.StatusOK)
+	c, cHits := countingServer(t, http.StatusBadRequest) // 4xx: rejected but healthy
+
+	client, _ := NewClient(a.URL, testSecret, WithTargets(RoundRobin, b.URL, c.URL))
+	for i := 0; i < 6; i++ {
+		client.Send(context.Background(), "order.created", nil)
+	}
+
+	if *aHits != 2 || *bHits != 2 || *cHits != 2 {
+		t.Errorf("Expected even rotation, got a=%d b=%d c=%d", *aHits, *bHits, *cHits)
+	}
+	if got := client.Targets(); !slices.Equal(got, []string{a.URL, b.URL, c.URL}) {
+		t.Errorf("Expected targets primary first, got %v", got)
+	}
+}
+
+func TestTargetPool_AllDown(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	pool, _ := newTargetPool(Config{TargetURL: "http://a", Targets: []string{"http://b"}})
+	pool.report("http://a", false, now.Add(time.Second))
+	pool.report("http://b", false, now)
+
+	if got := pool.pick(now); got != "http://b" {
+		t.Errorf("Expected the target recovering first, got %s", got)
+	}
+}
+
+func TestNewClient_TargetsInvalid(t *testing.T) {
+	if _, err := NewClient("http://a", testSecret, WithTargets(Failover, "")); err == nil {
+		t.Error("Expected error for an empty target")
+	}
+	if _, err := NewClient("http://a", testSecret, WithTargets(Failover, "http://b"), WithSignedHeaders()); err == nil {
+		t.Error("Expected error combining signed headers with multiple targets")
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+// QueryTokenVersion is the Attempt.SignatureVersion of query-token deliveries
+const QueryTokenVersion = "token"
+
+// WithQueryToken authenticates deliveries with a JWT in the hookshot_token
+// query parameter instead of signature headers, for receivers on platforms
+// that cannot read custom headers. The token binds the message ID and body
+// hash and expires ttl after each attempt is sent. It requires an HMAC
+// (whsec_) secret; receivers check it with signing.VerifyToken or
+// receiver.WithQueryTokens.
+func WithQueryToken(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.QueryTokenTTL = ttl
+	}
+}
+
+// newTokenKey returns the query token key, or nil when tokens are disabled
+func newTokenKey(cfg Config, primary signing.Signer) ([]byte, error) {
+	if cfg.QueryTokenTTL <= 0 {
+		return nil, nil
+	}
+	if primary.Version() != signing.SchemeV1 {
+		return nil, fmt.Errorf("webhook: query tokens require an HMAC (whsec_) secret")
+	}
+	if len(cfg.SignedHeaders) > 0 {
+		return nil, fmt.Errorf("webhook: query tokens replace signature headers and cannot be combined with signed headers")
+	}
+	key, err := signing.DecodeSecret(cfg.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to create signer: %w", err)
+	}
+	return key, nil
+}
+
+// setToken adds a freshly issued token to the request URL
+func (c *Client) setToken(req *http.Request, d delivery) {
+	now := c.det.Now()
+	q := req.URL.Query()
+	q.Set(signing.TokenParam, signing.SignToken(c.tokenKey, d.msgID, now, now.Add(c.config.QueryTokenTTL), d.body))
+	req.URL.RawQuery = q.Encode()
+}
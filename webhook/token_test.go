@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
+)
+
+func TestNewClient_QueryToken(t *testing.T) {
+	secretKey, _, _ := signing.GenerateKeyPair()
+	if _, err := NewClient("http://localhost", secretKey, WithQueryToken(time.Minute)); err == nil {
+		t.Error("Expected error for an Ed25519 secret")
+	}
+	if _, err := NewClient("http://localhost", testSecret, WithQueryToken(time.Minute), WithSignedHeaders()); err == nil {
+		t.Error("Expected error combining query tokens with signed headers")
+	}
+	if _, err := NewClient("http://localhost", testSecret, WithQueryToken(time.Minute)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+)
+
+// Timings breaks one delivery attempt down by phase. Phases that did not
+// happen (e.g. DNS and connect on a reused connection) are zero.
+type Timings struct {
+	DNS     time.Duration // Host lookup
+	Connect time.Duration // TCP connect
+	TLS     time.Duration // TLS handshake
+	Wait    time.Duration // Request written to first response byte: the receiver's processing time
+	TTFB    time.Duration // Attempt start to first response byte
+	Total   time.Duration // Attempt start to response body read or failure
+	Reused  bool          // Served over a pooled connection
+}
+
+// Attempt records the outcome of one delivery attempt
+type Attempt struct {
+	Number           int    // 1-based, matching the Webhook-Attempt header
+	Target           string // URL the attempt was sent to
+	StatusCode       int    // Zero when no response was received
+	Error            error
+	Phase            string // For network errors, where the attempt failed: dns, connect, tls, write or wait
+	Timings          Timings
+	Hedged           bool    // A second copy was sent after the hedge threshold
+	HedgeWon         bool    // The outcome recorded is the second copy's
+	HedgeTimings     Timings // The second copy's phase timings, when Hedged
+	SignatureVersion string  // Signature versions the attempt carried, e.g. "v1 v1h", or QueryTokenVersion
+}
+
+// WithAttemptObserver calls fn after every delivery attempt, so phase timings
+// can be exported as metrics and slow DNS or TLS told apart from slow receivers
+func WithAttemptObserver(fn func(Attempt)) Option {
+	return func(c *Config) {
+		c.OnAttempt = fn
+	}
+}
+
+// attemptTrace collects httptrace events for one attempt; callbacks may run
+// on transport goroutines
+type attemptTrace struct {
+	mu                  sync.Mutex
+	start               time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	gotConn, wrote      time.Time
+	firstByte           time.Time
+	dnsErr, tlsErr      bool
+	reused              bool
+}
+
+func (t *attemptTrace) mark(at *time.Time) {
+	t.mu.Lock()
+	*at = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *attemptTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			t.mark(&t.dnsDone)
+			t.mu.Lock()
+			t.dnsErr = info.Err != nil
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			// Dialing several addresses reports several starts; keep the first
+			if t.connStart.IsZero() {
+				t.connStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone:       func(string, string, error) { t.mark(&t.connDone) },
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.mark(&t.tlsDone)
+			t.mu.Lock()
+			t.tlsErr = err != nil
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mark(&t.gotConn)
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wrote) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+func between(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+func (t *attemptTrace) timings(end time.Time) Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Timings{
+		DNS:     between(t.dnsStart, t.dnsDone),
+		Connect: between(t.connStart, t.connDone),
+		TLS:     between(t.tlsStart, t.tlsDone),
+		Wait:    between(t.wrote, t.firstByte),
+		TTFB:    between(t.start, t.firstByte),
+		Total:   end.Sub(t.start),
+		Reused:  t.reused,
+	}
+}
+
+// phase reports how far the attempt got
+func (t *attemptTrace) phase() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.dnsErr || (!t.dnsStart.IsZero() && t.dnsDone.IsZero()):
+		return "dns"
+	case t.tlsErr || (!t.tlsStart.IsZero() && t.tlsDone.IsZero()):
+		return "tls"
+	case t.gotConn.IsZero():
+		return "connect"
+	case t.wrote.IsZero():
+		return "write"
+	default:
+		return "wait"
+	}
+}
+
+// recordAttempt builds the attempt record, with credentials in the target
+// masked, and reports it to OnAttempt. Timings are the first copy's; a
+// hedged copy's are in HedgeTimings, and Phase follows the copy whose
+// outcome is recorded.
+func (c *Client) recordAttempt(n int, target, version string, status int, err error, hedge hedgeOutcome, t *attemptTrace) Attempt {
+	now := time.Now()
+	a := Attempt{Number: n, Target: redact.String(target), SignatureVersion: version, StatusCode: status, Error: err, Timings: t.timings(now)}
+	if hedge.trace != nil {
+		a.Hedged, a.HedgeWon, a.HedgeTimings = true, hedge.used, hedge.trace.timings(now)
+	}
+	if err != nil && status == 0 {
+		a.Phase = hedge.outcomeTrace(t).phase()
+	}
+	if c.config.OnAttempt != nil {
+		c.config.OnAttempt(a)
+	}
+	return a
+}
+
+// outcomeTrace returns the trace of the copy whose outcome was used
+func (h hedgeOutcome) outcomeTrace(first *attemptTrace) *attemptTrace {
+	if h.used {
+		return h.trace
+	}
+	return first
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_AttemptTimings(t *testing.T) {
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	det, _ := testDeterminism()
+	var observed []Attempt
+	client, _ := NewClient(server.URL, testSecret,
+		WithHTTPClient(server.Client()),
+		WithDeterminism(det),
+		WithAttemptObserver(func(a Attempt) { observed = append(observed, a) }),
+	)
+
+	resp := client.Send(context.Background(), "order.created", nil)
+	if !resp.Success {
+		t.Fatalf("Expected success, got %v", resp.Error)
+	}
+	if len(resp.Attempts) != 2 || len(observed) != 2 {
+		t.Fatalf("Expected 2 attempt records, got %d (observed %d)", len(resp.Attempts), len(observed))
+	}
+
+	first, second := resp.Attempts[0], resp.Attempts[1]
+	if first.Number != 1 || first.StatusCode != 500 || !errors.Is(first.Error, ErrServerError) || first.Phase != "" {
+		t.Errorf("Unexpected first attempt: %+v", first)
+	}
+	if first.Timings.Connect <= 0 || first.Timings.TLS <= 0 || first.Timings.Reused {
+		t.Errorf("Expected connect and TLS timings on a new connection, got %+v", first.Timings)
+	}
+
+	if second.Number != 2 || second.StatusCode != 200 || second.Error != nil {
+		t.Errorf("Unexpected second attempt: %+v", second)
+	}
+	if !second.Timings.Reused || second.Timings.TLS != 0 || second.Timings.Connect != 0 {
+		t.Errorf("Expected a reused connection without handshake, got %+v", second.Timings)
+	}
+	if second.Timings.Wait < 20*time.Millisecond || second.Timings.TTFB < second.Timings.Wait || second.Timings.Total < second.Timings.TTFB {
+		t.Errorf("Expected Wait >= 20ms <= TTFB <= Total, got %+v", second.Timings)
+	}
+}
+
+func TestClient_AttemptPhase(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	client, _ := NewClient(url, testSecret, WithMaxRetries(1))
+	resp := client.Send(context.Background(), "order.created", nil)
+
+	if len(resp.Attempts) != 1 {
+		t.Fatalf("Expected 1 attempt record, got %d", len(resp.Attempts))
+	}
+	if a := resp.Attempts[0]; a.Phase != "connect" || !errors.Is(a.Error, ErrNetwork) {
+		t.Errorf("Expected network error in connect phase, got %+v", a)
+	}
+}
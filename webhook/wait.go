@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// settledHistory bounds how many finished deliveries WaitForDelivery remembers
+const settledHistory = 1024
+
+// Terminal reports whether the client will not attempt the delivery again:
+// it was sent, failed for good, or was handed to the overflow handler
+func (s DeliveryStage) Terminal() bool {
+	return s == StageSent || s == StageFailed || s == StageDeferred
+}
+
+// deliveryWaiters wakes WaitForDelivery callers and remembers recent outcomes,
+// so waiting on a delivery that has just finished does not block
+type deliveryWaiters struct {
+	mu      sync.Mutex
+	waiting map[string][]chan DeliveryEvent
+	settled map[string]DeliveryEvent
+	order   []string // Settled IDs, oldest first
+}
+
+func (w *deliveryWaiters) settle(e DeliveryEvent) {
+	if !e.Stage.Terminal() {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.settled == nil {
+		w.settled = make(map[string]DeliveryEvent)
+	}
+	if _, ok := w.settled[e.MessageID]; !ok {
+		w.order = append(w.order, e.MessageID)
+	}
+	w.settled[e.MessageID] = e
+	if len(w.order) > settledHistory {
+		delete(w.settled, w.order[0])
+		w.order = w.order[1:]
+	}
+
+	for _, ch := range w.waiting[e.MessageID] {
+		ch <- e
+	}
+	delete(w.waiting, e.MessageID)
+}
+
+// wait returns a channel receiving msgID's outcome and a func to stop waiting
+func (w *deliveryWaiters) wait(msgID string) (<-chan DeliveryEvent, func()) {
+	ch := make(chan DeliveryEvent, 1)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if e, ok := w.settled[msgID]; ok {
+		ch <- e
+		return ch, func() {}
+	}
+	if w.waiting == nil {
+		w.waiting = make(map[string][]chan DeliveryEvent)
+	}
+	w.waiting[msgID] = append(w.waiting[msgID], ch)
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		chans := w.waiting[msgID]
+		for i, c := range chans {
+			if c == ch {
+				w.waiting[msgID] = append(chans[:i], chans[i+1:]...)
+				break
+			}
+		}
+		if len(w.waiting[msgID]) == 0 {
+			delete(w.waiting, msgID)
+		}
+	}
+}
+
+// WaitForDelivery blocks until the delivery with messageID reaches a terminal
+// stage and returns that lifecycle event: StageSent, StageFailed (there is no
+// dead-letter queue), or StageDeferred when its deadline passed and the rest
+// went to the overflow handler. It suits parked and background sends; the last
+// 1024 outcomes are remembered, so a delivery that already finished returns at
+// once. An unknown ID waits until ctx is done.
+func (c *Client) WaitForDelivery(ctx context.Context, messageID string) (DeliveryEvent, error) {
+	ch, stop := c.waiters.wait(messageID)
+	defer stop()
+
+	select {
+	case e := <-ch:
+		return e, nil
+	case <-ctx.Done():
+		return DeliveryEvent{}, ctx.Err()
+	}
+}
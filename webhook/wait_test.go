@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForDelivery(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	client, _ := NewClient(server.URL, testSecret)
+
+	client.Pause()
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+	if !resp.Parked {
+		t.Fatalf("Expected send to be parked")
+	}
+
+	done := make(chan DeliveryEvent, 1)
+	go func() {
+		e, err := client.WaitForDelivery(context.Background(), resp.MessageID)
+		if err != nil {
+			t.Errorf("Expected outcome, got %v", err)
+		}
+		done <- e
+	}()
+	time.Sleep(10 * time.Millisecond)
+	client.Resume()
+
+	select {
+	case e := <-done:
+		if e.Stage != StageSent || e.MessageID != resp.MessageID || e.StatusCode != http.StatusOK {
+			t.Errorf("Expected sent outcome for %s, got %+v", resp.MessageID, e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected WaitForDelivery to return after Resume")
+	}
+}
+
+func TestClient_WaitForDelivery_AlreadySettled(t *testing.T) {
+	server, _ := countingServer(t, http.StatusBadRequest)
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(0))
+
+	resp := client.Send(context.Background(), "order.created", map[string]any{})
+	e, err := client.WaitForDelivery(context.Background(), resp.MessageID)
+	if err != nil || e.Stage != StageFailed || e.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected remembered failure, got %+v %v", e, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitForDelivery(ctx, "msg_unknown"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline error for unknown ID, got %v", err)
+	}
+	if len(client.waiters.waiting) != 0 {
+		t.Errorf("Expected abandoned waiters to be removed, got %d", len(client.waiters.waiting))
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+)
+
+// WarmUp resolves every host the client sends to (the target URL, pooled
+// Targets, the canary and the shadow URL) and opens a pooled connection to
+// each, including the TLS handshake, ahead of the first delivery. It sends an
+// unsigned HEAD request per distinct scheme and host; any HTTP response,
+// whatever its status, counts as success. Failures are joined.
+func (c *Client) WarmUp(ctx context.Context) error {
+	var errs []error
+	for _, target := range c.warmTargets() {
+		if err := c.warm(ctx, target); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warmTargets returns one URL per distinct scheme and host the client can send to
+func (c *Client) warmTargets() []string {
+	urls := append([]string{c.config.TargetURL}, c.config.Targets...)
+	urls = append(urls, c.config.CanaryURL, c.config.ShadowURL)
+
+	seen := make(map[string]bool)
+	var targets []string
+	for _, raw := range urls {
+		if raw == "" {
+			continue
+		}
+		key := raw
+		if u, err := url.Parse(raw); err == nil {
+			key = u.Scheme + "://" + u.Host
+		}
+		if !seen[key] {
+			seen[key] = true
+			targets = append(targets, raw)
+		}
+	}
+	return targets
+}
+
+func (c *Client) warm(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+	}
+	c.setIdentity(req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+	}
+	// Drain so the connection returns to the idle pool
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
+
+// KeepWarm calls WarmUp every interval until ctx is done, so idle pooled
+// connections are not closed between sparse deliveries
+func (c *Client) KeepWarm(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.WarmUp(ctx); err != nil {
+				c.logger.Warn("webhook: keep-warm ping failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WarmUp_ReusesConnection(t *testing.T) {
+	var conns int32
+	var heads int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			atomic.AddInt32(&heads, 1)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret)
+
+	if err := client.WarmUp(context.Background()); err != nil {
+		t.Fatalf("WarmUp failed: %v", err)
+	}
+	if resp := client.Send(context.Background(), "test.event", nil); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	if atomic.LoadInt32(&heads) != 1 {
+		t.Errorf("Expected 1 warm-up request, got %d", heads)
+	}
+	if atomic.LoadInt32(&conns) != 1 {
+		t.Errorf("Expected the delivery to reuse the warmed connection, got %d connections", conns)
+	}
+}
+
+func TestClient_WarmUp_EveryHost(t *testing.T) {
+	var primary, pooled, canary int32
+	count := func(n *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				atomic.AddInt32(n, 1)
+			}
+		}))
+	}
+	a, b, cn := count(&primary), count(&pooled), count(&canary)
+	defer a.Close()
+	defer b.Close()
+	defer cn.Close()
+
+	client, _ := NewClient(a.URL+"/hook", testSecret,
+		WithTargets(Failover, b.URL, a.URL+"/other"),
+		WithTrafficSplit(cn.URL, 10),
+	)
+
+	if err := client.WarmUp(context.Background()); err != nil {
+		t.Fatalf("WarmUp failed: %v", err)
+	}
+	if primary != 1 || pooled != 1 || canary != 1 {
+		t.Errorf("Expected one warm-up per distinct host, got primary=%d pooled=%d canary=%d", primary, pooled, canary)
+	}
+}
+
+func TestClient_WarmUp_Unreachable(t *testing.T) {
+	client, _ := NewClient("http://127.0.0.1:1/webhook", testSecret)
+
+	err := client.WarmUp(context.Background())
+	if !errors.Is(err, ErrNetwork) {
+		t.Errorf("Expected ErrNetwork, got %v", err)
+	}
+}
+
+func TestClient_KeepWarm(t *testing.T) {
+	var heads int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&heads, 1)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	client.KeepWarm(ctx, 10*time.Millisecond)
+
+	if atomic.LoadInt32(&heads) < 2 {
+		t.Errorf("Expected repeated keep-warm pings, got %d", heads)
+	}
+}
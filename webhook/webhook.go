@@ -0,0 +1,707 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sabry-awad97/Hookshot/redact"
+	"github.com/sabry-awad97/Hookshot/retry"
+	"github.com/sabry-awad97/Hookshot/signing"
+
+	"github.com/google/uuid"
+)
+
+// Sentinel errors for error inspection
+var (
+	ErrClientError = errors.New("webhook: client error")
+	ErrServerError = errors.New("webhook: server error")
+	ErrNetwork     = errors.New("webhook: network error")
+)
+
+// AttemptHeader carries the 1-based delivery attempt number; it is not signed
+const AttemptHeader = "Webhook-Attempt"
+
+// OrderingKeyHeader carries the key set with WithOrderingKey
+const OrderingKeyHeader = "Webhook-Ordering-Key"
+
+// Config holds the webhook client configuration
+type Config struct {
+	TargetURL         string                 // URL to send webhooks to
+	Secret            string                 // Signing secret (whsec_... for HMAC v1, whsk_... for Ed25519 v1a)
+	MaxRetries        uint64                 // Max retry attempts (default: 3)
+	Timeout           time.Duration          // HTTP timeout (default: 10s)
+	MaxInterval       time.Duration          // Max backoff interval (default: 30s)
+	Logger            *slog.Logger           // Optional structured logger
+	HTTPClient        *http.Client           // Optional custom HTTP client
+	Determinism       Determinism            // Optional clock, ID, jitter and scheduler sources
+	NamePolicies      []NamePolicy           // Event-name policies applied before sending
+	DNSCache          *DNSCache              // Optional resolver cache for the default HTTP client
+	Headers           signing.HeaderNames    // Signature header names (default: svix-*)
+	SignedHeaders     []string               // Headers covered by an extra v1h signature, with the target URL
+	Transforms        []BodyTransform        // Applied to the marshaled payload before signing
+	MaxBackoffHint    time.Duration          // Upper bound on receiver-requested retry delays (default: MaxInterval)
+	AdditionalSecrets []string               // Further signing secrets, e.g. a whsk_ key alongside a whsec_ secret
+	SignatureVersions []string               // Signature versions to emit (default: every configured secret, plus v1h)
+	ResumeRate        int                    // Parked deliveries flushed per second after Resume (default: 10)
+	MaxHeld           int                    // Deliveries Pause parks, and DisableDelivery holds, before failing more with ErrHoldFull (default: 10000, 0: no limit)
+	OnAttempt         func(Attempt)          // Called after every delivery attempt, e.g. to record phase timings as metrics
+	Targets           []string               // Additional target URLs pooled with TargetURL
+	TargetStrategy    TargetStrategy         // Selection among TargetURL and Targets (default: Failover)
+	TargetCooldown    time.Duration          // How long a failing target is excluded (default: 30s)
+	UserAgent         string                 // User-Agent for every request (default: Go-http-client/1.1)
+	Sender            SenderIdentity         // Sent as Webhook-Sender when set
+	QueryTokenTTL     time.Duration          // When set, send a query-parameter JWT valid this long instead of signature headers
+	DeadlineOverflow  func(*Deferred)        // Receives deliveries whose retries did not fit the context deadline
+	ShadowURL         string                 // Receives a fire-and-forget copy of every delivery
+	OnShadow          func(ShadowResult)     // Called with the outcome of each shadow delivery
+	CanaryURL         string                 // Receives CanaryPercent of deliveries, assigned by ordering key
+	CanaryPercent     int                    // Share of deliveries routed to CanaryURL (0-100)
+	LegacyHeaders     signing.HeaderNames    // Also emitted until LegacyUntil, for header naming migrations
+	LegacyUntil       time.Time              // End of dual header emission (zero: indefinitely)
+	ContentDigest     bool                   // Send Webhook-Content-SHA256 over the body
+	EncodingGuard     bool                   // Warn when a body carries an EncodingRisk
+	PayloadMetrics    bool                   // Record PayloadStats per event type
+	RedirectPolicy    *RedirectPolicy        // How 3xx responses are handled (default: RedirectNone)
+	DedupWindow       time.Duration          // Suppress repeats of the same event and data within this window
+	Enrichers         []EnrichStage          // Run in order on every payload before it is marshaled
+	SecretEncoding    signing.SecretEncoding // How HMAC secrets encode their key (default: base64)
+	EgressAccounting  bool                   // Record EgressUsage per day, tenant and endpoint
+	HedgePercentile   float64                // Hedge attempts slower than this percentile of recent successes (0: off)
+	HedgeMinDelay     time.Duration          // Hedge threshold until enough latencies are known, and its floor
+	SuccessStatuses   []int                  // Statuses that count as delivered (default: 200-299)
+	OnEvidence        func(Evidence)         // Receives a sealed snapshot of every delivery attempt
+}
+
+// Client is a reusable webhook sender
+type Client struct {
+	config        Config
+	signers       []signing.Signer // Primary secret first, then AdditionalSecrets
+	versions      atomic.Pointer[[]string]
+	headerKey     []byte // HMAC key for v1h signatures
+	tokenKey      []byte // HMAC key for query tokens, replacing signature headers
+	http          *http.Client
+	dialGuard     bool // http's dials enforce requirePublic
+	logger        *slog.Logger
+	det           Determinism
+	pause         pauseState
+	kill          killSwitch
+	targets       *targetPool
+	canaryPercent atomic.Int32
+	migration     migrationCounters
+	observers     deliveryObservers
+	payloads      payloadMetrics
+	egress        egressLedger
+	latency       latencyWindow // Successful latencies, for the hedge threshold
+	evidence      evidenceChain
+	dedup         *dedupWindow // Nil unless DedupWindow is set
+	waiters       deliveryWaiters
+	states        stateMachine
+}
+
+// Payload represents a generic webhook payload
+type Payload struct {
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+
+	CorrelationID string `json:"correlation_id,omitempty"` // Shared by every event in a chain
+	CausationID   string `json:"causation_id,omitempty"`   // Message ID of the event that caused this one
+	Sequence      uint64 `json:"sequence,omitempty"`       // Position among events sharing an ordering key, from 1
+}
+
+// Response contains the result of a webhook send
+type Response struct {
+	Success    bool
+	StatusCode int
+	MessageID  string
+	Error      error
+	Parked     bool         // Held by Pause; delivered after Resume
+	Attempts   []Attempt    // One record per delivery attempt, in order
+	Deferred   bool         // Remaining attempts were handed to the deadline overflow handler
+	BodySize   int          // Signed body bytes
+	Duplicate  bool         // Suppressed by the dedup window; MessageID is the original send's
+	State      MessageState // Lifecycle state when the send returned; empty if it was never accepted
+}
+
+// Option is a functional option for configuring the Client
+type Option func(*Config)
+
+// WithMaxRetries sets the maximum number of retry attempts
+func WithMaxRetries(n uint64) Option {
+	return func(c *Config) {
+		c.MaxRetries = n
+	}
+}
+
+// WithTimeout sets the HTTP request timeout
+func WithTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = d
+	}
+}
+
+// WithMaxInterval sets the maximum backoff interval
+func WithMaxInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.MaxInterval = d
+	}
+}
+
+// WithLogger sets a custom structured logger
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// WithStandardWebhooks emits webhook-id, webhook-timestamp and webhook-signature
+// headers per the Standard Webhooks specification instead of svix-prefixed ones
+func WithStandardWebhooks() Option {
+	return func(c *Config) {
+		c.Headers = signing.StandardHeaders
+	}
+}
+
+// WithHeaderNames emits the message ID, timestamp and signature under custom
+// header names, e.g. for receivers expecting X-Signature. The signed content
+// is unchanged, so only the receiver's header lookup needs to match.
+func WithHeaderNames(names signing.HeaderNames) Option {
+	return func(c *Config) {
+		c.Headers = names
+	}
+}
+
+// WithContentDigest sends the body's SHA-256 in Webhook-Content-SHA256. The
+// digest is covered by v1 through the body, and listed among the v1h signed
+// headers when WithSignedHeaders is also set, so receivers can report a body
+// rewritten by a proxy separately from a bad signature.
+func WithContentDigest() Option {
+	return func(c *Config) {
+		c.ContentDigest = true
+	}
+}
+
+// WithSignedHeaders adds a v1h signature covering the target URL and the named
+// headers (default: Content-Type and Idempotency-Key) alongside the regular one,
+// so receivers can reject bodies replayed to other endpoints or with altered
+// metadata. It requires an HMAC (whsec_) secret.
+func WithSignedHeaders(names ...string) Option {
+	return func(c *Config) {
+		if len(names) == 0 {
+			names = []string{"Content-Type", "Idempotency-Key"}
+		}
+		c.SignedHeaders = names
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client for connection pooling
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// SendOption configures a single send
+type SendOption func(*sendOptions)
+
+type sendOptions struct {
+	idempotencyKey string
+	orderingKey    string
+	assigned       func(msgID string) // Called once the send is past validation and dedup
+}
+
+// WithIdempotencyKey derives a stable message ID from key, so repeated sends
+// of the same logical event carry the same svix-id and can be deduplicated
+func WithIdempotencyKey(key string) SendOption {
+	return func(o *sendOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithOrderingKey tags the send with a key receivers can use to order related events
+func WithOrderingKey(key string) SendOption {
+	return func(o *sendOptions) {
+		o.orderingKey = key
+	}
+}
+
+// NewClient creates a new webhook client using functional options
+func NewClient(targetURL, secret string, opts ...Option) (*Client, error) {
+	if targetURL == "" {
+		return nil, fmt.Errorf("webhook: targetURL is required")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("webhook: secret is required")
+	}
+
+	cfg := NewConfig(targetURL, secret, opts...)
+	if err := normalizeSecrets(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Headers.Validate(); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	if err := validLegacyHeaders(cfg); err != nil {
+		return nil, err
+	}
+
+	signers, err := newSigners(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var headerKey []byte
+	tokenKey, err := newTokenKey(cfg, signers[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ContentDigest && len(cfg.SignedHeaders) > 0 && !slices.ContainsFunc(cfg.SignedHeaders, func(h string) bool {
+		return strings.EqualFold(h, signing.ContentDigestHeader)
+	}) {
+		cfg.SignedHeaders = append(slices.Clone(cfg.SignedHeaders), signing.ContentDigestHeader)
+	}
+	if len(cfg.SignedHeaders) > 0 {
+		if signers[0].Version() != signing.SchemeV1 {
+			return nil, fmt.Errorf("webhook: signed headers require an HMAC (whsec_) secret")
+		}
+		if headerKey, err = signing.DecodeSecret(cfg.Secret); err != nil {
+			return nil, fmt.Errorf("webhook: failed to create signer: %w", err)
+		}
+	}
+
+	versions, err := enabledVersions(signers, cfg.SignatureVersions, headerKey != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := newTargetPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := validPercent(cfg.CanaryPercent); err != nil {
+		return nil, err
+	}
+
+	logger := redact.Logger(cfg.Logger)
+
+	httpClient := cfg.HTTPClient
+	redirects := cfg.RedirectPolicy
+	dialGuard := false
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = guardedDialer().DialContext
+		if cfg.DNSCache != nil {
+			transport = transportWithDNSCache(cfg.DNSCache)
+		}
+		httpClient = &http.Client{Timeout: cfg.Timeout, Transport: transport}
+		dialGuard = true
+		if redirects == nil {
+			redirects = &RedirectNone
+		}
+	} else if redirects != nil {
+		clone := *httpClient
+		httpClient = &clone
+		if redirects.mode != redirectNone && !redirects.AllowPrivate {
+			httpClient.Transport, dialGuard = guardTransport(httpClient.Transport)
+		}
+	}
+
+	c := &Client{
+		config:    cfg,
+		signers:   signers,
+		headerKey: headerKey,
+		http:      httpClient,
+		logger:    logger,
+		det:       cfg.Determinism.withDefaults(),
+		targets:   targets,
+		dedup:     newDedupWindow(cfg.DedupWindow),
+		tokenKey:  tokenKey,
+		dialGuard: dialGuard,
+	}
+	if redirects != nil {
+		httpClient.CheckRedirect = c.checkRedirect(*redirects)
+	}
+	if cfg.OnEvidence != nil {
+		clone := *httpClient
+		base := clone.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		clone.Transport = &evidenceTransport{c: c, base: base}
+		c.http = &clone
+	}
+	c.versions.Store(&versions)
+	c.canaryPercent.Store(int32(cfg.CanaryPercent))
+	return c, nil
+}
+
+// Send dispatches a webhook with the given event and data
+func (c *Client) Send(ctx context.Context, event string, data any, opts ...SendOption) Response {
+	payload := Payload{
+		Event:     event,
+		Timestamp: c.det.Now(),
+		Data:      data,
+	}
+	return c.SendPayload(ctx, payload, opts...)
+}
+
+// SendPayload dispatches a custom payload.
+// Note: The signing timestamp is generated at send time and may differ from payload.Timestamp.
+func (c *Client) SendPayload(ctx context.Context, payload Payload, opts ...SendOption) Response {
+	var so sendOptions
+	for _, opt := range opts {
+		opt(&so)
+	}
+
+	payload, jsonData, err := c.encode(ctx, payload, nil)
+	if err != nil {
+		return Response{Error: err}
+	}
+
+	msgID := c.det.NewID()
+	if so.idempotencyKey != "" {
+		msgID = idempotentMessageID(so.idempotencyKey)
+	}
+
+	var dedupHash [sha256.Size]byte
+	if c.dedup != nil {
+		if dedupHash, err = dedupKey(payload); err != nil {
+			return Response{Error: fmt.Errorf("webhook: failed to marshal payload: %w", err)}
+		}
+		if original, dup := c.dedup.claim(dedupHash, msgID, c.det.Now()); dup {
+			c.logger.Info("webhook: duplicate payload suppressed", "event", payload.Event, "original", original)
+			return Response{Duplicate: true, MessageID: original}
+		}
+	}
+
+	if so.assigned != nil {
+		so.assigned(msgID)
+	}
+	c.transition(delivery{msgID: msgID, event: payload.Event}, StateAccepted, 0)
+
+	d := delivery{
+		body:   jsonData,
+		msgID:  msgID,
+		event:  payload.Event,
+		tenant: TenantFromContext(ctx),
+		header: make(http.Header),
+	}
+	resp := c.dispatch(ctx, d, so)
+	if c.dedup != nil && !resp.Success && !resp.Deferred && !resp.Parked {
+		c.dedup.release(dedupHash, msgID)
+	}
+	return resp
+}
+
+// encode runs a payload through the pipeline shared by every send entry
+// point: name policies, correlation, enrichment, marshaling, body transforms,
+// payload metrics and the encoding guard. doc wraps the payload for marshaling,
+// e.g. as a BatchItem; nil marshals it as is.
+func (c *Client) encode(ctx context.Context, payload Payload, doc func(Payload) any) (Payload, []byte, error) {
+	if err := c.checkName(payload.Event); err != nil {
+		return payload, nil, err
+	}
+	correlate(ctx, &payload)
+	if len(c.config.Enrichers) > 0 {
+		var err error
+		if payload, err = c.enrich(ctx, payload); err != nil {
+			return payload, nil, err
+		}
+	}
+
+	var v any = payload
+	if doc != nil {
+		v = doc(payload)
+	}
+	started := time.Now()
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return payload, nil, fmt.Errorf("webhook: failed to marshal payload: %w", err)
+	}
+	for _, transform := range c.config.Transforms {
+		if jsonData, err = transform(jsonData); err != nil {
+			return payload, nil, fmt.Errorf("webhook: failed to transform payload: %w", err)
+		}
+	}
+	if c.config.PayloadMetrics {
+		c.payloads.record(payload.Event, jsonData, time.Since(started))
+	}
+	if c.config.EncodingGuard {
+		if risks := EncodingRisks(jsonData); len(risks) > 0 {
+			c.logger.Warn("webhook: payload bytes are likely to be re-encoded in transit", "event", payload.Event, "risks", risks)
+		}
+	}
+	return payload, jsonData, nil
+}
+
+// dispatch routes an accepted delivery and sends it, unless Pause or
+// DisableDelivery holds it
+func (c *Client) dispatch(ctx context.Context, d delivery, so sendOptions) Response {
+	if so.idempotencyKey != "" {
+		d.header.Set("Idempotency-Key", so.idempotencyKey)
+	}
+	if so.orderingKey != "" {
+		d.header.Set(OrderingKeyHeader, so.orderingKey)
+	}
+	splitKey := so.orderingKey
+	if splitKey == "" {
+		splitKey = d.msgID
+	}
+	d.target, d.pinned = c.splitTarget(splitKey)
+
+	if resp, ok := c.park(d); ok {
+		return resp
+	}
+	if c.halted(d) {
+		return c.hold(d, nil)
+	}
+	c.seal(&d)
+	c.mirror(d)
+	return c.sendWithRetry(ctx, d)
+}
+
+// seal stamps the delivery with the current time and signs it
+func (c *Client) seal(d *delivery) {
+	c.sealWith(d, *c.versions.Load())
+}
+
+// sealWith stamps and signs the delivery with the given versions only
+func (c *Client) sealWith(d *delivery, versions []string) {
+	d.timestamp = c.det.Now()
+	d.signature = c.sign(d.msgID, d.timestamp, d.body, versions)
+	if c.headerKey != nil && slices.Contains(versions, signing.SchemeV1H) {
+		d.header.Set("Content-Type", "application/json")
+		d.header.Set(signing.SignedHeadersHeader, strings.ToLower(strings.Join(c.config.SignedHeaders, ",")))
+		if c.config.ContentDigest {
+			d.header.Set(signing.ContentDigestHeader, signing.ContentDigest(d.body))
+		}
+		target := d.target
+		if target == "" {
+			target = c.config.TargetURL
+		}
+		d.signature = strings.TrimSpace(d.signature + " " + signing.SignV1H(c.headerKey, d.msgID, d.timestamp, target, d.header, c.config.SignedHeaders, d.body))
+	}
+}
+
+// delivery is a signed message ready to be sent
+type delivery struct {
+	body      []byte
+	msgID     string
+	timestamp time.Time
+	signature string
+	header    http.Header // Extra per-send headers
+	target    string      // Target URL (default: Config.TargetURL)
+	attempt   int         // Attempts already made, for resumed deliveries
+	remaining uint64      // Attempts left when resumed (default: MaxRetries)
+	pinned    bool        // target was fixed by the traffic split
+	event     string      // Payload event name, for lifecycle events
+	tenant    string      // From ContextWithTenant, for egress accounting
+	batch     *batchState // Items of a SendBatch delivery
+}
+
+// newRequest builds one signed delivery attempt
+func (c *Client) newRequest(ctx context.Context, d delivery, attempt int) (*http.Request, error) {
+	target := d.target
+	if target == "" {
+		target = c.config.TargetURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewReader(d.body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, vs := range d.header {
+		req.Header[k] = vs
+	}
+	c.setIdentity(req.Header)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(AttemptHeader, strconv.Itoa(attempt))
+	if c.config.ContentDigest {
+		req.Header.Set(signing.ContentDigestHeader, signing.ContentDigest(d.body))
+	}
+	if c.tokenKey != nil {
+		c.setToken(req, d)
+		return req, nil
+	}
+	setSignatureHeaders(req.Header, c.config.Headers, d)
+	if c.dualHeaders() {
+		setSignatureHeaders(req.Header, c.config.LegacyHeaders, d)
+	}
+	return req, nil
+}
+
+// idempotentMessageID maps an idempotency key to a stable msg_-prefixed UUID
+func idempotentMessageID(key string) string {
+	return fmt.Sprintf("msg_%s", uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String())
+}
+
+func (c *Client) sendWithRetry(ctx context.Context, d delivery) Response {
+	var lastErr error
+	var lastStatusCode int
+	var attempts []Attempt
+
+	scheduled := max(c.config.MaxRetries, 1)
+	if d.remaining > 0 {
+		scheduled = d.remaining
+	}
+	policy := retry.Policy{
+		MaxAttempts:     scheduled,
+		InitialInterval: 1 * time.Second,
+		MaxInterval:     c.config.MaxInterval,
+		MaxDelay:        c.config.MaxBackoffHint,
+		Jitter:          c.det.Jitter,
+		Scheduler:       c.det.Scheduler,
+	}
+	cut := c.budgetForDeadline(ctx, &policy, d.msgID)
+	policy.OnRetry = func(_ error, delay time.Duration) {
+		if d.batch != nil && errors.Is(lastErr, ErrPartialBatch) {
+			c.nextRound(&d, attempts)
+			return
+		}
+		c.emitRetry(d, attempts, delay)
+	}
+
+	operation := func(ctx context.Context, attempt int) (err error) {
+		attempt += d.attempt
+		var status int
+		var hedge hedgeOutcome
+		tr := &attemptTrace{start: time.Now()}
+		if !d.pinned {
+			d.target = c.targets.pick(c.det.Now())
+		}
+		if c.disabled(d.target) {
+			lastErr = ErrDeliveryDisabled
+			return retry.MarkPermanent(lastErr)
+		}
+		c.transition(d, StateAttempting, attempt)
+		defer func() {
+			var attemptErr error
+			if err != nil {
+				attemptErr = lastErr // err may be wrapped with retry markers
+			}
+			// 4xx means the receiver is up and rejected the message
+			c.targets.report(d.target, status > 0 && status < 500, c.det.Now())
+			a := c.recordAttempt(attempt, d.target, c.signatureVersion(d), status, attemptErr, hedge, tr)
+			attempts = append(attempts, a)
+			c.accountEgress(d, a)
+			if a.Hedged {
+				c.accountEgress(d, Attempt{Target: a.Target})
+			}
+		}()
+
+		ctx = c.withEvidence(ctx, d, attempt)
+		req, err := c.newRequest(httptrace.WithClientTrace(ctx, tr.clientTrace()), d, attempt)
+		if err != nil {
+			lastErr = fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+			return lastErr
+		}
+
+		resp, hedge, err := c.do(ctx, req, d, attempt)
+		if errors.Is(err, ErrRedirect) {
+			lastErr = err
+			return retry.MarkPermanent(lastErr)
+		}
+		if err != nil {
+			lastErr = fmt.Errorf("%w: %s", ErrNetwork, redact.Error(err))
+			c.logger.Warn("webhook: network error", "error", err, "phase", tr.phase(), "target", d.target)
+			return lastErr
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		lastStatusCode = resp.StatusCode
+		status = resp.StatusCode
+
+		delivered := c.delivered(resp.StatusCode)
+
+		// 4xx - permanent failure, don't retry
+		if !delivered && resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			lastErr = fmt.Errorf("%w: status %d: %s", ErrClientError, resp.StatusCode, redact.String(string(body)))
+			return retry.MarkPermanent(lastErr)
+		}
+
+		// 3xx - left unfollowed by the redirect policy
+		if !delivered && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+			lastErr = redirectError(resp)
+			if c.retryRedirects() {
+				return lastErr
+			}
+			return retry.MarkPermanent(lastErr)
+		}
+
+		// 5xx - retryable
+		if !delivered && resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%w: status %d: %s", ErrServerError, resp.StatusCode, redact.String(string(body)))
+			c.logger.Warn("webhook: server error", "status", resp.StatusCode, "target", d.target)
+			if hint, ok := backoffHint(resp.Header, c.det.Now()); ok {
+				return retry.WithDelay(lastErr, hint)
+			}
+			return lastErr
+		}
+
+		// 2xx outside SuccessStatuses
+		if !delivered {
+			lastErr = fmt.Errorf("%w: status %d", ErrUnexpectedStatus, resp.StatusCode)
+			return retry.MarkPermanent(lastErr)
+		}
+
+		// 2xx with per-item results - retry only the items that failed
+		if d.batch != nil {
+			if more, err := d.batch.settle(resp.StatusCode, body); err != nil {
+				lastErr = err
+				if !more {
+					return retry.MarkPermanent(lastErr)
+				}
+				if hint, ok := backoffHint(resp.Header, c.det.Now()); ok {
+					return retry.WithDelay(lastErr, hint)
+				}
+				return lastErr
+			}
+		}
+
+		return nil
+	}
+
+	if err := retry.Do(ctx, policy, operation); err != nil {
+		if errors.Is(lastErr, ErrDeliveryDisabled) {
+			d.attempt += len(attempts)
+			d.remaining = scheduled - uint64(len(attempts))
+			return c.hold(d, attempts)
+		}
+		resp := Response{Error: lastErr, StatusCode: lastStatusCode, MessageID: d.msgID, Attempts: attempts, BodySize: len(d.body)}
+		if left := scheduled - uint64(len(attempts)); left > 0 && !retry.IsPermanent(err) {
+			resp.Deferred = c.overflow(ctx, d, cut, len(attempts), left)
+		}
+		stage := StageFailed
+		resp.State = StateFailed
+		if resp.Deferred {
+			stage, resp.State = StageDeferred, StateExpired
+		}
+		c.emitOutcome(stage, d, attempts)
+		return resp
+	}
+	c.emitOutcome(StageSent, d, attempts)
+
+	return Response{
+		Success:    true,
+		State:      StateDelivered,
+		StatusCode: lastStatusCode,
+		MessageID:  d.msgID,
+		Attempts:   attempts,
+		BodySize:   len(d.body),
+	}
+}
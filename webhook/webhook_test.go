@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/sabry-awad97/Hookshot/signing"
 )
 
 const testSecret = "whsec_C2FtcGxlX3NlY3JldF9rZXlfZm9yX3Rlc3Rpbmc="
@@ -51,6 +55,86 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_HeaderNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   signing.HeaderNames
+		wantErr bool
+	}{
+		{name: "custom", names: signing.HeaderNames{ID: "X-Request-Id", Timestamp: "X-Request-Timestamp", Signature: "X-Signature"}},
+		{name: "missing signature", names: signing.HeaderNames{ID: "X-Request-Id", Timestamp: "X-Request-Timestamp"}, wantErr: true},
+		{name: "duplicate", names: signing.HeaderNames{ID: "X-Sig", Timestamp: "X-Ts", Signature: "x-sig"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("http://localhost:4000/webhook", testSecret, WithHeaderNames(tt.names))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_SignedHeaders(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithSignedHeaders())
+	resp := client.Send(context.Background(), "order.created", map[string]any{}, WithIdempotencyKey("order-1"))
+	if !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+
+	if got := header.Get(signing.SignedHeadersHeader); got != "content-type,idempotency-key" {
+		t.Errorf("Expected signed header list, got %q", got)
+	}
+	sigs, _ := signing.ParseSignatures(header.Get("svix-signature"))
+	if len(sigs) != 2 || sigs[0].Version != signing.SchemeV1 || sigs[1].Version != signing.SchemeV1H {
+		t.Errorf("Expected v1 and v1h signatures, got %v", sigs)
+	}
+
+	key, _ := signing.DecodeSecret(testSecret)
+	ts, _ := strconv.ParseInt(header.Get("svix-timestamp"), 10, 64)
+	err := signing.VerifyV1H(key, header.Get("svix-id"), time.Unix(ts, 0), server.URL, header, body, header.Get("svix-signature"), nil)
+	if err != nil {
+		t.Errorf("Expected v1h signature to verify, got %v", err)
+	}
+
+	secretKey, _, _ := signing.GenerateKeyPair()
+	if _, err := NewClient(server.URL, secretKey, WithSignedHeaders()); err == nil {
+		t.Error("Expected error for Ed25519 key with signed headers")
+	}
+}
+
+func TestClient_ContentDigest(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithContentDigest(), WithSignedHeaders())
+	if resp := client.Send(context.Background(), "order.created", map[string]any{}); !resp.Success {
+		t.Fatalf("Expected success, got error: %v", resp.Error)
+	}
+	if got := header.Get(signing.ContentDigestHeader); got != signing.ContentDigest(body) {
+		t.Errorf("Expected digest %s, got %s", signing.ContentDigest(body), got)
+	}
+	if got := header.Get(signing.SignedHeadersHeader); got != "content-type,idempotency-key,webhook-content-sha256" {
+		t.Errorf("Expected the digest among the signed headers, got %q", got)
+	}
+}
+
 func TestClient_Send(t *testing.T) {
 	var receivedPayload Payload
 	var receivedHeaders http.Header
@@ -205,6 +289,31 @@ func TestClient_NoRetry_ClientError(t *testing.T) {
 	}
 }
 
+func TestClient_RedactsEchoedSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad signature " + r.Header.Get("Svix-Signature") + " for " + testSecret))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret, WithMaxRetries(1))
+	resp := client.Send(context.Background(), "test.client_error", nil)
+
+	if resp.Error == nil {
+		t.Fatal("Expected an error")
+	}
+	msg := resp.Error.Error()
+	if strings.Contains(msg, testSecret) || strings.Contains(msg, "C2FtcGxl") {
+		t.Errorf("Expected the secret redacted, got %q", msg)
+	}
+	if !strings.Contains(msg, "v1,[REDACTED]") {
+		t.Errorf("Expected the signature redacted, got %q", msg)
+	}
+	if len(resp.Attempts) != 1 || resp.Attempts[0].Error.Error() != msg {
+		t.Errorf("Expected the attempt record to carry the redacted error, got %v", resp.Attempts)
+	}
+}
+
 func TestClient_MaxRetriesExceeded(t *testing.T) {
 	var attempts int32
 
@@ -383,3 +492,35 @@ func TestFunctionalOptions(t *testing.T) {
 		t.Errorf("Expected MaxInterval 60s, got %v", client.config.MaxInterval)
 	}
 }
+
+func TestClient_SendOptions(t *testing.T) {
+	var headers []http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, testSecret)
+
+	ctx := context.Background()
+	first := client.Send(ctx, "order.created", nil, WithIdempotencyKey("order-1"), WithOrderingKey("cust-42"))
+	second := client.Send(ctx, "order.created", nil, WithIdempotencyKey("order-1"))
+
+	if first.MessageID != second.MessageID {
+		t.Errorf("Expected same message ID for same idempotency key, got '%s' and '%s'", first.MessageID, second.MessageID)
+	}
+	if len(first.MessageID) != 40 || !strings.HasPrefix(first.MessageID, "msg_") {
+		t.Errorf("Expected msg_ + UUID message ID, got '%s'", first.MessageID)
+	}
+	if headers[0].Get("Idempotency-Key") != "order-1" {
+		t.Errorf("Expected Idempotency-Key 'order-1', got '%s'", headers[0].Get("Idempotency-Key"))
+	}
+	if headers[0].Get("Webhook-Ordering-Key") != "cust-42" {
+		t.Errorf("Expected Webhook-Ordering-Key 'cust-42', got '%s'", headers[0].Get("Webhook-Ordering-Key"))
+	}
+	if headers[1].Get("Webhook-Ordering-Key") != "" {
+		t.Error("Expected no ordering key on second send")
+	}
+}